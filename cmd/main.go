@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/codepnw/blog-api/internal/server"
 )
@@ -16,7 +18,25 @@ const envPath = "dev.env"
 // @in header
 // @name Authorization
 func main() {
-	if err := server.Run(envPath); err != nil {
+	var err error
+	switch cmd, args := command(); cmd {
+	case "", "serve":
+		err = server.Run(envPath)
+	case "import":
+		err = server.Import(envPath, args)
+	case "export":
+		err = server.Export(envPath, args)
+	default:
+		err = fmt.Errorf("unknown command %q, expected serve, import or export", cmd)
+	}
+	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+func command() (string, []string) {
+	if len(os.Args) < 2 {
+		return "", nil
+	}
+	return os.Args[1], os.Args[2:]
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/codepnw/blog-api/internal/utils/validate"
@@ -9,9 +13,19 @@ import (
 )
 
 type EnvConfig struct {
-	APP APPConfig `envPrefix:"APP_"`
-	DB  DBConfig  `envPrefix:"DB_"`
-	JWT JWTConfig `envPrefix:"JWT_"`
+	APP   APPConfig   `envPrefix:"APP_"`
+	DB    DBConfig    `envPrefix:"DB_"`
+	JWT   JWTConfig   `envPrefix:"JWT_"`
+	Job   JobConfig   `envPrefix:"JOB_"`
+	Cache CacheConfig `envPrefix:"CACHE_"`
+	Media MediaConfig `envPrefix:"MEDIA_"`
+
+	Analytics AnalyticsConfig `envPrefix:"ANALYTICS_"`
+	Reaction  ReactionConfig  `envPrefix:"REACTION_"`
+	Feed      FeedConfig      `envPrefix:"FEED_"`
+	Robots    RobotsConfig    `envPrefix:"ROBOTS_"`
+	Locale    LocaleConfig    `envPrefix:"LOCALE_"`
+	Preview   PreviewConfig   `envPrefix:"PREVIEW_"`
 }
 
 type APPConfig struct {
@@ -19,6 +33,12 @@ type APPConfig struct {
 	Host    string `env:"HOST" envDefault:"127.0.0.1"`
 	Port    int    `env:"PORT" envDefault:"4000"`
 	Version int    `env:"VERSION" envDefault:"1"`
+	// RequireIfMatch rejects PATCH and DELETE requests without If-Match.
+	RequireIfMatch bool `env:"REQUIRE_IF_MATCH" envDefault:"false"`
+	// SiteURL is the public address of the blog, used for links in feeds
+	// and the sitemap: posts are SiteURL/posts/<id>. When empty, links point
+	// at the API.
+	SiteURL string `env:"SITE_URL" validate:"omitempty,url"`
 }
 
 type DBConfig struct {
@@ -35,6 +55,90 @@ type JWTConfig struct {
 	RefreshKey string `env:"REFRESH_KEY" validate:"required"`
 }
 
+type JobConfig struct {
+	PublishInterval time.Duration `env:"PUBLISH_INTERVAL" envDefault:"1m"`
+	RenderInterval  time.Duration `env:"RENDER_INTERVAL" envDefault:"1m"`
+	PurgeInterval   time.Duration `env:"PURGE_INTERVAL" envDefault:"1h"`
+	RelatedInterval time.Duration `env:"RELATED_INTERVAL" envDefault:"1m"`
+	SitemapInterval time.Duration `env:"SITEMAP_INTERVAL" envDefault:"1m"`
+	TrashRetention  time.Duration `env:"TRASH_RETENTION" envDefault:"720h"`
+	// AutosaveRetention is how long an autosave is kept after its last save.
+	AutosaveRetention time.Duration `env:"AUTOSAVE_RETENTION" envDefault:"168h"`
+}
+
+// CacheConfig is how long shared caches may keep anonymous GET responses of
+// each route group.
+type CacheConfig struct {
+	PostsMaxAge      time.Duration `env:"POSTS_MAX_AGE" envDefault:"1m"`
+	CategoriesMaxAge time.Duration `env:"CATEGORIES_MAX_AGE" envDefault:"10m"`
+	CommentsMaxAge   time.Duration `env:"COMMENTS_MAX_AGE" envDefault:"30s"`
+}
+
+type MediaConfig struct {
+	// Storage is "local" or "s3".
+	Storage  string `env:"STORAGE" envDefault:"local" validate:"oneof=local s3"`
+	LocalDir string `env:"LOCAL_DIR" envDefault:"./uploads"`
+	// BaseURL is the public URL files are served from. For local storage the
+	// API serves LocalDir at this path.
+	BaseURL     string `env:"BASE_URL" envDefault:"/uploads"`
+	S3Endpoint  string `env:"S3_ENDPOINT"`
+	S3Region    string `env:"S3_REGION" envDefault:"us-east-1"`
+	S3Bucket    string `env:"S3_BUCKET"`
+	S3AccessKey string `env:"S3_ACCESS_KEY"`
+	S3SecretKey string `env:"S3_SECRET_KEY"`
+	S3UseSSL    bool   `env:"S3_USE_SSL" envDefault:"false"`
+
+	MaxSize       int64 `env:"MAX_SIZE" envDefault:"10485760"`
+	MaxPixels     int   `env:"MAX_PIXELS" envDefault:"40000000"`
+	VariantWidths []int `env:"VARIANT_WIDTHS" envDefault:"320,768,1280"`
+	// CWebP is the cwebp binary used for WebP variants. They are skipped
+	// when it can't be found.
+	CWebP string `env:"CWEBP" envDefault:"cwebp"`
+}
+
+type AnalyticsConfig struct {
+	// Secret keys the visitor hashes. When empty it is derived from the JWT
+	// secret key.
+	Secret        string        `env:"SECRET" validate:"required"`
+	BufferSize    int           `env:"BUFFER_SIZE" envDefault:"10000" validate:"min=1"`
+	BatchSize     int           `env:"BATCH_SIZE" envDefault:"500" validate:"min=1"`
+	FlushInterval time.Duration `env:"FLUSH_INTERVAL" envDefault:"5s" validate:"gt=0"`
+}
+
+type ReactionConfig struct {
+	// Types are the reactions readers can choose from.
+	Types []string `env:"TYPES" envDefault:"like,love,laugh,wow,sad,celebrate" validate:"min=1,dive,required,max=32"`
+}
+
+// FeedConfig describes the blog in its RSS, Atom and JSON feeds.
+type FeedConfig struct {
+	Title       string `env:"TITLE" envDefault:"Blog"`
+	Description string `env:"DESCRIPTION"`
+	// FullContent puts the rendered post in feed items instead of the excerpt.
+	FullContent bool `env:"FULL_CONTENT" envDefault:"false"`
+	Limit       int  `env:"LIMIT" envDefault:"20" validate:"min=1,max=100"`
+}
+
+// RobotsConfig are the rules served in robots.txt for every crawler.
+type RobotsConfig struct {
+	Allow    []string `env:"ALLOW"`
+	Disallow []string `env:"DISALLOW"`
+}
+
+// LocaleConfig lists the languages posts are written and translated in.
+type LocaleConfig struct {
+	Supported []string `env:"SUPPORTED" envDefault:"en,th" validate:"min=1,dive,required,max=10"`
+}
+
+// PreviewConfig signs the secret links to unpublished posts.
+type PreviewConfig struct {
+	// Secret signs the links. When empty it is derived from the JWT secret
+	// key.
+	Secret     string        `env:"SECRET" validate:"required"`
+	DefaultTTL time.Duration `env:"DEFAULT_TTL" envDefault:"72h"`
+	MaxTTL     time.Duration `env:"MAX_TTL" envDefault:"720h"`
+}
+
 func LoadConfig(path string) (*EnvConfig, error) {
 	if err := godotenv.Load(path); err != nil {
 		return nil, fmt.Errorf("load env failed: %w", err)
@@ -45,9 +149,27 @@ func LoadConfig(path string) (*EnvConfig, error) {
 		return nil, fmt.Errorf("parse env failed: %w", err)
 	}
 
+	// Every instance must agree on these secrets, so they are never random.
+	if cfg.JWT.SecretKey != "" {
+		if cfg.Preview.Secret == "" {
+			cfg.Preview.Secret = deriveSecret(cfg.JWT.SecretKey, "preview")
+		}
+		if cfg.Analytics.Secret == "" {
+			cfg.Analytics.Secret = deriveSecret(cfg.JWT.SecretKey, "analytics")
+		}
+	}
+
 	if err := validate.Struct(cfg); err != nil {
 		return nil, fmt.Errorf("validate env failed: %w", err)
 	}
 
 	return cfg, nil
 }
+
+// deriveSecret makes a key for purpose out of key, so that one can't be
+// worked out from another.
+func deriveSecret(key, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}
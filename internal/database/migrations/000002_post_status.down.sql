@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_posts_status_published_at;
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS post_status;
@@ -0,0 +1,10 @@
+CREATE TYPE post_status AS ENUM ('draft', 'scheduled', 'published', 'archived');
+
+ALTER TABLE posts
+    ADD COLUMN status post_status NOT NULL DEFAULT 'draft',
+    ADD COLUMN published_at TIMESTAMPTZ;
+
+-- Posts created before the lifecycle existed were already public.
+UPDATE posts SET status = 'published', published_at = created_at;
+
+CREATE INDEX IF NOT EXISTS idx_posts_status_published_at ON posts (status, published_at);
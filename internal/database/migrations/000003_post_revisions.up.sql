@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    content TEXT NOT NULL DEFAULT '',
+    editor_id UUID NOT NULL REFERENCES users(id),
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE (post_id, revision)
+);
+
+-- Seed the current text of existing posts as their first revision.
+INSERT INTO post_revisions (post_id, revision, title, content, editor_id, note, created_at)
+SELECT id, 1, title, COALESCE(content, ''), author_id, 'initial version', updated_at
+FROM posts;
@@ -0,0 +1,10 @@
+CREATE TYPE content_format AS ENUM ('markdown', 'html', 'plaintext');
+
+-- content_html stays NULL until the render job has filled it in.
+ALTER TABLE posts
+    ADD COLUMN content_format content_format NOT NULL DEFAULT 'markdown',
+    ADD COLUMN content_html TEXT,
+    ADD COLUMN content_toc JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE post_revisions
+    ADD COLUMN content_format content_format NOT NULL DEFAULT 'markdown';
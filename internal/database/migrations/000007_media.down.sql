@@ -0,0 +1,4 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS cover_media_id;
+
+DROP INDEX IF EXISTS idx_media_uploader_id;
+DROP TABLE IF EXISTS media;
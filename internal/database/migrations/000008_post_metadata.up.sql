@@ -0,0 +1,7 @@
+ALTER TABLE posts
+    ADD COLUMN excerpt TEXT NOT NULL DEFAULT '',
+    ADD COLUMN word_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN reading_time_minutes INT NOT NULL DEFAULT 0;
+
+-- Clearing the HTML cache makes the render job fill in the new columns.
+UPDATE posts SET content_html = NULL;
@@ -0,0 +1,10 @@
+-- One row per visitor, post and day. visitor_hash is keyed with a secret and
+-- the day, so it can't be turned back into an IP or linked across days.
+CREATE TABLE IF NOT EXISTS post_views (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    visitor_hash CHAR(32) NOT NULL,
+    referrer VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (post_id, day, visitor_hash)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS reacted_at,
+    DROP COLUMN IF EXISTS reaction_counts;
+
+DROP TABLE IF EXISTS post_reactions;
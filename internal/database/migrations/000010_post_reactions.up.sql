@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS post_reactions (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(32) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (post_id, user_id, reaction)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_reactions_user_id ON post_reactions(user_id);
+
+-- Counts per reaction type, kept up to date with post_reactions so lists
+-- don't have to aggregate. reacted_at moves Last-Modified, since reacting
+-- doesn't touch updated_at.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS reaction_counts JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS reacted_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS series_posts;
+DROP INDEX IF EXISTS idx_series_author_id;
+DROP TABLE IF EXISTS series;
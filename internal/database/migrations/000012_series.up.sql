@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS series (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_series_author_id ON series(author_id);
+
+-- post_id is the primary key, so a post belongs to at most one series. The
+-- position constraint is deferred so parts can be reordered in one statement.
+CREATE TABLE IF NOT EXISTS series_posts (
+    post_id UUID PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    series_id UUID NOT NULL REFERENCES series(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    CONSTRAINT series_posts_position_key UNIQUE (series_id, position) DEFERRABLE INITIALLY DEFERRED
+);
@@ -0,0 +1,16 @@
+-- Bylines in the order they are credited. posts.author_id stays the owner
+-- of the post and always has a byline.
+CREATE TABLE IF NOT EXISTS post_authors (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'author' CHECK (role IN ('author', 'contributor', 'editor')),
+    position INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_authors_user_id ON post_authors(user_id);
+
+INSERT INTO post_authors (post_id, user_id, role, position)
+SELECT id, author_id, 'author', 1 FROM posts
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS related_dirty,
+    DROP COLUMN IF EXISTS related_at;
+
+DROP INDEX IF EXISTS idx_related_posts_related_id;
+DROP TABLE IF EXISTS related_posts;
@@ -0,0 +1,19 @@
+-- pg_trgm is a trusted extension, the database owner can create it.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- The precomputed most related posts of each published post, best first.
+CREATE TABLE IF NOT EXISTS related_posts (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    related_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    score REAL NOT NULL,
+    rank INT NOT NULL,
+    PRIMARY KEY (post_id, related_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_related_posts_related_id ON related_posts(related_id);
+
+-- related_at is the updated_at the list was computed for, so an edit makes
+-- it stale. related_dirty asks for a recompute because a neighbour changed.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS related_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS related_dirty BOOLEAN NOT NULL DEFAULT FALSE;
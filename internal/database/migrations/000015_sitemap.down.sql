@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_posts_sitemap_gone;
+DROP INDEX IF EXISTS idx_posts_sitemap_stale;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS sitemap_at;
+
+DROP TABLE IF EXISTS sitemap_urls;
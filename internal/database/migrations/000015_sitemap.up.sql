@@ -0,0 +1,16 @@
+-- Every page listed in the sitemap. id keeps the order stable, so a URL
+-- stays in the same sitemap file while it exists.
+CREATE TABLE IF NOT EXISTS sitemap_urls (
+    id BIGSERIAL PRIMARY KEY,
+    path TEXT NOT NULL UNIQUE,
+    lastmod TIMESTAMPTZ NOT NULL
+);
+
+-- sitemap_at is the updated_at the post's sitemap entry was written for.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS sitemap_at TIMESTAMPTZ;
+
+-- The posts the sitemap job still has to add, update or remove.
+CREATE INDEX IF NOT EXISTS idx_posts_sitemap_stale ON posts(id)
+    WHERE status = 'published' AND deleted_at IS NULL AND (sitemap_at IS NULL OR updated_at > sitemap_at);
+CREATE INDEX IF NOT EXISTS idx_posts_sitemap_gone ON posts(id)
+    WHERE sitemap_at IS NOT NULL AND (status <> 'published' OR deleted_at IS NOT NULL);
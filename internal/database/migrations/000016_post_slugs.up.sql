@@ -0,0 +1,5 @@
+-- Slugs come from imported Markdown files and let a re-import skip the posts
+-- it already created. Posts written through the API have none.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS slug VARCHAR(200);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_slug ON posts(slug);
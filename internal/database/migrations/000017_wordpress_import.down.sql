@@ -0,0 +1,6 @@
+ALTER TABLE comments ALTER COLUMN content TYPE VARCHAR(255) USING LEFT(content, 255);
+
+DROP INDEX IF EXISTS idx_redirects_post_id;
+DROP TABLE IF EXISTS redirects;
+DROP TABLE IF EXISTS wordpress_refs;
+DROP TABLE IF EXISTS import_jobs;
@@ -0,0 +1,40 @@
+-- WordPress imports run in the background. The counts are written as the job
+-- goes, so they double as its progress.
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source VARCHAR(20) NOT NULL,
+    site TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'completed', 'failed')),
+    total INT NOT NULL DEFAULT 0,
+    processed INT NOT NULL DEFAULT 0,
+    counts JSONB NOT NULL DEFAULT '{}',
+    errors JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+-- What each WordPress post and comment became, so running an import again
+-- skips what it already created. Users, categories and tags are matched by
+-- email and name instead.
+CREATE TABLE IF NOT EXISTS wordpress_refs (
+    site TEXT NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('post', 'comment')),
+    wp_id BIGINT NOT NULL,
+    local_id TEXT NOT NULL,
+    PRIMARY KEY (site, kind, wp_id)
+);
+
+-- Old permalinks, answered with a permanent redirect to the post.
+CREATE TABLE IF NOT EXISTS redirects (
+    path TEXT PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_redirects_post_id ON redirects(post_id);
+
+-- WordPress comments are not limited in length.
+ALTER TABLE comments ALTER COLUMN content TYPE TEXT;
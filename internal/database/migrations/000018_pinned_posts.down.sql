@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS post_pins;
+
+-- Enum values can't be dropped, so editors go back to being users and the
+-- value stays unused.
+UPDATE users SET role = 'user' WHERE role = 'editor';
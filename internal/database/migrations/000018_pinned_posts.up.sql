@@ -0,0 +1,18 @@
+-- Editors curate the homepage and category pages without owning the posts.
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'editor';
+
+-- A pin without a category is site-wide. Pins are shown by position, and
+-- expired ones are ignored until they are renewed or removed.
+CREATE TABLE IF NOT EXISTS post_pins (
+    id BIGSERIAL PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    category_id UUID REFERENCES categories(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    pinned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- A post is pinned at most once per scope.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_pins_scope ON post_pins ((COALESCE(category_id::TEXT, '')), post_id);
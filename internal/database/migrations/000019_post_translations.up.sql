@@ -0,0 +1,24 @@
+-- The language a post is written in. Translations hold the same post in
+-- other languages; the post itself stays the canonical version.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en';
+
+CREATE TABLE IF NOT EXISTS post_translations (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    slug VARCHAR(200),
+    content TEXT NOT NULL DEFAULT '',
+    content_format content_format NOT NULL DEFAULT 'markdown',
+    content_html TEXT NOT NULL DEFAULT '',
+    content_toc JSONB NOT NULL DEFAULT '[]',
+    excerpt TEXT NOT NULL DEFAULT '',
+    word_count INT NOT NULL DEFAULT 0,
+    reading_time_minutes INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (post_id, locale)
+);
+
+-- Slugs are unique within a language.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_translations_slug ON post_translations(locale, slug);
+CREATE INDEX IF NOT EXISTS idx_post_translations_locale ON post_translations(locale);
@@ -0,0 +1,3 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS visibility;
+
+DROP TYPE IF EXISTS post_visibility;
@@ -0,0 +1,6 @@
+-- Who can reach a published post. Unlisted posts are left out of listings,
+-- feeds and the sitemap, members-only posts show anonymous readers a teaser,
+-- and private posts are limited to their authors and admins.
+CREATE TYPE post_visibility AS ENUM ('public', 'unlisted', 'members', 'private');
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS visibility post_visibility NOT NULL DEFAULT 'public';
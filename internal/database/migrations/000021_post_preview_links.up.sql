@@ -0,0 +1,14 @@
+-- Secret links to a post for reviewers without an account. The token itself
+-- is signed from the id and expiry and never stored; revoking a link or
+-- letting it expire disables it.
+CREATE TABLE IF NOT EXISTS post_preview_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_preview_links_post_id ON post_preview_links (post_id, created_at DESC);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS post_reviews;
+
+DROP TYPE IF EXISTS review_action;
+
+-- Enum values can't be dropped, so posts under review go back to draft and
+-- the values stay unused.
+UPDATE posts SET status = 'draft' WHERE status IN ('in_review', 'approved', 'changes_requested');
@@ -0,0 +1,19 @@
+-- Editorial review: contributors submit a draft, editors approve it or ask
+-- for changes, and only approved posts can be published by contributors.
+ALTER TYPE post_status ADD VALUE IF NOT EXISTS 'in_review';
+ALTER TYPE post_status ADD VALUE IF NOT EXISTS 'approved';
+ALTER TYPE post_status ADD VALUE IF NOT EXISTS 'changes_requested';
+
+CREATE TYPE review_action AS ENUM ('submitted', 'approved', 'changes_requested');
+
+-- The review history of a post, oldest first.
+CREATE TABLE IF NOT EXISTS post_reviews (
+    id BIGSERIAL PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action review_action NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_reviews_post_id ON post_reviews (post_id, created_at);
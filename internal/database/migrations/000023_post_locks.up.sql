@@ -0,0 +1,9 @@
+-- Advisory edit leases. A post has at most one; an expired lease is ignored
+-- and taken over by the next editor, so a vanished client never blocks a
+-- post for longer than its TTL.
+CREATE TABLE IF NOT EXISTS post_locks (
+    post_id UUID PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    acquired_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,15 @@
+-- Unsaved edits of a post, one buffer per user. NULL fields were left as
+-- they are in the post. base_version is the version the edits started from,
+-- so that applying them can't overwrite changes made since.
+CREATE TABLE IF NOT EXISTS post_autosaves (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title TEXT,
+    content TEXT,
+    content_format TEXT,
+    base_version INT NOT NULL,
+    saved_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_autosaves_saved_at ON post_autosaves (saved_at);
@@ -0,0 +1,2 @@
+-- The recount only corrected the counters; there is nothing to undo.
+SELECT 1;
@@ -0,0 +1,10 @@
+-- Deleting a user used to drop their reactions through the cascade without
+-- taking them off posts.reaction_counts. Recount every post from the rows
+-- once to undo the drift.
+UPDATE posts p SET reaction_counts = COALESCE((
+    SELECT jsonb_object_agg(c.reaction, c.n) FROM (
+        SELECT reaction, COUNT(*) AS n FROM post_reactions
+        WHERE post_id = p.id
+        GROUP BY reaction
+    ) AS c
+), '{}');
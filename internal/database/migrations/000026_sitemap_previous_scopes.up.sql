@@ -0,0 +1,9 @@
+-- The category and author pages a post in the sitemap was listed on before
+-- it moved off them. The next sync refreshes these along with the post's
+-- current pages, then forgets them.
+CREATE TABLE IF NOT EXISTS sitemap_previous_scopes (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    scope VARCHAR(16) NOT NULL,
+    key TEXT NOT NULL,
+    PRIMARY KEY (post_id, scope, key)
+);
@@ -0,0 +1,3 @@
+-- Enum values can't be dropped, so the lifecycle steps leave the history and
+-- the values stay unused.
+DELETE FROM post_reviews WHERE action IN ('published', 'scheduled', 'unpublished', 'archived');
@@ -0,0 +1,6 @@
+-- Publishing, scheduling, unpublishing and archiving are recorded in the
+-- review history of the post along with the review steps.
+ALTER TYPE review_action ADD VALUE IF NOT EXISTS 'published';
+ALTER TYPE review_action ADD VALUE IF NOT EXISTS 'scheduled';
+ALTER TYPE review_action ADD VALUE IF NOT EXISTS 'unpublished';
+ALTER TYPE review_action ADD VALUE IF NOT EXISTS 'archived';
@@ -0,0 +1,31 @@
+package analyticsdomain
+
+import "time"
+
+// View is a post read by a visitor, at most one per visitor, post and day.
+type View struct {
+	PostID      string
+	Day         time.Time
+	VisitorHash string
+	// Referrer is the host of the referring page, empty for direct visits.
+	Referrer string
+}
+
+type DailyViews struct {
+	Date  string `json:"date"`
+	Views int    `json:"views"`
+}
+
+type ReferrerViews struct {
+	Referrer string `json:"referrer"`
+	Views    int    `json:"views"`
+}
+
+type PostStats struct {
+	PostID     string           `json:"post_id"`
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	TotalViews int              `json:"total_views"`
+	Daily      []*DailyViews    `json:"daily"`
+	Referrers  []*ReferrerViews `json:"referrers"`
+}
@@ -0,0 +1,30 @@
+package bookmarkdomain
+
+import (
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+)
+
+// ReadingList is a named collection of posts. Public lists can be read by
+// anyone through their share URL.
+type ReadingList struct {
+	ID          string                `json:"id"`
+	OwnerID     string                `json:"owner_id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Public      bool                  `json:"public"`
+	ShareToken  string                `json:"-"`
+	ShareURL    string                `json:"share_url,omitempty"`
+	PostCount   int                   `json:"post_count"`
+	Posts       []*postdomain.Summary `json:"posts,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// ReadingListUpdate holds the fields to change; nil fields are kept.
+type ReadingListUpdate struct {
+	Name        *string
+	Description *string
+	Public      *bool
+}
@@ -4,4 +4,5 @@ type Category struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Version     int    `json:"version"`
 }
@@ -3,10 +3,12 @@ package commentdomain
 import "time"
 
 type Comment struct {
-	ID        int64     `json:"id"`
-	PostID    string    `json:"post_id"`
-	UserID    string    `json:"user_id"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        int64      `json:"id"`
+	PostID    string     `json:"post_id"`
+	UserID    string     `json:"user_id"`
+	Content   string     `json:"content"`
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
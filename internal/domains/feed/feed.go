@@ -0,0 +1,17 @@
+package feeddomain
+
+import (
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+)
+
+// Feed is the latest published posts of the blog, or of one category, author
+// or tag, ready to be written as RSS, Atom or JSON Feed.
+type Feed struct {
+	Title       string
+	Description string
+	// Updated is the latest updated_at of the posts, zero when there are none.
+	Updated time.Time
+	Posts   []*postdomain.Post
+}
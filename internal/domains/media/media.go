@@ -0,0 +1,28 @@
+package mediadomain
+
+import "time"
+
+type Media struct {
+	ID         string    `json:"id"`
+	UploaderID string    `json:"uploader_id"`
+	StorageKey string    `json:"-"`
+	Filename   string    `json:"filename"`
+	MimeType   string    `json:"mime_type"`
+	Size       int64     `json:"size"`
+	Width      int       `json:"width"`
+	Height     int       `json:"height"`
+	URL        string    `json:"url"`
+	Variants   []Variant `json:"variants"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Variant is a resized or re-encoded copy of the original upload.
+type Variant struct {
+	Name       string `json:"name"`
+	StorageKey string `json:"-"`
+	MimeType   string `json:"mime_type"`
+	Size       int64  `json:"size"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	URL        string `json:"url"`
+}
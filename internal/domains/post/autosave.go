@@ -0,0 +1,17 @@
+package postdomain
+
+import "time"
+
+// Autosave holds the unsaved edits of a user to a post, kept apart from the
+// post until applied. Nil fields are left as they are. Outdated is set when
+// the post changed since BaseVersion, which applying would then refuse.
+type Autosave struct {
+	PostID        string    `json:"post_id"`
+	UserID        string    `json:"user_id"`
+	Title         *string   `json:"title,omitempty"`
+	Content       *string   `json:"content,omitempty"`
+	ContentFormat *string   `json:"content_format,omitempty"`
+	BaseVersion   int       `json:"base_version"`
+	Outdated      bool      `json:"outdated"`
+	SavedAt       time.Time `json:"saved_at"`
+}
@@ -0,0 +1,14 @@
+package postdomain
+
+import "time"
+
+// Lock is the edit lease of a post: while it lasts, only its holder may
+// edit the post. AcquiredAt stays the same when the holder renews it.
+type Lock struct {
+	PostID     string    `json:"post_id"`
+	UserID     string    `json:"user_id"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
@@ -0,0 +1,15 @@
+package postdomain
+
+import "time"
+
+// Pin features a post on the homepage, or on the page of CategoryID when it
+// is set. Lower positions come first; a pin past ExpiresAt is ignored.
+type Pin struct {
+	PostID     string     `json:"post_id"`
+	CategoryID *string    `json:"category_id"`
+	Position   int        `json:"position"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	PinnedBy   *string    `json:"pinned_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
@@ -2,12 +2,119 @@ package postdomain
 
 import "time"
 
+// Post is served in one language: Locale is its own, or that of the
+// translation chosen for the reader. Locales lists every language it is
+// available in, its own first. Teaser is set when a members-only post is
+// served to an anonymous reader without its content.
 type Post struct {
-	ID         string    `json:"id"`
-	AuthorID   string    `json:"author_id"`
-	Title      string    `json:"title"`
-	Content    string    `json:"content"`
-	CategoryID *string   `json:"category_id"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID            string      `json:"id"`
+	AuthorID      string      `json:"author_id"`
+	Authors       []Byline    `json:"authors"`
+	Locale        string      `json:"locale"`
+	Title         string      `json:"title"`
+	Slug          *string     `json:"slug,omitempty"`
+	Content       string      `json:"content"`
+	ContentFormat string      `json:"content_format"`
+	ContentHTML   string      `json:"content_html"`
+	TOC           []Heading   `json:"toc"`
+	Excerpt       string      `json:"excerpt"`
+	WordCount     int         `json:"word_count"`
+	ReadingTime   int         `json:"reading_time_minutes"`
+	CategoryID    *string     `json:"category_id"`
+	CoverMediaID  *string     `json:"cover_media_id"`
+	Tags          []string    `json:"tags"`
+	Status        string      `json:"status"`
+	Visibility    string      `json:"visibility"`
+	Teaser        bool        `json:"teaser,omitempty"`
+	PublishedAt   *time.Time  `json:"published_at"`
+	Reactions     Reactions   `json:"reactions"`
+	MyReactions   []string    `json:"my_reactions,omitempty"`
+	Bookmarked    bool        `json:"bookmarked"`
+	Series        *SeriesNav  `json:"series,omitempty"`
+	Locales       []string    `json:"locales"`
+	Alternates    []Alternate `json:"alternates,omitempty"`
+	Version       int         `json:"version"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	DeletedAt     *time.Time  `json:"deleted_at,omitempty"`
+	// ReactedAt is when a reaction was last added or removed.
+	ReactedAt *time.Time `json:"-"`
+}
+
+// Summary is the lightweight form of a post returned by list endpoints.
+type Summary struct {
+	ID           string     `json:"id"`
+	AuthorID     string     `json:"author_id"`
+	Authors      []Byline   `json:"authors"`
+	Locale       string     `json:"locale"`
+	Title        string     `json:"title"`
+	Excerpt      string     `json:"excerpt"`
+	WordCount    int        `json:"word_count"`
+	ReadingTime  int        `json:"reading_time_minutes"`
+	CategoryID   *string    `json:"category_id"`
+	CoverMediaID *string    `json:"cover_media_id"`
+	Status       string     `json:"status"`
+	Visibility   string     `json:"visibility"`
+	PublishedAt  *time.Time `json:"published_at"`
+	Reactions    Reactions  `json:"reactions"`
+	MyReactions  []string   `json:"my_reactions,omitempty"`
+	Bookmarked   bool       `json:"bookmarked"`
+	// Pinned is only set by the featured list and by listings that put
+	// pinned posts first.
+	Pinned    bool       `json:"pinned,omitempty"`
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ReactedAt *time.Time `json:"-"`
+}
+
+// ListFilter narrows a post listing. ViewerID adds the viewer's own
+// unpublished posts, and Locale keeps the posts written in or translated
+// into that language.
+type ListFilter struct {
+	ViewerID string
+	Locale   string
+}
+
+// FeedFilter narrows a feed to one category, author or tag. Empty fields
+// don't filter.
+type FeedFilter struct {
+	CategoryID string
+	AuthorID   string
+	Tag        string
+}
+
+// Reactions counts the reactions of a post by type.
+type Reactions map[string]int
+
+// Byline is one author of a post, in the order they are credited. AuthorID
+// is the owner of the post and always has a byline.
+type Byline struct {
+	UserID    string `json:"user_id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Role      string `json:"role"`
+}
+
+// SeriesNav places a post within its series. Position and Total only count
+// the parts the viewer can see.
+type SeriesNav struct {
+	ID       string      `json:"id"`
+	Title    string      `json:"title"`
+	Position int         `json:"position"`
+	Total    int         `json:"total"`
+	Previous *SeriesLink `json:"previous"`
+	Next     *SeriesLink `json:"next"`
+}
+
+type SeriesLink struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Heading is one entry of the generated table of contents.
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
 }
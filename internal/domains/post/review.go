@@ -0,0 +1,15 @@
+package postdomain
+
+import "time"
+
+// Review is one step of the editorial review of a post: a submission, an
+// approval or a request for changes. Publishing, scheduling, unpublishing
+// and archiving are recorded as steps too.
+type Review struct {
+	ID        int64     `json:"id"`
+	PostID    string    `json:"post_id"`
+	ActorID   *string   `json:"actor_id"`
+	Action    string    `json:"action"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package postdomain
+
+import "time"
+
+type Revision struct {
+	ID        int64     `json:"id"`
+	PostID    string    `json:"post_id"`
+	Revision  int       `json:"revision"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content,omitempty"`
+	Format    string    `json:"content_format"`
+	EditorID  string    `json:"editor_id"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package postdomain
+
+import "time"
+
+// Translation is a post in another language than its own. It only holds what
+// is written; everything else is shared with the post.
+type Translation struct {
+	PostID        string    `json:"post_id"`
+	Locale        string    `json:"locale"`
+	Title         string    `json:"title"`
+	Slug          *string   `json:"slug,omitempty"`
+	Content       string    `json:"content"`
+	ContentFormat string    `json:"content_format"`
+	ContentHTML   string    `json:"content_html"`
+	TOC           []Heading `json:"toc"`
+	Excerpt       string    `json:"excerpt"`
+	WordCount     int       `json:"word_count"`
+	ReadingTime   int       `json:"reading_time_minutes"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Alternate links to the post in one language, as in an hreflang link. The
+// post's own language is also listed as "x-default".
+type Alternate struct {
+	Hreflang string `json:"hreflang"`
+	Href     string `json:"href"`
+}
@@ -0,0 +1,34 @@
+package previewdomain
+
+import (
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+)
+
+// Link is a secret link to a post, usually an unpublished draft, for
+// reviewers without an account. Its token is signed from the ID and expiry,
+// so it isn't stored, and is only given out while the link is active.
+type Link struct {
+	ID        string     `json:"id"`
+	PostID    string     `json:"post_id"`
+	CreatedBy *string    `json:"created_by"`
+	Note      string     `json:"note,omitempty"`
+	Token     string     `json:"-"`
+	URL       string     `json:"url,omitempty"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Active reports whether the link still opens the post at now.
+func (l *Link) Active(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// Preview is a post opened through a preview link, as it currently stands.
+type Preview struct {
+	Preview   bool             `json:"preview"`
+	ExpiresAt time.Time        `json:"expires_at"`
+	Post      *postdomain.Post `json:"post"`
+}
@@ -0,0 +1,9 @@
+package reactiondomain
+
+// Reactions are the reaction counts of a post and the reactions of the
+// current user.
+type Reactions struct {
+	PostID string         `json:"post_id"`
+	Counts map[string]int `json:"counts"`
+	Mine   []string       `json:"mine"`
+}
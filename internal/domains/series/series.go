@@ -0,0 +1,26 @@
+package seriesdomain
+
+import (
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+)
+
+// Series links the parts of a multi-part post in order.
+type Series struct {
+	ID          string                `json:"id"`
+	AuthorID    string                `json:"author_id"`
+	Title       string                `json:"title"`
+	Description string                `json:"description"`
+	Parts       []*postdomain.Summary `json:"parts"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// Part is a post of a series with its title, as stored.
+type Part struct {
+	PostID     string
+	Title      string
+	Status     string
+	Visibility string
+}
@@ -0,0 +1,18 @@
+package sitemapdomain
+
+import "time"
+
+// URL is one page listed in the sitemap. Path is relative to the site URL.
+// Locales lists the languages a post is available in, its own first, and is
+// empty for other pages.
+type URL struct {
+	Path    string
+	LastMod time.Time
+	Locales []string
+}
+
+// File is one sitemap of the sitemap index, numbered from 1.
+type File struct {
+	Page    int
+	LastMod time.Time
+}
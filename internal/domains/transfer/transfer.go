@@ -0,0 +1,50 @@
+package transferdomain
+
+import "time"
+
+// File is one Markdown document of an import or export. Name is its path
+// within the ZIP archive or directory.
+type File struct {
+	Name string
+	Data []byte
+}
+
+// FrontMatter is the YAML header of an imported or exported Markdown file.
+type FrontMatter struct {
+	Title    string     `yaml:"title"`
+	Slug     string     `yaml:"slug,omitempty"`
+	Date     *time.Time `yaml:"date,omitempty"`
+	Category string     `yaml:"category,omitempty"`
+	Tags     []string   `yaml:"tags,omitempty"`
+	// Author is the email of the author's account.
+	Author string `yaml:"author,omitempty"`
+	Draft  bool   `yaml:"draft,omitempty"`
+}
+
+type ImportOptions struct {
+	// DryRun checks every file and reports what would happen without
+	// creating anything.
+	DryRun bool
+	// AuthorID owns the files that don't name an author.
+	AuthorID string
+}
+
+type ImportReport struct {
+	DryRun  bool          `json:"dry_run"`
+	Created int           `json:"created"`
+	Skipped int           `json:"skipped"`
+	Failed  int           `json:"failed"`
+	Files   []*ImportItem `json:"files"`
+}
+
+// ImportItem is the outcome for one file: "created", "would_create" on a dry
+// run, "exists" when a post already has the slug, or "failed".
+type ImportItem struct {
+	File       string `json:"file"`
+	Result     string `json:"result"`
+	Slug       string `json:"slug,omitempty"`
+	Title      string `json:"title,omitempty"`
+	PostStatus string `json:"post_status,omitempty"`
+	PostID     string `json:"post_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,53 @@
+package wordpressdomain
+
+import "time"
+
+// ImportJob is a WordPress import running in the background. Total and
+// Processed count the authors, categories, tags and posts of the export;
+// comments and redirects are handled with their post.
+type ImportJob struct {
+	ID         string        `json:"id"`
+	Site       string        `json:"site"`
+	Status     string        `json:"status"`
+	Total      int           `json:"total"`
+	Processed  int           `json:"processed"`
+	Counts     ImportCounts  `json:"counts"`
+	Errors     []ImportError `json:"errors"`
+	Error      string        `json:"error,omitempty"`
+	CreatedBy  *string       `json:"created_by"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	FinishedAt *time.Time    `json:"finished_at"`
+}
+
+type ImportCounts struct {
+	Users      ImportCount `json:"users"`
+	Categories ImportCount `json:"categories"`
+	Tags       ImportCount `json:"tags"`
+	Posts      ImportCount `json:"posts"`
+	Comments   ImportCount `json:"comments"`
+	Redirects  ImportCount `json:"redirects"`
+}
+
+// ImportCount tells what became of the items of one kind. Skipped items
+// already existed, or have no equivalent here, such as spam comments.
+type ImportCount struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// ImportError explains why an item failed, e.g. item "post 42".
+type ImportError struct {
+	Item  string `json:"item"`
+	Error string `json:"error"`
+}
+
+// Comment is a WordPress comment on its way in, keeping its original date.
+type Comment struct {
+	WPID      int64
+	PostID    string
+	UserID    string
+	Content   string
+	CreatedAt time.Time
+}
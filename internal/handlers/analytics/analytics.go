@@ -0,0 +1,76 @@
+package analyticshandler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	analyticsusecase "github.com/codepnw/blog-api/internal/usecases/analytics"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultRangeDays = 30
+
+type handler struct {
+	uc analyticsusecase.Usecase
+}
+
+func NewAnalyticsHandler(uc analyticsusecase.Usecase) *handler {
+	return &handler{uc: uc}
+}
+
+// Get Post Stats
+// @Summary Get Post Stats
+// @Description Unique visitors per day and top referrers. Only for the author and admins.
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param from query string false "First day, YYYY-MM-DD (default 30 days ago)"
+// @Param to query string false "Last day, YYYY-MM-DD (default today)"
+// @Success 200 {object} analyticsdomain.PostStats
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/stats [get]
+func (h *handler) GetPostStats(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	to := time.Now().UTC()
+	if value := ctx.Query("to"); value != "" {
+		if to, err = time.Parse(time.DateOnly, value); err != nil {
+			return handlers.BadRequest(ctx, "to must be a date in YYYY-MM-DD format")
+		}
+	}
+
+	from := to.AddDate(0, 0, -(defaultRangeDays - 1))
+	if value := ctx.Query("from"); value != "" {
+		if from, err = time.Parse(time.DateOnly, value); err != nil {
+			return handlers.BadRequest(ctx, "from must be a date in YYYY-MM-DD format")
+		}
+	}
+
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	result, err := h.uc.GetPostStats(ctx.Context(), postID, user.UserID, user.Role, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrPostNotFound):
+			return handlers.NotFound(ctx, err.Error())
+		case errors.Is(err, errs.ErrStatsForbidden):
+			return handlers.Forbidden(ctx, err.Error())
+		case errors.Is(err, errs.ErrInvalidDateRange):
+			return handlers.BadRequest(ctx, err.Error())
+		default:
+			return handlers.InternalServerError(ctx, err)
+		}
+	}
+	return handlers.Success(ctx, result)
+}
@@ -0,0 +1,349 @@
+package bookmarkhandler
+
+import (
+	"errors"
+
+	bookmarkdomain "github.com/codepnw/blog-api/internal/domains/bookmark"
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	bookmarkusecase "github.com/codepnw/blog-api/internal/usecases/bookmark"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/validate"
+	"github.com/gofiber/fiber/v2"
+)
+
+type handler struct {
+	uc bookmarkusecase.Usecase
+	// sharePath is the route shared lists are served from.
+	sharePath string
+}
+
+func NewBookmarkHandler(uc bookmarkusecase.Usecase, sharePath string) *handler {
+	return &handler{uc: uc, sharePath: sharePath}
+}
+
+// Get Bookmarks
+// @Summary Get Bookmarks
+// @Description Newest first. Posts that are no longer published are left out.
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} []postdomain.Summary
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/bookmarks [get]
+func (h *handler) GetBookmarks(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.GetBookmarks(ctx.Context(), user.UserID)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Add Bookmark
+// @Summary Add Bookmark
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/bookmarks/{post_id} [put]
+func (h *handler) AddBookmark(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	if err := h.uc.AddBookmark(ctx.Context(), user.UserID, postID); err != nil {
+		return h.bookmarkError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+// Remove Bookmark
+// @Summary Remove Bookmark
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/bookmarks/{post_id} [delete]
+func (h *handler) RemoveBookmark(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	if err := h.uc.RemoveBookmark(ctx.Context(), user.UserID, postID); err != nil {
+		return h.bookmarkError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+// Create Reading List
+// @Summary Create Reading List
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param data body bookmarkhandler.ReadingListCreateReq true "Reading list data"
+// @Success 201 {object} bookmarkdomain.ReadingList
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/reading-lists [post]
+func (h *handler) CreateList(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(ReadingListCreateReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	input := &bookmarkdomain.ReadingList{
+		OwnerID:     user.UserID,
+		Name:        req.Name,
+		Description: req.Description,
+		Public:      req.Public,
+	}
+
+	result, err := h.uc.CreateList(ctx.Context(), input)
+	if err != nil {
+		return h.bookmarkError(ctx, err)
+	}
+	return handlers.Created(ctx, h.withShareURL(ctx, result))
+}
+
+// Get My Reading Lists
+// @Summary Get My Reading Lists
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} []bookmarkdomain.ReadingList
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/reading-lists [get]
+func (h *handler) GetMyLists(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.GetMyLists(ctx.Context(), user.UserID)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	for _, list := range result {
+		h.withShareURL(ctx, list)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Get Reading List
+// @Summary Get Reading List
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "Reading List ID"
+// @Success 200 {object} bookmarkdomain.ReadingList
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/reading-lists/{list_id} [get]
+func (h *handler) GetList(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	id := ctx.Params(handlers.ParamKeyListID)
+	result, err := h.uc.GetList(ctx.Context(), id, user.UserID)
+	if err != nil {
+		return h.bookmarkError(ctx, err)
+	}
+	return handlers.Success(ctx, h.withShareURL(ctx, result))
+}
+
+// Get Shared Reading List
+// @Summary Get Shared Reading List
+// @Description Public lists only, with their published posts.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Param share_token path string true "Share token"
+// @Success 200 {object} bookmarkdomain.ReadingList
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /reading-lists/{share_token} [get]
+func (h *handler) GetSharedList(ctx *fiber.Ctx) error {
+	token := ctx.Params(handlers.ParamKeyShareToken)
+
+	result, err := h.uc.GetSharedList(ctx.Context(), token)
+	if err != nil {
+		return h.bookmarkError(ctx, err)
+	}
+	return handlers.Success(ctx, h.withShareURL(ctx, result))
+}
+
+// Edit Reading List
+// @Summary Edit Reading List
+// @Description Making a list private disables its share URL; making it public again restores the same URL.
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "Reading List ID"
+// @Param data body bookmarkhandler.ReadingListUpdateReq true "Reading list data"
+// @Success 200 {object} bookmarkdomain.ReadingList
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/reading-lists/{list_id} [patch]
+func (h *handler) UpdateList(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(ReadingListUpdateReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	input := &bookmarkdomain.ReadingListUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+		Public:      req.Public,
+	}
+
+	id := ctx.Params(handlers.ParamKeyListID)
+	result, err := h.uc.UpdateList(ctx.Context(), id, user.UserID, input)
+	if err != nil {
+		return h.bookmarkError(ctx, err)
+	}
+	return handlers.Success(ctx, h.withShareURL(ctx, result))
+}
+
+// Delete Reading List
+// @Summary Delete Reading List
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "Reading List ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/reading-lists/{list_id} [delete]
+func (h *handler) DeleteList(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	id := ctx.Params(handlers.ParamKeyListID)
+	if err := h.uc.DeleteList(ctx.Context(), id, user.UserID); err != nil {
+		return h.bookmarkError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+// Add Post To Reading List
+// @Summary Add Post To Reading List
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "Reading List ID"
+// @Param post_id path string true "Post ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/reading-lists/{list_id}/posts/{post_id} [put]
+func (h *handler) AddToList(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	id := ctx.Params(handlers.ParamKeyListID)
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	if err := h.uc.AddToList(ctx.Context(), id, user.UserID, postID); err != nil {
+		return h.bookmarkError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+// Remove Post From Reading List
+// @Summary Remove Post From Reading List
+// @Tags reading-lists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param list_id path string true "Reading List ID"
+// @Param post_id path string true "Post ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /users/me/reading-lists/{list_id}/posts/{post_id} [delete]
+func (h *handler) RemoveFromList(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	id := ctx.Params(handlers.ParamKeyListID)
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	if err := h.uc.RemoveFromList(ctx.Context(), id, user.UserID, postID); err != nil {
+		return h.bookmarkError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+// withShareURL sets the public URL of shared lists.
+func (h *handler) withShareURL(ctx *fiber.Ctx, list *bookmarkdomain.ReadingList) *bookmarkdomain.ReadingList {
+	if list.Public {
+		list.ShareURL = ctx.BaseURL() + h.sharePath + "/" + list.ShareToken
+	}
+	return list
+}
+
+func (h *handler) bookmarkError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound),
+		errors.Is(err, errs.ErrReadingListNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
@@ -0,0 +1,13 @@
+package bookmarkhandler
+
+type ReadingListCreateReq struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description,omitempty" validate:"omitempty,max=500"`
+	Public      bool   `json:"public,omitempty"`
+}
+
+type ReadingListUpdateReq struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
+	Public      *bool   `json:"public,omitempty"`
+}
@@ -1,9 +1,13 @@
 package categoryhandler
 
 import (
+	"errors"
+	"time"
+
 	categorydomain "github.com/codepnw/blog-api/internal/domains/category"
 	"github.com/codepnw/blog-api/internal/handlers"
 	categoryusecase "github.com/codepnw/blog-api/internal/usecases/category"
+	"github.com/codepnw/blog-api/internal/utils/errs"
 	"github.com/codepnw/blog-api/internal/utils/validate"
 	"github.com/gofiber/fiber/v2"
 )
@@ -55,7 +59,9 @@ func (h *handler) Create(ctx *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param category_id path string true "Category ID"
+// @Param If-None-Match header string false "ETag of the cached copy"
 // @Success 200 {object} categorydomain.Category
+// @Success 304 {object} handlers.EmptyRes
 // @Failure 401 {object} handlers.UnauthorizedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /categories/category_id [get]
@@ -64,7 +70,11 @@ func (h *handler) GetByID(ctx *fiber.Ctx) error {
 
 	result, err := h.uc.GetByID(ctx.Context(), id)
 	if err != nil {
-		return handlers.InternalServerError(ctx, err)
+		return h.categoryError(ctx, err)
+	}
+
+	if handlers.Fresh(ctx, handlers.VersionETag(result.Version), time.Time{}) {
+		return handlers.NotModified(ctx)
 	}
 	return handlers.Success(ctx, result)
 }
@@ -75,7 +85,9 @@ func (h *handler) GetByID(ctx *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param If-None-Match header string false "ETag of the cached copy"
 // @Success 200 {object} []categorydomain.Category
+// @Success 304 {object} handlers.EmptyRes
 // @Failure 401 {object} handlers.UnauthorizedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /categories [get]
@@ -84,6 +96,14 @@ func (h *handler) GetAll(ctx *fiber.Ctx) error {
 	if err != nil {
 		return handlers.InternalServerError(ctx, err)
 	}
+
+	items := make([]handlers.Validator, 0, len(result))
+	for _, c := range result {
+		items = append(items, handlers.Validator{ID: c.ID, Version: c.Version})
+	}
+	if handlers.Fresh(ctx, handlers.CollectionETag(items), time.Time{}) {
+		return handlers.NotModified(ctx)
+	}
 	return handlers.Success(ctx, result)
 }
 
@@ -94,10 +114,13 @@ func (h *handler) GetAll(ctx *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param category_id path string true "Category ID"
+// @Param If-Match header string false "ETag of the version being edited"
 // @Param data body categoryhandler.CategoryUpdateReq true "New category"
 // @Success 200 {object} categorydomain.Category
 // @Failure 400 {object} handlers.BadRequestRes
 // @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /categories/category_id [patch]
 func (h *handler) Update(ctx *fiber.Ctx) error {
@@ -111,6 +134,11 @@ func (h *handler) Update(ctx *fiber.Ctx) error {
 		return handlers.BadRequest(ctx, err.Error())
 	}
 
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
 	input := new(categorydomain.Category)
 	if req.Name != nil {
 		input.Name = *req.Name
@@ -119,12 +147,15 @@ func (h *handler) Update(ctx *fiber.Ctx) error {
 		input.Description = *req.Description
 	}
 	input.ID = id
+	input.Version = version
 
-	if err := h.uc.Update(ctx.Context(), input); err != nil {
-		return handlers.InternalServerError(ctx, err)
+	result, err := h.uc.Update(ctx.Context(), input)
+	if err != nil {
+		return h.categoryError(ctx, err)
 	}
 
-	return handlers.Success(ctx, "category updated")
+	handlers.SetETag(ctx, result.Version)
+	return handlers.Success(ctx, result)
 }
 
 // Delete Category
@@ -134,15 +165,34 @@ func (h *handler) Update(ctx *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param category_id path string true "Category ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 204 {object} handlers.EmptyRes
 // @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /categories/category_id [delete]
 func (h *handler) Delete(ctx *fiber.Ctx) error {
 	id := ctx.Params(handlers.ParamKeyCategoryID)
 
-	if err := h.uc.Delete(ctx.Context(), id); err != nil {
-		return handlers.InternalServerError(ctx, err)
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	if err := h.uc.Delete(ctx.Context(), id, version); err != nil {
+		return h.categoryError(ctx, err)
 	}
 	return handlers.NoContent(ctx)
 }
+
+func (h *handler) categoryError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrCategoryNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrVersionConflict):
+		return handlers.PreconditionFailed(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
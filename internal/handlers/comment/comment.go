@@ -1,12 +1,15 @@
 package commenthandler
 
 import (
+	"errors"
 	"strconv"
+	"time"
 
 	commentdomain "github.com/codepnw/blog-api/internal/domains/comment"
 	"github.com/codepnw/blog-api/internal/handlers"
 	"github.com/codepnw/blog-api/internal/middleware"
 	commentusecase "github.com/codepnw/blog-api/internal/usecases/comment"
+	"github.com/codepnw/blog-api/internal/utils/errs"
 	"github.com/codepnw/blog-api/internal/utils/validate"
 	"github.com/gofiber/fiber/v2"
 )
@@ -57,6 +60,8 @@ func (h *handler) CreateComment(ctx *fiber.Ctx) error {
 	if err != nil {
 		return handlers.InternalServerError(ctx, err)
 	}
+
+	handlers.SetETag(ctx, result.Version)
 	return handlers.Created(ctx, result)
 }
 
@@ -65,7 +70,9 @@ func (h *handler) CreateComment(ctx *fiber.Ctx) error {
 // @Tags comments
 // @Accept json
 // @Produce json
+// @Param If-None-Match header string false "ETag of the cached copy"
 // @Success 200 {object} []commentdomain.Comment
+// @Success 304 {object} handlers.EmptyRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /posts{post_id}/comments [get]
 func (h *handler) GetCommentByPost(ctx *fiber.Ctx) error {
@@ -75,6 +82,14 @@ func (h *handler) GetCommentByPost(ctx *fiber.Ctx) error {
 	if err != nil {
 		return handlers.InternalServerError(ctx, err)
 	}
+
+	items := make([]handlers.Validator, 0, len(result))
+	for _, c := range result {
+		items = append(items, handlers.Validator{ID: strconv.FormatInt(c.ID, 10), Version: c.Version})
+	}
+	if handlers.Fresh(ctx, handlers.CollectionETag(items), time.Time{}) {
+		return handlers.NotModified(ctx)
+	}
 	return handlers.Success(ctx, result)
 }
 
@@ -86,10 +101,12 @@ func (h *handler) GetCommentByPost(ctx *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param post_id path string true "Post ID"
 // @Param comment_id path string true "Comment ID"
+// @Param If-Match header string false "ETag of the version being edited"
 // @Param data body commenthandler.CommentReq true "New comment"
 // @Success 200 {object} commentdomain.Comment
 // @Failure 400 {object} handlers.BadRequestRes
 // @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /posts{post_id}/comments/{comment_id} [patch]
 func (h *handler) EditComment(ctx *fiber.Ctx) error {
@@ -109,16 +126,24 @@ func (h *handler) EditComment(ctx *fiber.Ctx) error {
 		return handlers.BadRequest(ctx, err.Error())
 	}
 
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
 	input := &commentdomain.Comment{
 		ID:      int64(commentID),
 		UserID:  user.UserID,
 		PostID:  postID,
 		Content: req.Content,
+		Version: version,
 	}
 	result, err := h.uc.EditComment(ctx.Context(), input, user.Role)
 	if err != nil {
-		return handlers.InternalServerError(ctx, err)
+		return h.commentError(ctx, err)
 	}
+
+	handlers.SetETag(ctx, result.Version)
 	return handlers.Success(ctx, result)
 }
 
@@ -130,8 +155,10 @@ func (h *handler) EditComment(ctx *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param post_id path string true "Post ID"
 // @Param comment_id path string true "Comment ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 204 {object} handlers.EmptyRes
 // @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /posts{post_id}/comments/{comment_id} [delete]
 func (h *handler) DeleteComment(ctx *fiber.Ctx) error {
@@ -143,8 +170,28 @@ func (h *handler) DeleteComment(ctx *fiber.Ctx) error {
 	commentID := ctx.Params(handlers.ParamKeyCommentID)
 	id, _ := strconv.ParseInt(commentID, 10, 64)
 
-	if err = h.uc.DeleteComment(ctx.Context(), id, user.UserID, user.Role); err != nil {
-		return handlers.InternalServerError(ctx, err)
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	if err = h.uc.DeleteComment(ctx.Context(), id, user.UserID, user.Role, version); err != nil {
+		return h.commentError(ctx, err)
 	}
 	return handlers.NoContent(ctx)
 }
+
+func (h *handler) commentError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrCommentNotFound), errors.Is(err, errs.ErrPostNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrCommentNotOwner):
+		return handlers.Forbidden(ctx, err.Error())
+	case errors.Is(err, errs.ErrCommentIsRequired):
+		return handlers.BadRequest(ctx, err.Error())
+	case errors.Is(err, errs.ErrVersionConflict):
+		return handlers.PreconditionFailed(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
@@ -1,5 +1,5 @@
 package commenthandler
 
 type CommentReq struct {
-	Content string `json:"content" validate:"required"`
+	Content string `json:"content" validate:"required,max=255"`
 }
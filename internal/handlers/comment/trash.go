@@ -0,0 +1,74 @@
+package commenthandler
+
+import (
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Get Trashed Comments
+// @Summary Get Trashed Comments
+// @Description Lists the current user's deleted comments. Admins can pass all=true to see every user's.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param all query bool false "Every user's trash (admin only)"
+// @Success 200 {array} []commentdomain.Comment
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /trash/comments [get]
+func (h *handler) GetTrash(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	userID := user.UserID
+	if ctx.QueryBool("all") {
+		if user.Role != string(userusecase.RoleAdmin) {
+			return handlers.Forbidden(ctx, "no permissions")
+		}
+		userID = ""
+	}
+
+	result, err := h.uc.GetTrash(ctx.Context(), userID)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Restore Comment
+// @Summary Restore Comment
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} commentdomain.Comment
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/comments/{comment_id}/restore [post]
+func (h *handler) RestoreComment(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	commentID, err := ctx.ParamsInt(handlers.ParamKeyCommentID)
+	if err != nil {
+		return handlers.BadRequest(ctx, "invalid comment id")
+	}
+
+	result, err := h.uc.RestoreComment(ctx.Context(), int64(commentID), user.UserID, user.Role)
+	if err != nil {
+		return h.commentError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
@@ -6,4 +6,17 @@ const (
 	ParamKeyAuthorID   = "author_id"
 	ParamKeyUserID     = "user_id"
 	ParamKeyCommentID  = "comment_id"
+	ParamKeyRevision   = "revision"
+	ParamKeyMediaID    = "media_id"
+	ParamKeyReaction   = "reaction"
+	ParamKeyListID     = "list_id"
+	ParamKeyShareToken = "share_token"
+	ParamKeySeriesID   = "series_id"
+	ParamKeyTag        = "tag"
+	ParamKeyPage       = "page"
+	ParamKeyJobID      = "job_id"
+	ParamKeyLocale     = "locale"
+	ParamKeyLinkID     = "link_id"
+	ParamKeyToken      = "token"
+	ParamKeySlug       = "slug"
 )
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Validator identifies one item of a collection for CollectionETag. State
+// covers data that changes without a new version, such as counters.
+type Validator struct {
+	ID      string
+	Version int
+	State   string
+}
+
+// VersionETag formats a resource version as a strong entity tag.
+func VersionETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// StateETag is VersionETag with a hash of state appended, for responses that
+// include data changing without a new version. IfMatchVersion only looks at
+// the version part, so the tag can still be sent back to edit the resource.
+func StateETag(version int, state string) string {
+	if state == "" {
+		return VersionETag(version)
+	}
+	sum := sha256.Sum256([]byte(state))
+	return fmt.Sprintf(`"%d.%x"`, version, sum[:8])
+}
+
+// CollectionETag derives a strong entity tag from the id and version of every
+// item, so adding, removing, reordering or editing any of them changes it.
+func CollectionETag(items []Validator) string {
+	h := sha256.New()
+	for _, item := range items {
+		fmt.Fprintf(h, "%s:%d:%s;", item.ID, item.Version, item.State)
+	}
+	return fmt.Sprintf(`"%x"`, h.Sum(nil)[:16])
+}
+
+// SetETag exposes the resource version as a strong entity tag.
+func SetETag(ctx *fiber.Ctx, version int) {
+	ctx.Set(fiber.HeaderETag, VersionETag(version))
+}
+
+// Fresh sets the ETag and, when known, Last-Modified on the response and
+// reports whether the client's cached copy is still current, in which case
+// the handler answers with NotModified.
+func Fresh(ctx *fiber.Ctx, etag string, lastModified time.Time) bool {
+	ctx.Set(fiber.HeaderETag, etag)
+	if !lastModified.IsZero() {
+		ctx.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-None-Match takes precedence; If-Modified-Since is only looked at
+	// when the client didn't send an entity tag.
+	if noneMatch := ctx.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		return etagMatches(noneMatch, etag)
+	}
+
+	modifiedSince := ctx.Get(fiber.HeaderIfModifiedSince)
+	if modifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(modifiedSince)
+	if err != nil {
+		return false
+	}
+	// HTTP dates have second precision.
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches does the weak comparison If-None-Match calls for against a
+// comma separated list of tags.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatchVersion returns the version sent in If-Match, or 0 when the header
+// is absent or "*" and the write should not be version checked.
+func IfMatchVersion(ctx *fiber.Ctx) (int, error) {
+	value := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	// If-Match uses strong comparison, so weak tags never match.
+	if strings.HasPrefix(value, "W/") {
+		return 0, errs.ErrInvalidIfMatch
+	}
+
+	value, _, _ = strings.Cut(strings.Trim(value, `"`), ".")
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return 0, errs.ErrInvalidIfMatch
+	}
+	return version, nil
+}
@@ -0,0 +1,96 @@
+package feedhandler
+
+import (
+	"encoding/xml"
+	"time"
+
+	feeddomain "github.com/codepnw/blog-api/internal/domains/feed"
+	"github.com/gofiber/fiber/v2"
+)
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Link      atomLink     `xml:"link"`
+	Published string       `xml:"published,omitempty"`
+	Updated   string       `xml:"updated"`
+	Authors   []atomPerson `xml:"author"`
+	Summary   *atomText    `xml:"summary,omitempty"`
+	Content   *atomText    `xml:"content,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func (h *handler) atom(ctx *fiber.Ctx, feed *feeddomain.Feed) ([]byte, error) {
+	// updated is required even when there is nothing in the feed yet.
+	updated := feed.Updated
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	out := atomFeed{
+		ID:       selfURL(ctx),
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: selfURL(ctx), Rel: "self", Type: "application/atom+xml"},
+			{Href: h.homeURL(ctx), Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]atomEntry, 0, len(feed.Posts)),
+	}
+
+	for _, post := range feed.Posts {
+		entry := atomEntry{
+			ID:      "urn:uuid:" + post.ID,
+			Title:   post.Title,
+			Link:    atomLink{Href: h.postURL(ctx, post.ID), Rel: "alternate"},
+			Updated: post.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		if post.PublishedAt != nil {
+			entry.Published = post.PublishedAt.UTC().Format(time.RFC3339)
+		}
+
+		// Every entry needs an author, as the feed itself names none.
+		names := authorNames(post)
+		if len(names) == 0 {
+			names = []string{feed.Title}
+		}
+		for _, name := range names {
+			entry.Authors = append(entry.Authors, atomPerson{Name: name})
+		}
+
+		if post.Excerpt != "" {
+			entry.Summary = &atomText{Type: "text", Body: post.Excerpt}
+		}
+		if content := h.content(post); content != "" {
+			entry.Content = &atomText{Type: "html", Body: content}
+		}
+		out.Entries = append(out.Entries, entry)
+	}
+
+	return marshalXML(out)
+}
@@ -0,0 +1,177 @@
+package feedhandler
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/codepnw/blog-api/internal/config"
+	feeddomain "github.com/codepnw/blog-api/internal/domains/feed"
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/handlers"
+	feedusecase "github.com/codepnw/blog-api/internal/usecases/feed"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+type handler struct {
+	uc        feedusecase.Usecase
+	cfg       config.FeedConfig
+	siteURL   string
+	apiPrefix string
+}
+
+func NewFeedHandler(uc feedusecase.Usecase, cfg config.FeedConfig, siteURL, apiPrefix string) *handler {
+	return &handler{uc: uc, cfg: cfg, siteURL: siteURL, apiPrefix: apiPrefix}
+}
+
+// encoder writes a feed in one of the formats.
+type encoder func(ctx *fiber.Ctx, feed *feeddomain.Feed) ([]byte, error)
+
+// RSS Feed
+// @Summary RSS Feed
+// @Description RSS 2.0 feed of the latest published posts, optionally of one category, author or tag.
+// @Tags feeds
+// @Produce xml
+// @Param category_id path string false "Category ID"
+// @Param user_id path string false "Author ID"
+// @Param tag path string false "Tag name"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of the cached copy"
+// @Success 200 {string} string "RSS document"
+// @Success 304 {object} handlers.EmptyRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /feed.xml [get]
+// @Router /categories/{category_id}/feed.xml [get]
+// @Router /users/{user_id}/feed.xml [get]
+// @Router /tags/{tag}/feed.xml [get]
+func (h *handler) RSS(ctx *fiber.Ctx) error {
+	return h.serve(ctx, "application/rss+xml; charset=utf-8", h.rss)
+}
+
+// Atom Feed
+// @Summary Atom Feed
+// @Description Atom feed of the latest published posts, optionally of one category, author or tag.
+// @Tags feeds
+// @Produce xml
+// @Param category_id path string false "Category ID"
+// @Param user_id path string false "Author ID"
+// @Param tag path string false "Tag name"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of the cached copy"
+// @Success 200 {string} string "Atom document"
+// @Success 304 {object} handlers.EmptyRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /atom.xml [get]
+// @Router /categories/{category_id}/atom.xml [get]
+// @Router /users/{user_id}/atom.xml [get]
+// @Router /tags/{tag}/atom.xml [get]
+func (h *handler) Atom(ctx *fiber.Ctx) error {
+	return h.serve(ctx, "application/atom+xml; charset=utf-8", h.atom)
+}
+
+// JSON Feed
+// @Summary JSON Feed
+// @Description JSON Feed 1.1 of the latest published posts, optionally of one category, author or tag.
+// @Tags feeds
+// @Produce json
+// @Param category_id path string false "Category ID"
+// @Param user_id path string false "Author ID"
+// @Param tag path string false "Tag name"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of the cached copy"
+// @Success 200 {string} string "JSON Feed document"
+// @Success 304 {object} handlers.EmptyRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /feed.json [get]
+// @Router /categories/{category_id}/feed.json [get]
+// @Router /users/{user_id}/feed.json [get]
+// @Router /tags/{tag}/feed.json [get]
+func (h *handler) JSON(ctx *fiber.Ctx) error {
+	return h.serve(ctx, "application/feed+json; charset=utf-8", h.jsonFeed)
+}
+
+func (h *handler) serve(ctx *fiber.Ctx, contentType string, encode encoder) error {
+	tag, err := url.PathUnescape(ctx.Params(handlers.ParamKeyTag))
+	if err != nil {
+		return handlers.BadRequest(ctx, "invalid tag")
+	}
+	filter := postdomain.FeedFilter{
+		CategoryID: ctx.Params(handlers.ParamKeyCategoryID),
+		AuthorID:   ctx.Params(handlers.ParamKeyUserID),
+		Tag:        tag,
+	}
+
+	feed, err := h.uc.GetFeed(ctx.Context(), filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrCategoryNotFound),
+			errors.Is(err, errs.ErrUserNotFound),
+			errors.Is(err, errs.ErrTagNotFound):
+			return handlers.NotFound(ctx, err.Error())
+		default:
+			return handlers.InternalServerError(ctx, err)
+		}
+	}
+
+	// Feed readers mostly poll with If-Modified-Since. A post leaving the
+	// feed doesn't move Last-Modified, but it does change the ETag, which
+	// takes precedence when sent.
+	if handlers.Fresh(ctx, feedETag(feed), feed.Updated) {
+		return handlers.NotModified(ctx)
+	}
+
+	body, err := encode(ctx, feed)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	ctx.Set(fiber.HeaderContentType, contentType)
+	return ctx.Send(body)
+}
+
+// feedETag covers the title, which follows renamed categories and authors,
+// and the membership and versions of the posts.
+func feedETag(feed *feeddomain.Feed) string {
+	items := make([]handlers.Validator, 0, len(feed.Posts)+1)
+	items = append(items, handlers.Validator{State: feed.Title})
+	for _, post := range feed.Posts {
+		items = append(items, handlers.Validator{ID: post.ID, Version: post.Version})
+	}
+	return handlers.CollectionETag(items)
+}
+
+func (h *handler) homeURL(ctx *fiber.Ctx) string {
+	return handlers.SiteURL(ctx, h.siteURL, h.apiPrefix)
+}
+
+func (h *handler) postURL(ctx *fiber.Ctx, id string) string {
+	return h.homeURL(ctx) + "/posts/" + id
+}
+
+func selfURL(ctx *fiber.Ctx) string {
+	return ctx.BaseURL() + ctx.Path()
+}
+
+// content returns the rendered post when feeds carry full content and it
+// has been rendered, otherwise an empty string and the excerpt is used.
+// Members-only posts only ever show their excerpt.
+func (h *handler) content(post *postdomain.Post) string {
+	if !h.cfg.FullContent || post.Visibility == string(postusecase.VisibilityMembers) {
+		return ""
+	}
+	return post.ContentHTML
+}
+
+func authorNames(post *postdomain.Post) []string {
+	names := make([]string, 0, len(post.Authors))
+	for _, byline := range post.Authors {
+		if name := strings.TrimSpace(byline.FirstName + " " + byline.LastName); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,71 @@
+package feedhandler
+
+import (
+	"encoding/json"
+	"time"
+
+	feeddomain "github.com/codepnw/blog-api/internal/domains/feed"
+	"github.com/gofiber/fiber/v2"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished *time.Time       `json:"date_published,omitempty"`
+	DateModified  time.Time        `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+func (h *handler) jsonFeed(ctx *fiber.Ctx, feed *feeddomain.Feed) ([]byte, error) {
+	out := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       feed.Title,
+		HomePageURL: h.homeURL(ctx),
+		FeedURL:     selfURL(ctx),
+		Description: feed.Description,
+		Items:       make([]jsonFeedItem, 0, len(feed.Posts)),
+	}
+
+	for _, post := range feed.Posts {
+		item := jsonFeedItem{
+			ID:            post.ID,
+			URL:           h.postURL(ctx, post.ID),
+			Title:         post.Title,
+			DatePublished: post.PublishedAt,
+			DateModified:  post.UpdatedAt,
+		}
+		// Items need content_html or content_text; the excerpt fills in when
+		// feeds don't carry full content.
+		if content := h.content(post); content != "" {
+			item.ContentHTML = content
+			item.Summary = post.Excerpt
+		} else {
+			item.ContentText = post.Excerpt
+		}
+		for _, name := range authorNames(post) {
+			item.Authors = append(item.Authors, jsonFeedAuthor{Name: name})
+		}
+		out.Items = append(out.Items, item)
+	}
+
+	return json.Marshal(out)
+}
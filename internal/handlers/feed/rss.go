@@ -0,0 +1,96 @@
+package feedhandler
+
+import (
+	"encoding/xml"
+	"time"
+
+	feeddomain "github.com/codepnw/blog-api/internal/domains/feed"
+	"github.com/gofiber/fiber/v2"
+)
+
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	DCNS      string     `xml:"xmlns:dc,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Creators    []string `xml:"dc:creator"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"content:encoded,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func (h *handler) rss(ctx *fiber.Ctx, feed *feeddomain.Feed) ([]byte, error) {
+	channel := rssChannel{
+		Title:       feed.Title,
+		Link:        h.homeURL(ctx),
+		Description: feed.Description,
+		Self:        rssLink{Href: selfURL(ctx), Rel: "self", Type: "application/rss+xml"},
+		Items:       make([]rssItem, 0, len(feed.Posts)),
+	}
+	// The channel description is required.
+	if channel.Description == "" {
+		channel.Description = feed.Title
+	}
+	if !feed.Updated.IsZero() {
+		channel.LastBuildDate = feed.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, post := range feed.Posts {
+		item := rssItem{
+			Title:       post.Title,
+			Link:        h.postURL(ctx, post.ID),
+			GUID:        rssGUID{Value: "urn:uuid:" + post.ID},
+			Creators:    authorNames(post),
+			Description: post.Excerpt,
+			Content:     h.content(post),
+		}
+		if post.PublishedAt != nil {
+			item.PubDate = post.PublishedAt.UTC().Format(time.RFC1123Z)
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	return marshalXML(rssFeed{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		DCNS:      "http://purl.org/dc/elements/1.1/",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		Channel:   channel,
+	})
+}
+
+func marshalXML(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
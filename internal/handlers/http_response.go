@@ -40,6 +40,31 @@ func Forbidden(ctx *fiber.Ctx, message string) error {
 	return NewErrorResponse(ctx, http.StatusForbidden, "FORBIDDEN", message, nil)
 }
 
+func Conflict(ctx *fiber.Ctx, message string) error {
+	return NewErrorResponse(ctx, http.StatusConflict, "CONFLICT", message, nil)
+}
+
+// Locked answers a write to a resource someone else holds the lock of.
+func Locked(ctx *fiber.Ctx, message string) error {
+	return NewErrorResponse(ctx, http.StatusLocked, "LOCKED", message, nil)
+}
+
+func PreconditionFailed(ctx *fiber.Ctx, message string) error {
+	return NewErrorResponse(ctx, http.StatusPreconditionFailed, "PRECONDITION_FAILED", message, nil)
+}
+
+func PreconditionRequired(ctx *fiber.Ctx, message string) error {
+	return NewErrorResponse(ctx, http.StatusPreconditionRequired, "PRECONDITION_REQUIRED", message, nil)
+}
+
+func RequestEntityTooLarge(ctx *fiber.Ctx, message string) error {
+	return NewErrorResponse(ctx, http.StatusRequestEntityTooLarge, "REQUEST_ENTITY_TOO_LARGE", message, nil)
+}
+
+func UnsupportedMediaType(ctx *fiber.Ctx, message string) error {
+	return NewErrorResponse(ctx, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", message, nil)
+}
+
 func InternalServerError(ctx *fiber.Ctx, err error) error {
 	return NewErrorResponse(ctx, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "", err.Error())
 }
@@ -70,10 +95,20 @@ func Created(ctx *fiber.Ctx, data any) error {
 	return NewSuccessResponse(ctx, http.StatusCreated, "created successfully", data)
 }
 
+// Accepted answers a request whose work goes on in the background.
+func Accepted(ctx *fiber.Ctx, data any) error {
+	return NewSuccessResponse(ctx, http.StatusAccepted, "accepted", data)
+}
+
 func NoContent(ctx *fiber.Ctx) error {
 	return NewSuccessResponse(ctx, http.StatusNoContent, "no content", nil)
 }
 
+// NotModified answers a conditional GET whose cached copy is still current.
+func NotModified(ctx *fiber.Ctx) error {
+	return ctx.SendStatus(http.StatusNotModified)
+}
+
 // -------------- Swagger Response --------------
 
 type EmptyRes struct{}
@@ -94,6 +129,26 @@ type ForbiddenRes struct {
 	Message string `json:"message"`
 }
 
+type ConflictRes struct {
+	Message string `json:"message"`
+}
+
+type LockedRes struct {
+	Message string `json:"message"`
+}
+
+type PreconditionFailedRes struct {
+	Message string `json:"message"`
+}
+
+type RequestEntityTooLargeRes struct {
+	Message string `json:"message"`
+}
+
+type UnsupportedMediaTypeRes struct {
+	Message string `json:"message"`
+}
+
 type InternalServerErrRes struct {
 	Error string `json:"error"`
 }
@@ -0,0 +1,156 @@
+package mediahandler
+
+import (
+	"errors"
+	"io"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	mediausecase "github.com/codepnw/blog-api/internal/usecases/media"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const formKeyFile = "file"
+
+type handler struct {
+	uc      mediausecase.Usecase
+	maxSize int64
+}
+
+func NewMediaHandler(uc mediausecase.Usecase, maxSize int64) *handler {
+	return &handler{uc: uc, maxSize: maxSize}
+}
+
+// Upload Media
+// @Summary Upload Media
+// @Description The type is detected from the content. Resized and WebP variants are generated.
+// @Tags media
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "JPEG, PNG, GIF or WebP image"
+// @Success 201 {object} mediadomain.Media
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 413 {object} handlers.RequestEntityTooLargeRes
+// @Failure 415 {object} handlers.UnsupportedMediaTypeRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /media [post]
+func (h *handler) Upload(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	header, err := ctx.FormFile(formKeyFile)
+	if err != nil {
+		return handlers.BadRequest(ctx, "file is required")
+	}
+	if header.Size > h.maxSize {
+		return handlers.RequestEntityTooLarge(ctx, errs.ErrMediaTooLarge.Error())
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	defer file.Close()
+
+	// Never trust the declared size, read at most one byte past the limit.
+	data, err := io.ReadAll(io.LimitReader(file, h.maxSize+1))
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+
+	result, err := h.uc.Upload(ctx.Context(), user.UserID, header.Filename, data)
+	if err != nil {
+		return h.mediaError(ctx, err)
+	}
+	return handlers.Created(ctx, result)
+}
+
+// Get Media
+// @Summary Get Media
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param media_id path string true "Media ID"
+// @Success 200 {object} mediadomain.Media
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /media/{media_id} [get]
+func (h *handler) GetByID(ctx *fiber.Ctx) error {
+	id := ctx.Params(handlers.ParamKeyMediaID)
+
+	result, err := h.uc.GetByID(ctx.Context(), id)
+	if err != nil {
+		return h.mediaError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Get My Media
+// @Summary Get My Media
+// @Tags media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} []mediadomain.Media
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /media [get]
+func (h *handler) GetMine(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.GetByUploader(ctx.Context(), user.UserID)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Delete Media
+// @Summary Delete Media
+// @Description Posts using it as cover image are left without one.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param media_id path string true "Media ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /media/{media_id} [delete]
+func (h *handler) Delete(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	id := ctx.Params(handlers.ParamKeyMediaID)
+	if err := h.uc.Delete(ctx.Context(), id, user.UserID, user.Role); err != nil {
+		return h.mediaError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+func (h *handler) mediaError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrMediaNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrMediaNotOwner):
+		return handlers.Forbidden(ctx, err.Error())
+	case errors.Is(err, errs.ErrMediaTooLarge):
+		return handlers.RequestEntityTooLarge(ctx, err.Error())
+	case errors.Is(err, errs.ErrMediaUnsupportedType):
+		return handlers.UnsupportedMediaType(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
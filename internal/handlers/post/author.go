@@ -0,0 +1,80 @@
+package posthandler
+
+import (
+	"errors"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/validate"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Set Post Authors
+// @Summary Set Post Authors
+// @Description Replaces the bylines. Co-authors can edit the post; only the owner and admins can change its authors.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being edited"
+// @Param data body posthandler.PostAuthorsReq true "Bylines in order"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
+// @Failure 423 {object} handlers.LockedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/authors [put]
+func (h *handler) SetAuthors(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	req := new(PostAuthorsReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkOwner(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	authors := make([]postdomain.Byline, 0, len(req.Authors))
+	for _, a := range req.Authors {
+		authors = append(authors, postdomain.Byline{UserID: a.UserID, Role: a.Role})
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.SetAuthors(ctx.Context(), postID, authors, version, user.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrUserNotFound),
+			errors.Is(err, errs.ErrPostOwnerRequired):
+			return handlers.BadRequest(ctx, err.Error())
+		default:
+			return h.statusError(ctx, err)
+		}
+	}
+
+	handlers.SetETag(ctx, result.Version)
+	return handlers.Success(ctx, result)
+}
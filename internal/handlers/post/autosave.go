@@ -0,0 +1,175 @@
+package posthandler
+
+import (
+	"errors"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/validate"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Autosave Post
+// @Summary Autosave Post
+// @Description Keeps the unsaved edits of the current user to a post, replacing their previous autosave. The post itself doesn't change until the autosave is applied.
+// @Tags autosaves
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version the edits started from. Left out, an existing autosave keeps the version it started from"
+// @Param data body posthandler.AutosaveReq true "Unsaved edits"
+// @Success 200 {object} postdomain.Autosave
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/autosave [put]
+func (h *handler) SaveAutosave(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	req := new(AutosaveReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.SaveAutosave(ctx.Context(), &postdomain.Autosave{
+		PostID:        postID,
+		UserID:        user.UserID,
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: req.Format,
+		BaseVersion:   version,
+	})
+	if err != nil {
+		return h.autosaveError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Get Autosave
+// @Summary Get Autosave
+// @Description Returns the autosave of the current user for a post.
+// @Tags autosaves
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {object} postdomain.Autosave
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/autosave [get]
+func (h *handler) GetAutosave(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.GetAutosave(ctx.Context(), ctx.Params(handlers.ParamKeyPostID), user.UserID)
+	if err != nil {
+		return h.autosaveError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Discard Autosave
+// @Summary Discard Autosave
+// @Tags autosaves
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/autosave [delete]
+func (h *handler) DiscardAutosave(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	if err := h.uc.DiscardAutosave(ctx.Context(), ctx.Params(handlers.ParamKeyPostID), user.UserID); err != nil {
+		return h.autosaveError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+// Apply Autosave
+// @Summary Apply Autosave
+// @Description Saves the autosave of the current user into the post as an edit, then discards it. Fails with 412 when the post changed since the autosave started, and with 423 while another user holds the edit lock.
+// @Tags autosaves
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param data body posthandler.ApplyAutosaveReq false "Revision note"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
+// @Failure 423 {object} handlers.LockedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/autosave/apply [post]
+func (h *handler) ApplyAutosave(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	req := new(ApplyAutosaveReq)
+	if err := parseOptional(ctx, req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.ApplyAutosave(ctx.Context(), postID, user.UserID, user.Role, req.Note)
+	if err != nil {
+		return h.autosaveError(ctx, err)
+	}
+
+	handlers.SetETag(ctx, result.Version)
+	return handlers.Success(ctx, result)
+}
+
+func (h *handler) autosaveError(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, errs.ErrAutosaveNotFound) {
+		return handlers.NotFound(ctx, err.Error())
+	}
+	return h.statusError(ctx, err)
+}
@@ -0,0 +1,127 @@
+package posthandler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Lock Post
+// @Summary Lock Post
+// @Description Takes the edit lock of a post, or renews it for its holder. While it lasts, edits by anyone else fail with 423. Locks expire on their own unless renewed.
+// @Tags locks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param data body posthandler.LockReq false "Lock lifetime"
+// @Success 200 {object} postdomain.Lock
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 423 {object} handlers.LockedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/lock [post]
+func (h *handler) Lock(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	req := new(LockReq)
+	if err := parseOptional(ctx, req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	result, err := h.uc.Lock(ctx.Context(), postID, user.UserID, ttl)
+	if err != nil {
+		return h.lockError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Get Post Lock
+// @Summary Get Post Lock
+// @Description Shows who holds the edit lock of a post and until when.
+// @Tags locks
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {object} postdomain.Lock
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/lock [get]
+func (h *handler) GetLock(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	result, err := h.uc.GetLock(ctx.Context(), postID)
+	if err != nil {
+		return h.lockError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Unlock Post
+// @Summary Unlock Post
+// @Description Releases the edit lock of a post. Only its holder can, or an admin breaking it.
+// @Tags locks
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/lock [delete]
+func (h *handler) Unlock(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	if err := h.uc.Unlock(ctx.Context(), ctx.Params(handlers.ParamKeyPostID), user.UserID, user.Role); err != nil {
+		return h.lockError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+func (h *handler) lockError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound),
+		errors.Is(err, errs.ErrLockNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrPostLocked):
+		return handlers.Locked(ctx, err.Error())
+	case errors.Is(err, errs.ErrLockNotHeld):
+		return handlers.Forbidden(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
@@ -0,0 +1,146 @@
+package posthandler
+
+import (
+	"errors"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/validate"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Get Featured Posts
+// @Summary Get Featured Posts
+// @Description The pinned posts of the homepage, or of a category, in pin order. Expired pins and unpublished posts are left out.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param category_id query string false "Category ID"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Success 200 {array} []postdomain.Summary
+// @Success 304 {object} handlers.EmptyRes
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/featured [get]
+func (h *handler) GetFeatured(ctx *fiber.Ctx) error {
+	categoryID, err := pinCategory(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	result, err := h.uc.GetFeatured(ctx.Context(), categoryID)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	if err := h.localizeList(ctx, result); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	if err := h.setViewerState(ctx, result); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+
+	if handlers.Fresh(ctx, postsETag(result), time.Time{}) {
+		return handlers.NotModified(ctx)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Pin Post
+// @Summary Pin Post
+// @Description Features a post on the homepage, or on a category page. Pinning it again moves it and replaces the expiry. Editors and admins only.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param data body posthandler.PinReq true "Pin data"
+// @Success 200 {object} postdomain.Pin
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/pin [put]
+func (h *handler) Pin(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(PinReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	input := &postdomain.Pin{
+		PostID:    ctx.Params(handlers.ParamKeyPostID),
+		Position:  req.Position,
+		ExpiresAt: req.ExpiresAt,
+		PinnedBy:  &user.UserID,
+	}
+	if req.CategoryID != "" {
+		input.CategoryID = &req.CategoryID
+	}
+
+	result, err := h.uc.Pin(ctx.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrPostNotFound):
+			return handlers.NotFound(ctx, err.Error())
+		case errors.Is(err, errs.ErrCategoryNotFound),
+			errors.Is(err, errs.ErrPinExpiryInPast):
+			return handlers.BadRequest(ctx, err.Error())
+		default:
+			return handlers.InternalServerError(ctx, err)
+		}
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Unpin Post
+// @Summary Unpin Post
+// @Description Removes a post from the homepage, or from a category page. Editors and admins only.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param category_id query string false "Category ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/pin [delete]
+func (h *handler) Unpin(ctx *fiber.Ctx) error {
+	categoryID, err := pinCategory(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	if err := h.uc.Unpin(ctx.Context(), ctx.Params(handlers.ParamKeyPostID), categoryID); err != nil {
+		if errors.Is(err, errs.ErrPinNotFound) {
+			return handlers.NotFound(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+// pinCategory reads the category_id query parameter. An empty one means the
+// homepage.
+func pinCategory(ctx *fiber.Ctx) (string, error) {
+	categoryID := ctx.Query(handlers.ParamKeyCategoryID)
+	if categoryID != "" && uuid.Validate(categoryID) != nil {
+		return "", errors.New("category_id must be a UUID")
+	}
+	return categoryID, nil
+}
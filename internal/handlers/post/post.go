@@ -1,12 +1,25 @@
 package posthandler
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/codepnw/blog-api/internal/config"
 	postdomain "github.com/codepnw/blog-api/internal/domains/post"
 	"github.com/codepnw/blog-api/internal/handlers"
 	"github.com/codepnw/blog-api/internal/middleware"
+	analyticsusecase "github.com/codepnw/blog-api/internal/usecases/analytics"
+	bookmarkusecase "github.com/codepnw/blog-api/internal/usecases/bookmark"
 	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	reactionusecase "github.com/codepnw/blog-api/internal/usecases/reaction"
+	seriesusecase "github.com/codepnw/blog-api/internal/usecases/series"
+	transferusecase "github.com/codepnw/blog-api/internal/usecases/transfer"
 	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
 	"github.com/codepnw/blog-api/internal/utils/errs"
 	"github.com/codepnw/blog-api/internal/utils/validate"
@@ -14,15 +27,40 @@ import (
 )
 
 type handler struct {
-	uc postusecase.Usecase
+	uc        postusecase.Usecase
+	analytics analyticsusecase.Usecase
+	reactions reactionusecase.Usecase
+	bookmarks bookmarkusecase.Usecase
+	series    seriesusecase.Usecase
+	locales   []string
+	siteURL   string
+	apiPrefix string
 }
 
-func NewPostHandler(uc postusecase.Usecase) *handler {
-	return &handler{uc: uc}
+func NewPostHandler(
+	uc postusecase.Usecase,
+	analytics analyticsusecase.Usecase,
+	reactions reactionusecase.Usecase,
+	bookmarks bookmarkusecase.Usecase,
+	series seriesusecase.Usecase,
+	locales config.LocaleConfig,
+	siteURL, apiPrefix string,
+) *handler {
+	return &handler{
+		uc:        uc,
+		analytics: analytics,
+		reactions: reactions,
+		bookmarks: bookmarks,
+		series:    series,
+		locales:   locales.Supported,
+		siteURL:   siteURL,
+		apiPrefix: apiPrefix,
+	}
 }
 
 // Create Post
 // @Summary Create Post
+// @Description Authors who aren't editors or admins can only create drafts, which go through review before they are published.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -31,6 +69,8 @@ func NewPostHandler(uc postusecase.Usecase) *handler {
 // @Success 201 {object} postdomain.Post
 // @Failure 400 {object} handlers.BadRequestRes
 // @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 409 {object} handlers.ConflictRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /posts [post]
 func (h *handler) Create(ctx *fiber.Ctx) error {
@@ -47,34 +87,86 @@ func (h *handler) Create(ctx *fiber.Ctx) error {
 		return handlers.BadRequest(ctx, err.Error())
 	}
 
+	if req.Locale != "" && !slices.Contains(h.locales, req.Locale) {
+		return handlers.BadRequest(ctx, errs.ErrUnsupportedLocale.Error())
+	}
+
 	input := &postdomain.Post{
-		AuthorID:   user.UserID,
-		Title:      req.Title,
-		Content:    req.Content,
-		CategoryID: &req.CategoryID,
+		AuthorID:      user.UserID,
+		Locale:        req.Locale,
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: req.Format,
+		CategoryID:    &req.CategoryID,
+		CoverMediaID:  &req.CoverMediaID,
+		Status:        req.Status,
+		Visibility:    req.Visibility,
+		PublishedAt:   req.PublishedAt,
+	}
+	if slug := transferusecase.Slugify(req.Slug); slug != "" {
+		input.Slug = &slug
 	}
 
-	result, err := h.uc.Create(ctx.Context(), input)
+	result, err := h.uc.Create(ctx.Context(), input, user.Role)
 	if err != nil {
-		return handlers.InternalServerError(ctx, err)
+		return h.statusError(ctx, err)
 	}
 
+	handlers.SetETag(ctx, result.Version)
 	return handlers.Created(ctx, result)
 }
 
 // Get Post By ID
 // @Summary Get Post By ID
+// @Description Unpublished and private posts are only visible to their authors and admins, and anonymous readers get a teaser of members-only posts, without the content. The post is served in the first language of lang and Accept-Language it is available in, or else in its own.
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param post_id path string true "Post ID"
+// @Param lang query string false "Preferred locale, e.g. th"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param If-None-Match header string false "ETag of the cached copy"
 // @Success 200 {object} postdomain.Post
+// @Success 304 {object} handlers.EmptyRes
 // @Failure 404 {object} handlers.NotFoundRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /posts/{post_id} [get]
 func (h *handler) GetByID(ctx *fiber.Ctx) error {
-	postID := ctx.Params(handlers.ParamKeyPostID)
+	return h.serve(ctx, ctx.Params(handlers.ParamKeyPostID), h.localeChain(ctx))
+}
+
+// Get Post By Slug
+// @Summary Get Post By Slug
+// @Description Same as Get Post By ID, for the post with this slug. The slug of a translation serves the post in the language of the translation.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param slug path string true "Post slug"
+// @Param lang query string false "Preferred locale, e.g. th"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Success 200 {object} postdomain.Post
+// @Success 304 {object} handlers.EmptyRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/by-slug/{slug} [get]
+func (h *handler) GetBySlug(ctx *fiber.Ctx) error {
+	chain := h.localeChain(ctx)
+	postID, lang, err := h.uc.ResolveSlug(ctx.Context(), ctx.Params(handlers.ParamKeySlug), chain)
+	if err != nil {
+		if errors.Is(err, errs.ErrPostNotFound) {
+			return handlers.NotFound(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+	return h.serve(ctx, postID, append([]string{lang}, chain...))
+}
 
+// serve answers a read of a single post in the first locale of chain it is
+// available in, and counts it as a view.
+func (h *handler) serve(ctx *fiber.Ctx, postID string, chain []string) error {
 	result, err := h.uc.GetByID(ctx.Context(), postID)
 	if err != nil {
 		if errors.Is(err, errs.ErrPostNotFound) {
@@ -82,59 +174,236 @@ func (h *handler) GetByID(ctx *fiber.Ctx) error {
 		}
 		return handlers.InternalServerError(ctx, err)
 	}
+	if !h.canView(ctx, result) {
+		return handlers.NotFound(ctx, errs.ErrPostNotFound.Error())
+	}
+	h.recordView(ctx, result)
 
+	if err := h.localize(ctx, result, chain); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	h.restrict(ctx, result)
+
+	var viewerID, role string
+	if user, err := middleware.GetCurrentUser(ctx); err == nil {
+		viewerID, role = user.UserID, user.Role
+		mine, bookmarked, err := h.viewerState(ctx, user.UserID, []string{result.ID})
+		if err != nil {
+			return handlers.InternalServerError(ctx, err)
+		}
+		result.MyReactions = mine[result.ID]
+		result.Bookmarked = bookmarked[result.ID]
+	}
+
+	if result.Series, err = h.series.GetNav(ctx.Context(), result, viewerID, role); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+
+	state := viewerETagState(result.Reactions, result.MyReactions, result.Bookmarked) + seriesETagState(result.Series) + result.Locale
+	if result.Teaser {
+		state += "|teaser"
+	}
+	etag := handlers.StateETag(result.Version, state)
+	if handlers.Fresh(ctx, etag, lastModified(result.UpdatedAt, result.ReactedAt)) {
+		return handlers.NotModified(ctx)
+	}
 	return handlers.Success(ctx, result)
 }
 
 // Get Post By User
 // @Summary Get Post By User
+// @Description Includes co-authored posts. Authors and admins also see drafts, scheduled, archived, unlisted and private posts.
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param user_id path string true "User ID"
-// @Success 200 {array} []postdomain.Post
+// @Param lang query string false "Only posts available in this locale, served in it"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Success 200 {array} []postdomain.Summary
+// @Success 304 {object} handlers.EmptyRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /users/{user_id}/posts [get]
 func (h *handler) GetByUserID(ctx *fiber.Ctx) error {
 	authorID := ctx.Params(handlers.ParamKeyAuthorID)
 
-	result, err := h.uc.GetByAuthorID(ctx.Context(), authorID)
+	publishedOnly := true
+	if user, err := middleware.GetCurrentUser(ctx); err == nil {
+		publishedOnly = user.UserID != authorID && user.Role != string(userusecase.RoleAdmin)
+	}
+
+	lang, err := h.listLocale(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	result, err := h.uc.GetByAuthorID(ctx.Context(), authorID, publishedOnly, lang)
 	if err != nil {
 		return handlers.InternalServerError(ctx, err)
 	}
+	if err := h.localizeList(ctx, result); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	if err := h.setViewerState(ctx, result); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
 
+	if handlers.Fresh(ctx, postsETag(result), time.Time{}) {
+		return handlers.NotModified(ctx)
+	}
 	return handlers.Success(ctx, result)
 }
 
 // Get Posts
 // @Summary Get Posts
+// @Description Returns published posts that are public or members-only, plus all of the current user's own posts.
 // @Tags posts
 // @Accept json
 // @Produce json
-// @Success 200 {array} []postdomain.Post
+// @Security BearerAuth
+// @Param pinned_first query bool false "Put the homepage's pinned posts first"
+// @Param lang query string false "Only posts available in this locale, served in it"
+// @Param Accept-Language header string false "Preferred locales"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Success 200 {array} []postdomain.Summary
+// @Success 304 {object} handlers.EmptyRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /posts [get]
 func (h *handler) GetAll(ctx *fiber.Ctx) error {
-	result, err := h.uc.GetAll(ctx.Context())
+	lang, err := h.listLocale(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	filter := postdomain.ListFilter{Locale: lang}
+	if user, err := middleware.GetCurrentUser(ctx); err == nil {
+		filter.ViewerID = user.UserID
+	}
+
+	result, err := h.uc.GetAll(ctx.Context(), filter, ctx.QueryBool("pinned_first"))
 	if err != nil {
 		return handlers.InternalServerError(ctx, err)
 	}
+	if err := h.localizeList(ctx, result); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	if err := h.setViewerState(ctx, result); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
 
+	if handlers.Fresh(ctx, postsETag(result), time.Time{}) {
+		return handlers.NotModified(ctx)
+	}
 	return handlers.Success(ctx, result)
 }
 
+// postsETag covers the membership and versions of a post list. Lists get no
+// Last-Modified, since removing a post doesn't move the newest updated_at.
+func postsETag(posts []*postdomain.Summary) string {
+	items := make([]handlers.Validator, 0, len(posts))
+	for _, p := range posts {
+		items = append(items, handlers.Validator{
+			ID:      p.ID,
+			Version: p.Version,
+			State:   viewerETagState(p.Reactions, p.MyReactions, p.Bookmarked) + p.Locale,
+		})
+	}
+	return handlers.CollectionETag(items)
+}
+
+// setViewerState fills in the current user's reactions and bookmarks on a
+// list of posts.
+func (h *handler) setViewerState(ctx *fiber.Ctx, posts []*postdomain.Summary) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil || len(posts) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+	mine, bookmarked, err := h.viewerState(ctx, user.UserID, ids)
+	if err != nil {
+		return err
+	}
+	for _, p := range posts {
+		p.MyReactions = mine[p.ID]
+		p.Bookmarked = bookmarked[p.ID]
+	}
+	return nil
+}
+
+func (h *handler) viewerState(ctx *fiber.Ctx, userID string, postIDs []string) (map[string][]string, map[string]bool, error) {
+	mine, err := h.reactions.GetMine(ctx.Context(), userID, postIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	bookmarked, err := h.bookmarks.GetBookmarked(ctx.Context(), userID, postIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mine, bookmarked, nil
+}
+
+// viewerETagState encodes reaction counts and the user's own reactions and
+// bookmark for the entity tag, since none of them change the post version.
+func viewerETagState(counts postdomain.Reactions, mine []string, bookmarked bool) string {
+	if len(counts) == 0 && len(mine) == 0 && !bookmarked {
+		return ""
+	}
+
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	var sb strings.Builder
+	for _, t := range types {
+		fmt.Fprintf(&sb, "%s=%d,", t, counts[t])
+	}
+	fmt.Fprintf(&sb, "|%s|%t", strings.Join(mine, ","), bookmarked)
+	return sb.String()
+}
+
+// seriesETagState covers the series navigation, which changes when other
+// parts are edited or reordered.
+func seriesETagState(nav *postdomain.SeriesNav) string {
+	if nav == nil {
+		return ""
+	}
+	b, _ := json.Marshal(nav)
+	return string(b)
+}
+
+// lastModified is the later of the last edit and the last reaction.
+func lastModified(updatedAt time.Time, reactedAt *time.Time) time.Time {
+	if reactedAt != nil && reactedAt.After(updatedAt) {
+		return *reactedAt
+	}
+	return updatedAt
+}
+
 // Edit Post
 // @Summary Edit Post
+// @Description Title or content edits by anyone but an editor or admin take a post in review, approved, scheduled or published back to draft, to be reviewed again. Fails with 423 while another user holds the edit lock of the post.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param post_id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being edited"
 // @Param data body posthandler.PostUpdateReq true "Post data"
 // @Success 200 {object} postdomain.Post
 // @Failure 400 {object} handlers.BadRequestRes
 // @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
 // @Failure 404 {object} handlers.NotFoundRes
+// @Failure 409 {object} handlers.ConflictRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
+// @Failure 423 {object} handlers.LockedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /posts/{post_id} [patch]
 func (h *handler) Update(ctx *fiber.Ctx) error {
@@ -148,67 +417,211 @@ func (h *handler) Update(ctx *fiber.Ctx) error {
 		return handlers.BadRequest(ctx, err.Error())
 	}
 
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
 	ok, err := h.checkPermissions(ctx, postID)
 	if err != nil {
-		switch {
-		case errors.Is(err, errs.ErrUserUnauthorized):
-			return handlers.Unauthorized(ctx, err.Error())
-		case errors.Is(err, errs.ErrPostNotFound):
-			return handlers.NotFound(ctx, err.Error())
-		default:
-			return handlers.InternalServerError(ctx, err)
-		}
+		return h.permissionError(ctx, err)
 	}
 	if !ok {
 		return handlers.Forbidden(ctx, "no permissions")
 	}
 
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	var note string
+	if req.Note != nil {
+		note = *req.Note
+	}
+
 	input := h.validateUpdate(postID, req)
-	result, err := h.uc.Update(ctx.Context(), input)
+	input.Version = version
+
+	result, err := h.uc.Update(ctx.Context(), input, user.UserID, user.Role, note)
 	if err != nil {
-		return handlers.InternalServerError(ctx, err)
+		return h.statusError(ctx, err)
 	}
 
+	handlers.SetETag(ctx, result.Version)
 	return handlers.Success(ctx, result)
 }
 
 // Delete Post
 // @Summary Delete Post
+// @Description Only the owner of the post and admins can delete it.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param post_id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 204 {object} handlers.EmptyRes
 // @Failure 401 {object} handlers.UnauthorizedRes
 // @Failure 404 {object} handlers.NotFoundRes
 // @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /posts/{post_id} [delete]
 func (h *handler) Delete(ctx *fiber.Ctx) error {
 	postID := ctx.Params(handlers.ParamKeyPostID)
 
-	ok, err := h.checkPermissions(ctx, postID)
+	version, err := handlers.IfMatchVersion(ctx)
 	if err != nil {
-		switch {
-		case errors.Is(err, errs.ErrUserUnauthorized):
-			return handlers.Unauthorized(ctx, err.Error())
-		case errors.Is(err, errs.ErrPostNotFound):
-			return handlers.NotFound(ctx, err.Error())
-		default:
-			return handlers.InternalServerError(ctx, err)
-		}
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkOwner(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
 	}
 	if !ok {
 		return handlers.Forbidden(ctx, "no permissions")
 	}
 
-	if err := h.uc.Delete(ctx.Context(), postID); err != nil {
-		return handlers.InternalServerError(ctx, err)
+	if err := h.uc.Delete(ctx.Context(), postID, version); err != nil {
+		return h.statusError(ctx, err)
 	}
 	return handlers.NoContent(ctx)
 }
 
+// Publish Post
+// @Summary Publish Post
+// @Description Publishes the post now, or schedules it when publish_at is set. Authors who aren't editors or admins can only publish approved posts. Fails with 409 from an archived post for them.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being published"
+// @Param data body posthandler.PostPublishReq false "Schedule"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 409 {object} handlers.ConflictRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/publish [post]
+func (h *handler) Publish(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	req := new(PostPublishReq)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(req); err != nil {
+			return handlers.BadRequest(ctx, err.Error())
+		}
+	}
+
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.Publish(ctx.Context(), postID, req.PublishAt, version, user.UserID, user.Role)
+	if err != nil {
+		return h.reviewError(ctx, err)
+	}
+
+	handlers.SetETag(ctx, result.Version)
+	return handlers.Success(ctx, result)
+}
+
+// Unpublish Post
+// @Summary Unpublish Post
+// @Description Moves a published, scheduled or archived post back to draft. Fails with 409 from any other status.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being unpublished"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 409 {object} handlers.ConflictRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/unpublish [post]
+func (h *handler) Unpublish(ctx *fiber.Ctx) error {
+	return h.lifecycle(ctx, h.uc.Unpublish)
+}
+
+// Archive Post
+// @Summary Archive Post
+// @Description Only published posts can be archived; anything else fails with 409.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param If-Match header string false "ETag of the version being archived"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 409 {object} handlers.ConflictRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/archive [post]
+func (h *handler) Archive(ctx *fiber.Ctx) error {
+	return h.lifecycle(ctx, h.uc.Archive)
+}
+
+// lifecycle runs a step of the post lifecycle that takes no input but the
+// version being changed.
+func (h *handler) lifecycle(ctx *fiber.Ctx, step func(ctx context.Context, id string, version int, userID string) (*postdomain.Post, error)) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := step(ctx.Context(), postID, version, user.UserID)
+	if err != nil {
+		return h.reviewError(ctx, err)
+	}
+
+	handlers.SetETag(ctx, result.Version)
+	return handlers.Success(ctx, result)
+}
+
 func (h *handler) validateUpdate(postID string, req *PostUpdateReq) *postdomain.Post {
 	newPost := new(postdomain.Post)
 	if req.Title != nil {
@@ -217,15 +630,45 @@ func (h *handler) validateUpdate(postID string, req *PostUpdateReq) *postdomain.
 	if req.Content != nil {
 		newPost.Content = *req.Content
 	}
+	if req.Format != nil {
+		newPost.ContentFormat = *req.Format
+	}
 	if req.CategoryID != nil {
 		newPost.CategoryID = req.CategoryID
 	}
+	if req.CoverMediaID != nil {
+		newPost.CoverMediaID = req.CoverMediaID
+	}
+	if req.Slug != nil {
+		// Left empty by a slug without a letter or digit in it, which then
+		// leaves the slug alone rather than removing it.
+		if slug := transferusecase.Slugify(*req.Slug); slug != "" || *req.Slug == "" {
+			newPost.Slug = &slug
+		}
+	}
+	if req.Visibility != nil {
+		newPost.Visibility = *req.Visibility
+	}
 	newPost.ID = postID
 
 	return newPost
 }
 
+// checkPermissions reports whether the current user may edit the post: any
+// of its authors, or an admin.
 func (h *handler) checkPermissions(ctx *fiber.Ctx, postID string) (bool, error) {
+	return h.checkAccess(ctx, postID, postusecase.IsAuthor)
+}
+
+// checkOwner reports whether the current user owns the post or is an admin.
+// Deleting a post and changing its authors is left to them.
+func (h *handler) checkOwner(ctx *fiber.Ctx, postID string) (bool, error) {
+	return h.checkAccess(ctx, postID, func(post *postdomain.Post, userID string) bool {
+		return post.AuthorID == userID
+	})
+}
+
+func (h *handler) checkAccess(ctx *fiber.Ctx, postID string, allowed func(*postdomain.Post, string) bool) (bool, error) {
 	user, err := middleware.GetCurrentUser(ctx)
 	if err != nil {
 		return false, errs.ErrUserUnauthorized
@@ -236,8 +679,68 @@ func (h *handler) checkPermissions(ctx *fiber.Ctx, postID string) (bool, error)
 		return false, errs.ErrPostNotFound
 	}
 
-	if post.AuthorID == user.UserID || user.Role == string(userusecase.RoleAdmin) {
+	if allowed(post, user.UserID) || user.Role == string(userusecase.RoleAdmin) {
 		return true, nil
 	}
 	return false, nil
 }
+
+// recordView counts a read of a published post, unless it is one of the
+// authors looking at their own post.
+func (h *handler) recordView(ctx *fiber.Ctx, post *postdomain.Post) {
+	if post.Status != string(postusecase.StatusPublished) {
+		return
+	}
+	if user, err := middleware.GetCurrentUser(ctx); err == nil && postusecase.IsAuthor(post, user.UserID) {
+		return
+	}
+	h.analytics.RecordView(post.ID, ctx.IP(), ctx.Get(fiber.HeaderUserAgent), ctx.Get(fiber.HeaderReferer))
+}
+
+// canView reports whether the current user may open the post. Published
+// posts are reachable unless private, anything else is limited to its
+// authors and admins, and to editors while it is under review.
+func (h *handler) canView(ctx *fiber.Ctx, post *postdomain.Post) bool {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return postusecase.CanRead(post, "")
+	}
+	if postusecase.InReview(post.Status) && postusecase.IsReviewer(user.Role) {
+		return true
+	}
+	return postusecase.CanRead(post, user.UserID) || user.Role == string(userusecase.RoleAdmin)
+}
+
+func (h *handler) permissionError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrUserUnauthorized):
+		return handlers.Unauthorized(ctx, err.Error())
+	case errors.Is(err, errs.ErrPostNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
+
+func (h *handler) statusError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrPostInvalidStatus),
+		errors.Is(err, errs.ErrPostScheduleInPast),
+		errors.Is(err, errs.ErrPostInvalidFormat),
+		errors.Is(err, errs.ErrMediaNotFound):
+		return handlers.BadRequest(ctx, err.Error())
+	case errors.Is(err, errs.ErrPostSlugTaken):
+		return handlers.Conflict(ctx, err.Error())
+	case errors.Is(err, errs.ErrVersionConflict):
+		return handlers.PreconditionFailed(ctx, err.Error())
+	case errors.Is(err, errs.ErrReviewRequired),
+		errors.Is(err, errs.ErrMediaNotOwner):
+		return handlers.Forbidden(ctx, err.Error())
+	case errors.Is(err, errs.ErrPostLocked):
+		return handlers.Locked(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
@@ -0,0 +1,55 @@
+package posthandler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultRelatedLimit = 5
+
+// Get Related Posts
+// @Summary Get Related Posts
+// @Description Published posts sharing the category, tags or wording of a published post, best match first. Recomputed in the background shortly after posts change.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param post_id path string true "Post ID"
+// @Param limit query int false "Number of posts, 1 to 10 (default 5)"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Success 200 {array} []postdomain.Summary
+// @Success 304 {object} handlers.EmptyRes
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/related [get]
+func (h *handler) GetRelated(ctx *fiber.Ctx) error {
+	limit := ctx.QueryInt("limit", defaultRelatedLimit)
+	if limit < 1 || limit > postusecase.MaxRelated {
+		return handlers.BadRequest(ctx, "limit must be between 1 and 10")
+	}
+
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	result, err := h.uc.GetRelated(ctx.Context(), postID, limit)
+	if err != nil {
+		if errors.Is(err, errs.ErrPostNotFound) {
+			return handlers.NotFound(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+	if err := h.localizeList(ctx, result); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	if err := h.setViewerState(ctx, result); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+
+	if handlers.Fresh(ctx, postsETag(result), time.Time{}) {
+		return handlers.NotModified(ctx)
+	}
+	return handlers.Success(ctx, result)
+}
@@ -1,13 +1,88 @@
 package posthandler
 
+import "time"
+
 type PostCreateReq struct {
-	Title      string `json:"title" validate:"required"`
-	Content    string `json:"content,omitempty" validate:"omitempty"`
-	CategoryID string `json:"category_id,omitempty" validate:"omitempty"`
+	Title        string     `json:"title" validate:"required"`
+	Slug         string     `json:"slug,omitempty" validate:"omitempty,max=200"`
+	Locale       string     `json:"locale,omitempty" validate:"omitempty"`
+	Content      string     `json:"content,omitempty" validate:"omitempty"`
+	Format       string     `json:"content_format,omitempty" validate:"omitempty,oneof=markdown html plaintext"`
+	CategoryID   string     `json:"category_id,omitempty" validate:"omitempty"`
+	CoverMediaID string     `json:"cover_media_id,omitempty" validate:"omitempty,uuid"`
+	Status       string     `json:"status,omitempty" validate:"omitempty,oneof=draft scheduled published"`
+	PublishedAt  *time.Time `json:"published_at,omitempty" validate:"required_if=Status scheduled"`
+	Visibility   string     `json:"visibility,omitempty" validate:"omitempty,oneof=public unlisted members private"`
 }
 
 type PostUpdateReq struct {
-	Title      *string `json:"title,omitempty" validate:"omitempty"`
+	Title *string `json:"title,omitempty" validate:"omitempty"`
+	// Slug sets the slug of the post, an empty string removes it.
+	Slug       *string `json:"slug,omitempty" validate:"omitempty,max=200"`
 	Content    *string `json:"content,omitempty" validate:"omitempty"`
+	Format     *string `json:"content_format,omitempty" validate:"omitempty,oneof=markdown html plaintext"`
 	CategoryID *string `json:"category_id,omitempty" validate:"omitempty"`
+	// CoverMediaID sets the cover image, an empty string removes it.
+	CoverMediaID *string `json:"cover_media_id,omitempty" validate:"omitempty,len=0|uuid"`
+	Visibility   *string `json:"visibility,omitempty" validate:"omitempty,oneof=public unlisted members private"`
+	// Note describes the change in the revision history.
+	Note *string `json:"note,omitempty" validate:"omitempty,max=255"`
+}
+
+type PostPublishReq struct {
+	// PublishAt schedules the post instead of publishing it right away.
+	PublishAt *time.Time `json:"publish_at,omitempty" validate:"omitempty"`
+}
+
+type ReviewReq struct {
+	Comment string `json:"comment,omitempty" validate:"omitempty,max=5000"`
+}
+
+type RejectReq struct {
+	// Comment tells the authors what to change.
+	Comment string `json:"comment" validate:"required,max=5000"`
+}
+
+type PostAuthorsReq struct {
+	// Authors lists every byline in the order they are credited, including
+	// the owner of the post.
+	Authors []BylineReq `json:"authors" validate:"required,min=1,max=20,unique=UserID,dive"`
+}
+
+type BylineReq struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+	Role   string `json:"role" validate:"required,oneof=author contributor editor"`
+}
+
+type PinReq struct {
+	// CategoryID pins the post on the category page instead of the homepage.
+	CategoryID string `json:"category_id,omitempty" validate:"omitempty,uuid"`
+	// Position places the pin, 1 being first. Left out, the post goes after
+	// the existing pins.
+	Position  int        `json:"position,omitempty" validate:"omitempty,min=1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" validate:"omitempty"`
+}
+
+type TranslationReq struct {
+	Title   string  `json:"title" validate:"required,max=200"`
+	Slug    *string `json:"slug,omitempty" validate:"omitempty,max=200"`
+	Content string  `json:"content,omitempty" validate:"omitempty"`
+	Format  string  `json:"content_format,omitempty" validate:"omitempty,oneof=markdown html plaintext"`
+}
+
+type LockReq struct {
+	// TTLSeconds is how long the lock lasts unless renewed, two minutes by
+	// default.
+	TTLSeconds int `json:"ttl_seconds,omitempty" validate:"omitempty,min=10,max=900"`
+}
+
+type AutosaveReq struct {
+	Title   *string `json:"title,omitempty" validate:"omitempty"`
+	Content *string `json:"content,omitempty" validate:"omitempty"`
+	Format  *string `json:"content_format,omitempty" validate:"omitempty,oneof=markdown html plaintext"`
+}
+
+type ApplyAutosaveReq struct {
+	// Note describes the change in the revision history.
+	Note string `json:"note,omitempty" validate:"omitempty,max=255"`
 }
@@ -0,0 +1,188 @@
+package posthandler
+
+import (
+	"errors"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/validate"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Submit Post For Review
+// @Summary Submit Post For Review
+// @Description Sends a draft, or a post that changes were requested on, to the editors. Any of its authors, or an admin, can submit it.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param data body posthandler.ReviewReq false "Note for the reviewers"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 409 {object} handlers.ConflictRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/submit [post]
+func (h *handler) Submit(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	req := new(ReviewReq)
+	if err := parseOptional(ctx, req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.Submit(ctx.Context(), postID, user.UserID, req.Comment)
+	if err != nil {
+		return h.reviewError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Approve Post
+// @Summary Approve Post
+// @Description Approves a post in review, so that its authors can publish it. Editors and admins only, and editors not on their own posts.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param data body posthandler.ReviewReq false "Comment for the authors"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 409 {object} handlers.ConflictRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/approve [post]
+func (h *handler) Approve(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(ReviewReq)
+	if err := parseOptional(ctx, req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	result, err := h.uc.Approve(ctx.Context(), ctx.Params(handlers.ParamKeyPostID), user.UserID, user.Role, req.Comment)
+	if err != nil {
+		return h.reviewError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Reject Post
+// @Summary Reject Post
+// @Description Sends a post in review, or an approved one, back to its authors with the changes to make. Editors and admins only, and editors not on their own posts.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param data body posthandler.RejectReq true "Requested changes"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 409 {object} handlers.ConflictRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/reject [post]
+func (h *handler) Reject(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(RejectReq)
+	if err := parseOptional(ctx, req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	result, err := h.uc.RequestChanges(ctx.Context(), ctx.Params(handlers.ParamKeyPostID), user.UserID, user.Role, req.Comment)
+	if err != nil {
+		return h.reviewError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Get Post Reviews
+// @Summary Get Post Reviews
+// @Description The review history of a post, oldest first. Visible to its authors, editors and admins.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {array} []postdomain.Review
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/reviews [get]
+func (h *handler) GetReviews(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+	if !postusecase.IsReviewer(user.Role) {
+		ok, err := h.checkPermissions(ctx, postID)
+		if err != nil {
+			return h.permissionError(ctx, err)
+		}
+		if !ok {
+			return handlers.Forbidden(ctx, "no permissions")
+		}
+	}
+
+	result, err := h.uc.GetReviews(ctx.Context(), postID)
+	if err != nil {
+		return h.statusError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// parseOptional reads a request body that may be left out, validating the
+// zero value of req when it is.
+func parseOptional(ctx *fiber.Ctx, req any) error {
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(req); err != nil {
+			return err
+		}
+	}
+	return validate.Struct(req)
+}
+
+func (h *handler) reviewError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrPostInvalidTransition):
+		return handlers.Conflict(ctx, err.Error())
+	case errors.Is(err, errs.ErrReviewForbidden),
+		errors.Is(err, errs.ErrReviewOwnPost):
+		return handlers.Forbidden(ctx, err.Error())
+	default:
+		return h.statusError(ctx, err)
+	}
+}
@@ -0,0 +1,184 @@
+package posthandler
+
+import (
+	"errors"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Get Post Revisions
+// @Summary Get Post Revisions
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {array} []postdomain.Revision
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/revisions [get]
+func (h *handler) GetRevisions(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	result, err := h.uc.GetRevisions(ctx.Context(), postID)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Get Post Revision
+// @Summary Get Post Revision
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param revision path int true "Revision number"
+// @Success 200 {object} postdomain.Revision
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/revisions/{revision} [get]
+func (h *handler) GetRevision(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	revision, err := ctx.ParamsInt(handlers.ParamKeyRevision)
+	if err != nil {
+		return handlers.BadRequest(ctx, "invalid revision")
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	result, err := h.uc.GetRevision(ctx.Context(), postID, revision)
+	if err != nil {
+		return h.revisionError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Diff Post Revisions
+// @Summary Diff Post Revisions
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param from query int true "Base revision"
+// @Param to query int true "Target revision"
+// @Param mode query string false "line (default) or word"
+// @Success 200 {object} postusecase.RevisionDiff
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/revisions/diff [get]
+func (h *handler) DiffRevisions(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	from, to := ctx.QueryInt("from"), ctx.QueryInt("to")
+	if from <= 0 || to <= 0 {
+		return handlers.BadRequest(ctx, "from and to revisions are required")
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	result, err := h.uc.DiffRevisions(ctx.Context(), postID, from, to, ctx.Query("mode"))
+	if err != nil {
+		return h.revisionError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Restore Post Revision
+// @Summary Restore Post Revision
+// @Description Copies the revision back onto the post and records it as a new revision.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param revision path int true "Revision number"
+// @Param If-Match header string false "ETag of the version being replaced"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
+// @Failure 423 {object} handlers.LockedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/revisions/{revision}/restore [post]
+func (h *handler) RestoreRevision(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	revision, err := ctx.ParamsInt(handlers.ParamKeyRevision)
+	if err != nil {
+		return handlers.BadRequest(ctx, "invalid revision")
+	}
+
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.RestoreRevision(ctx.Context(), postID, revision, version, user.UserID, user.Role)
+	if err != nil {
+		return h.revisionError(ctx, err)
+	}
+
+	handlers.SetETag(ctx, result.Version)
+	return handlers.Success(ctx, result)
+}
+
+func (h *handler) revisionError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrRevisionNotFound), errors.Is(err, errs.ErrPostNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrInvalidDiffMode):
+		return handlers.BadRequest(ctx, err.Error())
+	default:
+		return h.statusError(ctx, err)
+	}
+}
@@ -0,0 +1,238 @@
+package posthandler
+
+import (
+	"errors"
+	"slices"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	transferusecase "github.com/codepnw/blog-api/internal/usecases/transfer"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/locale"
+	"github.com/codepnw/blog-api/internal/utils/validate"
+	"github.com/gofiber/fiber/v2"
+)
+
+const queryKeyLang = "lang"
+
+// Get Post Translations
+// @Summary Get Post Translations
+// @Description The translations of a post, with their content unless the post is members-only and the reader anonymous. The post's own language is not among them.
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {array} []postdomain.Translation
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/translations [get]
+func (h *handler) GetTranslations(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	post, err := h.uc.GetByID(ctx.Context(), postID)
+	if err != nil {
+		if errors.Is(err, errs.ErrPostNotFound) {
+			return handlers.NotFound(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+	if !h.canView(ctx, post) {
+		return handlers.NotFound(ctx, errs.ErrPostNotFound.Error())
+	}
+
+	result, err := h.uc.GetTranslations(ctx.Context(), postID)
+	if err != nil {
+		return h.statusError(ctx, err)
+	}
+	h.restrictTranslations(ctx, post, result)
+	return handlers.Success(ctx, result)
+}
+
+// Set Post Translation
+// @Summary Set Post Translation
+// @Description Creates or replaces the translation of a post into a supported locale. Any of its authors, or an admin, can translate it. By anyone but an editor or admin, it takes the post back to draft to be reviewed again.
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param locale path string true "Locale, e.g. th"
+// @Param If-Match header string false "ETag of the version being edited"
+// @Param data body posthandler.TranslationReq true "Translation"
+// @Success 200 {object} postdomain.Post
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 409 {object} handlers.ConflictRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
+// @Failure 423 {object} handlers.LockedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/translations/{locale} [put]
+func (h *handler) SetTranslation(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	lang := ctx.Params(handlers.ParamKeyLocale)
+	if !slices.Contains(h.locales, lang) {
+		return handlers.BadRequest(ctx, errs.ErrUnsupportedLocale.Error())
+	}
+
+	req := new(TranslationReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	input := &postdomain.Translation{
+		PostID:        postID,
+		Locale:        lang,
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: req.Format,
+	}
+	if req.Slug != nil {
+		if slug := transferusecase.Slugify(*req.Slug); slug != "" {
+			input.Slug = &slug
+		}
+	}
+
+	result, err := h.uc.SetTranslation(ctx.Context(), input, version, user.UserID, user.Role)
+	if err != nil {
+		return h.translationError(ctx, err)
+	}
+	h.setAlternates(ctx, result)
+
+	handlers.SetETag(ctx, result.Version)
+	return handlers.Success(ctx, result)
+}
+
+// Delete Post Translation
+// @Summary Delete Post Translation
+// @Description Any of the post's authors, or an admin, can remove a translation. By anyone but an editor or admin, it takes the post back to draft to be reviewed again.
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param locale path string true "Locale, e.g. th"
+// @Param If-Match header string false "ETag of the version being edited"
+// @Success 200 {object} postdomain.Post
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
+// @Failure 423 {object} handlers.LockedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/translations/{locale} [delete]
+func (h *handler) DeleteTranslation(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	ok, err := h.checkPermissions(ctx, postID)
+	if err != nil {
+		return h.permissionError(ctx, err)
+	}
+	if !ok {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.DeleteTranslation(ctx.Context(), postID, ctx.Params(handlers.ParamKeyLocale), version, user.UserID, user.Role)
+	if err != nil {
+		return h.translationError(ctx, err)
+	}
+	h.setAlternates(ctx, result)
+
+	handlers.SetETag(ctx, result.Version)
+	return handlers.Success(ctx, result)
+}
+
+// localize serves the post in the first language of chain it is available
+// in and lists where the others are.
+func (h *handler) localize(ctx *fiber.Ctx, post *postdomain.Post, chain []string) error {
+	ctx.Vary(fiber.HeaderAcceptLanguage)
+	if err := h.uc.Localize(ctx.Context(), post, chain); err != nil {
+		return err
+	}
+	ctx.Set(fiber.HeaderContentLanguage, post.Locale)
+	h.setAlternates(ctx, post)
+	return nil
+}
+
+// localizeList serves each post of a list in the reader's language.
+func (h *handler) localizeList(ctx *fiber.Ctx, posts []*postdomain.Summary) error {
+	ctx.Vary(fiber.HeaderAcceptLanguage)
+	return h.uc.LocalizeSummaries(ctx.Context(), posts, h.localeChain(ctx))
+}
+
+// localeChain is the order of languages the reader prefers, from ?lang=
+// and Accept-Language.
+func (h *handler) localeChain(ctx *fiber.Ctx) []string {
+	return locale.Chain(ctx.Query(queryKeyLang), ctx.Get(fiber.HeaderAcceptLanguage), h.locales)
+}
+
+// listLocale reads the locale a list is filtered by. Unlike the preference
+// of a single post, an unknown one is an error, since it would match nothing.
+func (h *handler) listLocale(ctx *fiber.Ctx) (string, error) {
+	lang := ctx.Query(queryKeyLang)
+	if lang == "" {
+		return "", nil
+	}
+	if l := locale.Match(lang, h.locales); l != "" {
+		return l, nil
+	}
+	return "", errs.ErrUnsupportedLocale
+}
+
+// setAlternates lists the post in every language it is available in, the
+// post's own being the default.
+func (h *handler) setAlternates(ctx *fiber.Ctx, post *postdomain.Post) {
+	href := handlers.SiteURL(ctx, h.siteURL, h.apiPrefix) + "/posts/" + post.ID
+
+	post.Alternates = []postdomain.Alternate{{Hreflang: "x-default", Href: href}}
+	for _, l := range post.Locales {
+		post.Alternates = append(post.Alternates, postdomain.Alternate{Hreflang: l, Href: href + "?lang=" + l})
+	}
+}
+
+func (h *handler) translationError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrTranslationNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrTranslationOwnLocale):
+		return handlers.BadRequest(ctx, err.Error())
+	case errors.Is(err, errs.ErrPostSlugTaken):
+		return handlers.Conflict(ctx, err.Error())
+	default:
+		return h.statusError(ctx, err)
+	}
+}
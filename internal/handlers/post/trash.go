@@ -0,0 +1,88 @@
+package posthandler
+
+import (
+	"errors"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Get Trashed Posts
+// @Summary Get Trashed Posts
+// @Description Lists the current user's deleted posts. Admins can pass all=true to see every user's.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param all query bool false "Every user's trash (admin only)"
+// @Success 200 {array} []postdomain.Post
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /trash/posts [get]
+func (h *handler) GetTrash(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	authorID := user.UserID
+	if ctx.QueryBool("all") {
+		if user.Role != string(userusecase.RoleAdmin) {
+			return handlers.Forbidden(ctx, "no permissions")
+		}
+		authorID = ""
+	}
+
+	result, err := h.uc.GetTrash(ctx.Context(), authorID)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Restore Post
+// @Summary Restore Post
+// @Description Moves a deleted post out of the trash, together with its comments.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {object} postdomain.Post
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/restore [post]
+func (h *handler) Restore(ctx *fiber.Ctx) error {
+	postID := ctx.Params(handlers.ParamKeyPostID)
+
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	post, err := h.uc.GetDeletedByID(ctx.Context(), postID)
+	if err != nil {
+		if errors.Is(err, errs.ErrPostNotFound) {
+			return handlers.NotFound(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+	if post.AuthorID != user.UserID && user.Role != string(userusecase.RoleAdmin) {
+		return handlers.Forbidden(ctx, "no permissions")
+	}
+
+	result, err := h.uc.Restore(ctx.Context(), postID)
+	if err != nil {
+		if errors.Is(err, errs.ErrPostNotFound) {
+			return handlers.NotFound(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
@@ -0,0 +1,49 @@
+package posthandler
+
+import (
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/middleware"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	"github.com/gofiber/fiber/v2"
+)
+
+const headerRobotsTag = "X-Robots-Tag"
+
+// restrict applies the visibility of a post the reader may open: unlisted
+// and private posts are kept out of search engines, and anonymous readers
+// get a teaser of members-only posts.
+func (h *handler) restrict(ctx *fiber.Ctx, post *postdomain.Post) {
+	if !postusecase.IsListed(post.Visibility) {
+		ctx.Set(headerRobotsTag, "noindex")
+	}
+	if !h.isTeaser(ctx, post) {
+		return
+	}
+	post.Content = ""
+	post.ContentHTML = ""
+	post.TOC = []postdomain.Heading{}
+	post.Teaser = true
+}
+
+// restrictTranslations leaves the title and excerpt of each translation
+// when the post is served as a teaser.
+func (h *handler) restrictTranslations(ctx *fiber.Ctx, post *postdomain.Post, translations []*postdomain.Translation) {
+	if !h.isTeaser(ctx, post) {
+		return
+	}
+	for _, tr := range translations {
+		tr.Content = ""
+		tr.ContentHTML = ""
+		tr.TOC = []postdomain.Heading{}
+	}
+}
+
+// isTeaser reports whether the post is members-only and the reader isn't
+// signed in.
+func (h *handler) isTeaser(ctx *fiber.Ctx, post *postdomain.Post) bool {
+	if post.Visibility != string(postusecase.VisibilityMembers) {
+		return false
+	}
+	_, err := middleware.GetCurrentUser(ctx)
+	return err != nil
+}
@@ -0,0 +1,181 @@
+package previewhandler
+
+import (
+	"errors"
+
+	previewdomain "github.com/codepnw/blog-api/internal/domains/preview"
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	previewusecase "github.com/codepnw/blog-api/internal/usecases/preview"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/validate"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type handler struct {
+	uc previewusecase.Usecase
+	// previewPath is the route previews are served from.
+	previewPath string
+}
+
+func NewPreviewHandler(uc previewusecase.Usecase, previewPath string) *handler {
+	return &handler{uc: uc, previewPath: previewPath}
+}
+
+// Create Preview Link
+// @Summary Create Preview Link
+// @Description A secret link that opens the post as it stands, draft or not, without an account. Any of the post's authors, or an admin, can create one.
+// @Tags previews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param data body previewhandler.PreviewLinkCreateReq false "Link options"
+// @Success 201 {object} previewdomain.Link
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/preview-links [post]
+func (h *handler) Create(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(PreviewLinkCreateReq)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(req); err != nil {
+			return handlers.BadRequest(ctx, err.Error())
+		}
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	input := &previewdomain.Link{
+		PostID:    ctx.Params(handlers.ParamKeyPostID),
+		CreatedBy: &user.UserID,
+		Note:      req.Note,
+	}
+	if req.ExpiresAt != nil {
+		input.ExpiresAt = *req.ExpiresAt
+	}
+
+	result, err := h.uc.Create(ctx.Context(), input, user.Role)
+	if err != nil {
+		return h.previewError(ctx, err)
+	}
+	return handlers.Created(ctx, h.withURL(ctx, result))
+}
+
+// Get Preview Links
+// @Summary Get Preview Links
+// @Description The links of a post, newest first, including expired and revoked ones. Only active links have a URL.
+// @Tags previews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {array} []previewdomain.Link
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/preview-links [get]
+func (h *handler) GetByPost(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	result, err := h.uc.GetByPost(ctx.Context(), ctx.Params(handlers.ParamKeyPostID), user.UserID, user.Role)
+	if err != nil {
+		return h.previewError(ctx, err)
+	}
+	for _, link := range result {
+		h.withURL(ctx, link)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Revoke Preview Link
+// @Summary Revoke Preview Link
+// @Description The link stops opening the post right away.
+// @Tags previews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param link_id path string true "Link ID"
+// @Success 200 {object} previewdomain.Link
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/preview-links/{link_id} [delete]
+func (h *handler) Revoke(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	linkID := ctx.Params(handlers.ParamKeyLinkID)
+	if uuid.Validate(linkID) != nil {
+		return handlers.NotFound(ctx, errs.ErrPreviewLinkNotFound.Error())
+	}
+
+	result, err := h.uc.Revoke(ctx.Context(), ctx.Params(handlers.ParamKeyPostID), linkID, user.UserID, user.Role)
+	if err != nil {
+		return h.previewError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Open Preview
+// @Summary Open Preview
+// @Description The post a preview link points to, read-only and as it currently stands. Previews are never cached or indexed.
+// @Tags previews
+// @Accept json
+// @Produce json
+// @Param token path string true "Preview token"
+// @Success 200 {object} previewdomain.Preview
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /preview/{token} [get]
+func (h *handler) Open(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+	ctx.Set("X-Robots-Tag", "noindex, nofollow")
+	ctx.Set(fiber.HeaderReferrerPolicy, "no-referrer")
+
+	result, err := h.uc.Open(ctx.Context(), ctx.Params(handlers.ParamKeyToken))
+	if err != nil {
+		return h.previewError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// withURL sets the public URL of an active link.
+func (h *handler) withURL(ctx *fiber.Ctx, link *previewdomain.Link) *previewdomain.Link {
+	if link.Token != "" {
+		link.URL = ctx.BaseURL() + h.previewPath + "/" + link.Token
+	}
+	return link
+}
+
+func (h *handler) previewError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound),
+		errors.Is(err, errs.ErrPreviewLinkNotFound),
+		errors.Is(err, errs.ErrPreviewLinkInvalid):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrPreviewForbidden):
+		return handlers.Forbidden(ctx, err.Error())
+	case errors.Is(err, errs.ErrPreviewExpiry):
+		return handlers.BadRequest(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
@@ -0,0 +1,10 @@
+package previewhandler
+
+import "time"
+
+type PreviewLinkCreateReq struct {
+	// Note tells links apart, e.g. the name of the reviewer.
+	Note string `json:"note,omitempty" validate:"omitempty,max=255"`
+	// ExpiresAt defaults to the configured lifetime of a link.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" validate:"omitempty"`
+}
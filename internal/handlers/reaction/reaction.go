@@ -0,0 +1,118 @@
+package reactionhandler
+
+import (
+	"errors"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	reactionusecase "github.com/codepnw/blog-api/internal/usecases/reaction"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+type handler struct {
+	uc reactionusecase.Usecase
+}
+
+func NewReactionHandler(uc reactionusecase.Usecase) *handler {
+	return &handler{uc: uc}
+}
+
+// Get Reactions
+// @Summary Get Reactions
+// @Description Reaction counts of a published post, and the current user's own reactions.
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {object} reactiondomain.Reactions
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/reactions [get]
+func (h *handler) GetByPost(ctx *fiber.Ctx) error {
+	var userID string
+	if user, err := middleware.GetCurrentUser(ctx); err == nil {
+		userID = user.UserID
+	}
+
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	result, err := h.uc.GetByPost(ctx.Context(), postID, userID)
+	if err != nil {
+		return h.reactionError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Add Reaction
+// @Summary Add Reaction
+// @Description Adding a reaction the user already gave is a no-op.
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param reaction path string true "Reaction type, e.g. like"
+// @Success 200 {object} reactiondomain.Reactions
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/reactions/{reaction} [put]
+func (h *handler) React(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	reaction := ctx.Params(handlers.ParamKeyReaction)
+
+	result, err := h.uc.React(ctx.Context(), postID, user.UserID, reaction)
+	if err != nil {
+		return h.reactionError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Remove Reaction
+// @Summary Remove Reaction
+// @Description Removing a reaction the user didn't give is a no-op.
+// @Tags reactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param reaction path string true "Reaction type, e.g. like"
+// @Success 200 {object} reactiondomain.Reactions
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /posts/{post_id}/reactions/{reaction} [delete]
+func (h *handler) Unreact(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	reaction := ctx.Params(handlers.ParamKeyReaction)
+
+	result, err := h.uc.Unreact(ctx.Context(), postID, user.UserID, reaction)
+	if err != nil {
+		return h.reactionError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+func (h *handler) reactionError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrPostNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrInvalidReaction):
+		return handlers.BadRequest(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
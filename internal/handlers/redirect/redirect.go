@@ -0,0 +1,46 @@
+package redirecthandler
+
+import (
+	"errors"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	redirectusecase "github.com/codepnw/blog-api/internal/usecases/redirect"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/wxr"
+	"github.com/gofiber/fiber/v2"
+)
+
+type handler struct {
+	uc        redirectusecase.Usecase
+	siteURL   string
+	apiPrefix string
+}
+
+func NewRedirectHandler(uc redirectusecase.Usecase, siteURL, apiPrefix string) *handler {
+	return &handler{uc: uc, siteURL: siteURL, apiPrefix: apiPrefix}
+}
+
+// Redirect Old Permalink
+// @Summary Redirect Old Permalink
+// @Description Answers any path no route matched. Permalinks of imported WordPress posts, including the ?p= form, are permanently redirected to the post.
+// @Tags redirects
+// @Param path path string true "Old permalink"
+// @Success 301 {object} handlers.EmptyRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /{path} [get]
+func (h *handler) Redirect(ctx *fiber.Ctx) error {
+	path := wxr.NormalizePath(ctx.Path())
+	if p := ctx.Query("p"); path == "/" && p != "" {
+		path = "/?p=" + p
+	}
+
+	postID, err := h.uc.Resolve(ctx.Context(), path)
+	if err != nil {
+		if errors.Is(err, errs.ErrRedirectNotFound) {
+			return handlers.NotFound(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+	return ctx.Redirect(handlers.SiteURL(ctx, h.siteURL, h.apiPrefix)+"/posts/"+postID, fiber.StatusMovedPermanently)
+}
@@ -0,0 +1,20 @@
+package serieshandler
+
+type SeriesCreateReq struct {
+	Title       string `json:"title" validate:"required,max=200"`
+	Description string `json:"description,omitempty" validate:"omitempty"`
+}
+
+type SeriesUpdateReq struct {
+	Title       *string `json:"title,omitempty" validate:"omitempty,min=1,max=200"`
+	Description *string `json:"description,omitempty" validate:"omitempty"`
+}
+
+type SeriesAddPostReq struct {
+	PostID string `json:"post_id" validate:"required,uuid"`
+}
+
+type SeriesOrderReq struct {
+	// PostIDs lists every part of the series in the new order.
+	PostIDs []string `json:"post_ids" validate:"required,min=1,dive,uuid"`
+}
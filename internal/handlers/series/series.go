@@ -0,0 +1,280 @@
+package serieshandler
+
+import (
+	"errors"
+
+	seriesdomain "github.com/codepnw/blog-api/internal/domains/series"
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	seriesusecase "github.com/codepnw/blog-api/internal/usecases/series"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/validate"
+	"github.com/gofiber/fiber/v2"
+)
+
+type handler struct {
+	uc seriesusecase.Usecase
+}
+
+func NewSeriesHandler(uc seriesusecase.Usecase) *handler {
+	return &handler{uc: uc}
+}
+
+// Create Series
+// @Summary Create Series
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param data body serieshandler.SeriesCreateReq true "Series data"
+// @Success 201 {object} seriesdomain.Series
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /series [post]
+func (h *handler) Create(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(SeriesCreateReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	input := &seriesdomain.Series{
+		AuthorID:    user.UserID,
+		Title:       req.Title,
+		Description: req.Description,
+	}
+
+	result, err := h.uc.Create(ctx.Context(), input)
+	if err != nil {
+		return h.seriesError(ctx, err)
+	}
+	return handlers.Created(ctx, result)
+}
+
+// Get Series
+// @Summary Get Series
+// @Description Lists the parts in order. Unpublished parts are only shown to the author and admins.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param series_id path string true "Series ID"
+// @Success 200 {object} seriesdomain.Series
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /series/{series_id} [get]
+func (h *handler) GetByID(ctx *fiber.Ctx) error {
+	var viewerID, role string
+	if user, err := middleware.GetCurrentUser(ctx); err == nil {
+		viewerID, role = user.UserID, user.Role
+	}
+
+	id := ctx.Params(handlers.ParamKeySeriesID)
+	result, err := h.uc.GetByID(ctx.Context(), id, viewerID, role)
+	if err != nil {
+		return h.seriesError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Edit Series
+// @Summary Edit Series
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param series_id path string true "Series ID"
+// @Param data body serieshandler.SeriesUpdateReq true "Series data"
+// @Success 200 {object} seriesdomain.Series
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /series/{series_id} [patch]
+func (h *handler) Update(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(SeriesUpdateReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	input := &seriesdomain.Series{ID: ctx.Params(handlers.ParamKeySeriesID)}
+	if req.Title != nil {
+		input.Title = *req.Title
+	}
+	if req.Description != nil {
+		input.Description = *req.Description
+	}
+
+	result, err := h.uc.Update(ctx.Context(), input, user.UserID, user.Role)
+	if err != nil {
+		return h.seriesError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Delete Series
+// @Summary Delete Series
+// @Description The posts of the series are kept.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param series_id path string true "Series ID"
+// @Success 204 {object} handlers.EmptyRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /series/{series_id} [delete]
+func (h *handler) Delete(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	id := ctx.Params(handlers.ParamKeySeriesID)
+	if err := h.uc.Delete(ctx.Context(), id, user.UserID, user.Role); err != nil {
+		return h.seriesError(ctx, err)
+	}
+	return handlers.NoContent(ctx)
+}
+
+// Add Post To Series
+// @Summary Add Post To Series
+// @Description Appends one of the series author's posts as the last part.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param series_id path string true "Series ID"
+// @Param data body serieshandler.SeriesAddPostReq true "Post"
+// @Success 200 {object} seriesdomain.Series
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 409 {object} handlers.ConflictRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /series/{series_id}/posts [post]
+func (h *handler) AddPost(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(SeriesAddPostReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	id := ctx.Params(handlers.ParamKeySeriesID)
+	result, err := h.uc.AddPost(ctx.Context(), id, req.PostID, user.UserID, user.Role)
+	if err != nil {
+		return h.seriesError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Remove Post From Series
+// @Summary Remove Post From Series
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param series_id path string true "Series ID"
+// @Param post_id path string true "Post ID"
+// @Success 200 {object} seriesdomain.Series
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /series/{series_id}/posts/{post_id} [delete]
+func (h *handler) RemovePost(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	id := ctx.Params(handlers.ParamKeySeriesID)
+	postID := ctx.Params(handlers.ParamKeyPostID)
+	result, err := h.uc.RemovePost(ctx.Context(), id, postID, user.UserID, user.Role)
+	if err != nil {
+		return h.seriesError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+// Reorder Series
+// @Summary Reorder Series
+// @Tags series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param series_id path string true "Series ID"
+// @Param data body serieshandler.SeriesOrderReq true "New order"
+// @Success 200 {object} seriesdomain.Series
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /series/{series_id}/order [put]
+func (h *handler) Reorder(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	req := new(SeriesOrderReq)
+	if err := ctx.BodyParser(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	if err := validate.Struct(req); err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	id := ctx.Params(handlers.ParamKeySeriesID)
+	result, err := h.uc.Reorder(ctx.Context(), id, req.PostIDs, user.UserID, user.Role)
+	if err != nil {
+		return h.seriesError(ctx, err)
+	}
+	return handlers.Success(ctx, result)
+}
+
+func (h *handler) seriesError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrSeriesNotFound),
+		errors.Is(err, errs.ErrPostNotFound),
+		errors.Is(err, errs.ErrPostNotInSeries):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrSeriesNotOwner):
+		return handlers.Forbidden(ctx, err.Error())
+	case errors.Is(err, errs.ErrSeriesPostNotOwned),
+		errors.Is(err, errs.ErrInvalidSeriesOrder):
+		return handlers.BadRequest(ctx, err.Error())
+	case errors.Is(err, errs.ErrPostInSeries):
+		return handlers.Conflict(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SiteURL is the public address of the blog that feeds and the sitemap link
+// to, or the API on the requested host when none is configured.
+func SiteURL(ctx *fiber.Ctx, siteURL, apiPrefix string) string {
+	if siteURL != "" {
+		return strings.TrimRight(siteURL, "/")
+	}
+	return ctx.BaseURL() + apiPrefix
+}
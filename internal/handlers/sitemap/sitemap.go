@@ -0,0 +1,207 @@
+package sitemaphandler
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codepnw/blog-api/internal/config"
+	"github.com/codepnw/blog-api/internal/handlers"
+	sitemapusecase "github.com/codepnw/blog-api/internal/usecases/sitemap"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	xhtmlNS   = "http://www.w3.org/1999/xhtml"
+)
+
+type handler struct {
+	uc        sitemapusecase.Usecase
+	robots    config.RobotsConfig
+	siteURL   string
+	apiPrefix string
+}
+
+func NewSitemapHandler(uc sitemapusecase.Usecase, robots config.RobotsConfig, siteURL, apiPrefix string) *handler {
+	return &handler{uc: uc, robots: robots, siteURL: siteURL, apiPrefix: apiPrefix}
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	NS       string         `xml:"xmlns,attr"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc        string      `xml:"loc"`
+	LastMod    string      `xml:"lastmod,omitempty"`
+	Alternates []xhtmlLink `xml:"xhtml:link"`
+}
+
+// xhtmlLink is an hreflang alternate of a URL.
+type xhtmlLink struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type urlSet struct {
+	XMLName xml.Name       `xml:"urlset"`
+	NS      string         `xml:"xmlns,attr"`
+	XHTMLNS string         `xml:"xmlns:xhtml,attr"`
+	URLs    []sitemapEntry `xml:"url"`
+}
+
+// Sitemap Index
+// @Summary Sitemap Index
+// @Description Lists the sitemap files, of up to 50,000 URLs each.
+// @Tags sitemap
+// @Produce xml
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Success 200 {string} string "Sitemap index"
+// @Success 304 {object} handlers.EmptyRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /sitemap.xml [get]
+func (h *handler) Index(ctx *fiber.Ctx) error {
+	files, err := h.uc.GetIndex(ctx.Context())
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+
+	var (
+		index   = sitemapIndex{NS: sitemapNS}
+		items   = make([]handlers.Validator, 0, len(files))
+		updated time.Time
+	)
+	for _, file := range files {
+		entry := sitemapEntry{Loc: fmt.Sprintf("%s/sitemaps/%d.xml", ctx.BaseURL(), file.Page)}
+		if !file.LastMod.IsZero() {
+			entry.LastMod = file.LastMod.UTC().Format(time.RFC3339)
+		}
+		index.Sitemaps = append(index.Sitemaps, entry)
+		items = append(items, handlers.Validator{ID: entry.Loc, State: entry.LastMod})
+		if file.LastMod.After(updated) {
+			updated = file.LastMod
+		}
+	}
+
+	if handlers.Fresh(ctx, handlers.CollectionETag(items), updated) {
+		return handlers.NotModified(ctx)
+	}
+	return sendXML(ctx, index)
+}
+
+// Sitemap File
+// @Summary Sitemap File
+// @Description Published posts and the category, author and tag pages that have any, with the latest updated_at as lastmod. Translated posts list their languages as hreflang alternates.
+// @Tags sitemap
+// @Produce xml
+// @Param page path int true "File number, from 1"
+// @Param If-None-Match header string false "ETag of the cached copy"
+// @Success 200 {string} string "Sitemap"
+// @Success 304 {object} handlers.EmptyRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /sitemaps/{page}.xml [get]
+func (h *handler) File(ctx *fiber.Ctx) error {
+	page, err := strconv.Atoi(ctx.Params(handlers.ParamKeyPage))
+	if err != nil {
+		return handlers.NotFound(ctx, errs.ErrSitemapNotFound.Error())
+	}
+
+	urls, err := h.uc.GetFile(ctx.Context(), page)
+	if err != nil {
+		if errors.Is(err, errs.ErrSitemapNotFound) {
+			return handlers.NotFound(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+
+	var (
+		site    = handlers.SiteURL(ctx, h.siteURL, h.apiPrefix)
+		set     = urlSet{NS: sitemapNS, XHTMLNS: xhtmlNS, URLs: make([]sitemapEntry, 0, len(urls))}
+		items   = make([]handlers.Validator, 0, len(urls))
+		updated time.Time
+	)
+	for _, u := range urls {
+		entry := sitemapEntry{
+			Loc:        site + escapePath(u.Path),
+			LastMod:    u.LastMod.UTC().Format(time.RFC3339),
+			Alternates: alternates(site+escapePath(u.Path), u.Locales),
+		}
+		set.URLs = append(set.URLs, entry)
+		items = append(items, handlers.Validator{ID: u.Path, State: entry.LastMod + strings.Join(u.Locales, ",")})
+		if u.LastMod.After(updated) {
+			updated = u.LastMod
+		}
+	}
+
+	if handlers.Fresh(ctx, handlers.CollectionETag(items), updated) {
+		return handlers.NotModified(ctx)
+	}
+	return sendXML(ctx, set)
+}
+
+// Robots
+// @Summary Robots
+// @Description Crawler rules from ROBOTS_ALLOW and ROBOTS_DISALLOW, pointing at the sitemap.
+// @Tags sitemap
+// @Produce plain
+// @Success 200 {string} string "robots.txt"
+// @Router /robots.txt [get]
+func (h *handler) Robots(ctx *fiber.Ctx) error {
+	var sb strings.Builder
+	sb.WriteString("User-agent: *\n")
+	for _, path := range h.robots.Allow {
+		fmt.Fprintf(&sb, "Allow: %s\n", path)
+	}
+	for _, path := range h.robots.Disallow {
+		fmt.Fprintf(&sb, "Disallow: %s\n", path)
+	}
+	// An empty Disallow allows everything; a group needs at least one rule.
+	if len(h.robots.Allow) == 0 && len(h.robots.Disallow) == 0 {
+		sb.WriteString("Disallow:\n")
+	}
+	fmt.Fprintf(&sb, "\nSitemap: %s/sitemap.xml\n", ctx.BaseURL())
+
+	ctx.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return ctx.SendString(sb.String())
+}
+
+// alternates links a translated post to each of its languages, and to loc
+// as the default. Posts in a single language need none.
+func alternates(loc string, locales []string) []xhtmlLink {
+	if len(locales) < 2 {
+		return nil
+	}
+	links := []xhtmlLink{{Rel: "alternate", Hreflang: "x-default", Href: loc}}
+	for _, l := range locales {
+		links = append(links, xhtmlLink{Rel: "alternate", Hreflang: l, Href: loc + "?lang=" + url.QueryEscape(l)})
+	}
+	return links
+}
+
+func sendXML(ctx *fiber.Ctx, v any) error {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	return ctx.Send(append([]byte(xml.Header), body...))
+}
+
+// escapePath escapes every segment of a stored path, since tag pages are
+// keyed by the tag's name.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
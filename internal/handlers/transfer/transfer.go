@@ -0,0 +1,149 @@
+package transferhandler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"strings"
+	"time"
+
+	transferdomain "github.com/codepnw/blog-api/internal/domains/transfer"
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	transferusecase "github.com/codepnw/blog-api/internal/usecases/transfer"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/gofiber/fiber/v2"
+)
+
+const formKeyFiles = "files"
+
+type handler struct {
+	uc transferusecase.Usecase
+}
+
+func NewTransferHandler(uc transferusecase.Usecase) *handler {
+	return &handler{uc: uc}
+}
+
+// Import Posts
+// @Summary Import Posts
+// @Description Creates posts from Markdown files with YAML front matter (title, slug, date, category, tags, author email, draft), uploaded as ZIP archives or single files. Files whose slug is already taken are skipped. Files without an author belong to the importing admin.
+// @Tags admin
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param files formData file true "ZIP archives or Markdown files"
+// @Param dry_run query bool false "Report what would be imported without creating anything"
+// @Success 200 {object} transferdomain.ImportReport
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 413 {object} handlers.RequestEntityTooLargeRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /admin/import [post]
+func (h *handler) Import(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	form, err := ctx.MultipartForm()
+	if err != nil || len(form.File[formKeyFiles]) == 0 {
+		return handlers.BadRequest(ctx, "files are required")
+	}
+
+	var files []*transferdomain.File
+	for _, header := range form.File[formKeyFiles] {
+		read, err := readUpload(header)
+		if err != nil {
+			return h.importError(ctx, err)
+		}
+		files = append(files, read...)
+		if len(files) > transferusecase.MaxFiles {
+			return handlers.BadRequest(ctx, errs.ErrImportTooManyFiles.Error())
+		}
+	}
+
+	opts := transferdomain.ImportOptions{
+		DryRun:   ctx.QueryBool("dry_run"),
+		AuthorID: user.UserID,
+	}
+	report, err := h.uc.Import(ctx.Context(), files, opts)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.Success(ctx, report)
+}
+
+// Export Posts
+// @Summary Export Posts
+// @Description Every post outside the trash as a ZIP of Markdown files in the format the import reads. Archived posts are exported as drafts.
+// @Tags admin
+// @Produce application/zip
+// @Security BearerAuth
+// @Success 200 {file} file "ZIP archive"
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /admin/export [get]
+func (h *handler) Export(ctx *fiber.Ctx) error {
+	files, err := h.uc.Export(ctx.Context())
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+
+	var buf bytes.Buffer
+	if err := transferusecase.WriteZip(&buf, files); err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+
+	name := fmt.Sprintf("posts-%s.zip", time.Now().UTC().Format("20060102"))
+	ctx.Set(fiber.HeaderContentType, "application/zip")
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, name))
+	return ctx.Send(buf.Bytes())
+}
+
+// readUpload unpacks a ZIP archive or reads a single Markdown file.
+func readUpload(header *multipart.FileHeader) ([]*transferdomain.File, error) {
+	isZip := strings.EqualFold(path.Ext(header.Filename), ".zip")
+	if !isZip && !transferusecase.IsMarkdown(header.Filename) {
+		return nil, fmt.Errorf("%w: %s", errs.ErrImportUnsupported, header.Filename)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if isZip {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, err
+		}
+		return transferusecase.ReadZip(data)
+	}
+
+	if header.Size > transferusecase.MaxFileSize {
+		return nil, errs.ErrImportFileTooLarge
+	}
+	data, err := io.ReadAll(io.LimitReader(file, transferusecase.MaxFileSize))
+	if err != nil {
+		return nil, err
+	}
+	return []*transferdomain.File{{Name: header.Filename, Data: data}}, nil
+}
+
+func (h *handler) importError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrImportFileTooLarge):
+		return handlers.RequestEntityTooLarge(ctx, err.Error())
+	case errors.Is(err, errs.ErrImportUnsupported), errors.Is(err, errs.ErrImportTooManyFiles):
+		return handlers.BadRequest(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
@@ -10,6 +10,8 @@ type UserCreateReq struct {
 type UserUpdateReq struct {
 	FirstName *string `json:"first_name,omitempty"`
 	LastName  *string `json:"last_name,omitempty"`
+	// Role takes effect the next time the user logs in.
+	Role *string `json:"role,omitempty" validate:"omitempty,oneof=user editor admin"`
 }
 
 type UserLoginReq struct {
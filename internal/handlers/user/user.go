@@ -76,6 +76,7 @@ func (h *handler) GetUser(ctx *fiber.Ctx) error {
 		return handlers.InternalServerError(ctx, err)
 	}
 
+	handlers.SetETag(ctx, result.Version)
 	return handlers.Success(ctx, result)
 }
 
@@ -103,6 +104,7 @@ func (h *handler) GetProfile(ctx *fiber.Ctx) error {
 		return handlers.InternalServerError(ctx, err)
 	}
 
+	handlers.SetETag(ctx, result.Version)
 	return handlers.Success(ctx, result)
 }
 
@@ -131,10 +133,12 @@ func (h *handler) GetAllUsers(ctx *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param user_id path string true "User ID"
+// @Param If-Match header string false "ETag of the version being edited"
 // @Param data body userhandler.UserUpdateReq true "User data"
 // @Success 200 {object} userdomain.User
 // @Failure 400 {object} handlers.BadRequestRes
 // @Failure 404 {object} handlers.NotFoundRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /users/{user_id} [patch]
 func (h *handler) UpdateUser(ctx *fiber.Ctx) error {
@@ -155,16 +159,23 @@ func (h *handler) UpdateUser(ctx *fiber.Ctx) error {
 	if req.LastName != nil {
 		input.LastName = *req.LastName
 	}
+	if req.Role != nil {
+		input.Role = *req.Role
+	}
 	input.ID = id
 
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+	input.Version = version
+
 	result, err := h.uc.UpdateUser(ctx.Context(), input)
 	if err != nil {
-		if errors.Is(err, errs.ErrUserNotFound) {
-			return handlers.NotFound(ctx, err.Error())
-		}
-		return handlers.InternalServerError(ctx, err)
+		return h.userError(ctx, err)
 	}
 
+	handlers.SetETag(ctx, result.Version)
 	return handlers.Success(ctx, result)
 }
 
@@ -175,22 +186,37 @@ func (h *handler) UpdateUser(ctx *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param user_id path string true "User ID"
+// @Param If-Match header string false "ETag of the version being deleted"
 // @Success 200 {object} userdomain.User
 // @Failure 404 {object} handlers.NotFoundRes
+// @Failure 412 {object} handlers.PreconditionFailedRes
 // @Failure 500 {object} handlers.InternalServerErrRes
 // @Router /users/{user_id} [delete]
 func (h *handler) DeleteUser(ctx *fiber.Ctx) error {
 	id := ctx.Params(handlers.ParamKeyUserID)
 
-	if err := h.uc.DeleteUser(ctx.Context(), id); err != nil {
-		if errors.Is(err, errs.ErrUserNotFound) {
-			return handlers.NotFound(ctx, err.Error())
-		}
-		return handlers.InternalServerError(ctx, err)
+	version, err := handlers.IfMatchVersion(ctx)
+	if err != nil {
+		return handlers.BadRequest(ctx, err.Error())
+	}
+
+	if err := h.uc.DeleteUser(ctx.Context(), id, version); err != nil {
+		return h.userError(ctx, err)
 	}
 	return handlers.NoContent(ctx)
 }
 
+func (h *handler) userError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errs.ErrUserNotFound):
+		return handlers.NotFound(ctx, err.Error())
+	case errors.Is(err, errs.ErrVersionConflict):
+		return handlers.PreconditionFailed(ctx, err.Error())
+	default:
+		return handlers.InternalServerError(ctx, err)
+	}
+}
+
 // ---- Auth ------
 
 // Auth Register
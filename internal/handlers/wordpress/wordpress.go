@@ -0,0 +1,93 @@
+package wordpresshandler
+
+import (
+	"errors"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	"github.com/codepnw/blog-api/internal/middleware"
+	wordpressusecase "github.com/codepnw/blog-api/internal/usecases/wordpress"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/wxr"
+	"github.com/gofiber/fiber/v2"
+)
+
+const formKeyFile = "file"
+
+type handler struct {
+	uc wordpressusecase.Usecase
+}
+
+func NewWordPressHandler(uc wordpressusecase.Usecase) *handler {
+	return &handler{uc: uc}
+}
+
+// Import WordPress Export
+// @Summary Import WordPress Export
+// @Description Starts importing a WXR file from Tools > Export in WordPress: authors and commenters become users with a random password, then categories, tags, posts with their HTML content, approved comments with their original dates, and redirects from the old permalinks. Posts whose author has no email belong to the importing admin. Running it again for the same site skips what was already imported. Follow the progress with the returned job.
+// @Tags admin
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "WXR file"
+// @Success 202 {object} wordpressdomain.ImportJob
+// @Failure 400 {object} handlers.BadRequestRes
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /admin/import/wordpress [post]
+func (h *handler) Import(ctx *fiber.Ctx) error {
+	user, err := middleware.GetCurrentUser(ctx)
+	if err != nil {
+		return handlers.Unauthorized(ctx, err.Error())
+	}
+
+	header, err := ctx.FormFile(formKeyFile)
+	if err != nil {
+		return handlers.BadRequest(ctx, "file is required")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	defer file.Close()
+
+	// The whole file is checked before the job starts, so a wrong upload is
+	// refused right away.
+	export, err := wxr.Parse(file)
+	if err != nil {
+		if errors.Is(err, wxr.ErrNotWXR) {
+			return handlers.BadRequest(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+
+	job, err := h.uc.Start(ctx.Context(), export, user.UserID)
+	if err != nil {
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.Accepted(ctx, job)
+}
+
+// Get Import Job
+// @Summary Get Import Job
+// @Description Progress and outcome of a WordPress import. A job whose server stopped before it finished is reported as interrupted; start the import again to finish it.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} wordpressdomain.ImportJob
+// @Failure 401 {object} handlers.UnauthorizedRes
+// @Failure 403 {object} handlers.ForbiddenRes
+// @Failure 404 {object} handlers.NotFoundRes
+// @Failure 500 {object} handlers.InternalServerErrRes
+// @Router /admin/import/jobs/{job_id} [get]
+func (h *handler) GetJob(ctx *fiber.Ctx) error {
+	job, err := h.uc.GetJob(ctx.Context(), ctx.Params(handlers.ParamKeyJobID))
+	if err != nil {
+		if errors.Is(err, errs.ErrImportJobNotFound) {
+			return handlers.NotFound(ctx, err.Error())
+		}
+		return handlers.InternalServerError(ctx, err)
+	}
+	return handlers.Success(ctx, job)
+}
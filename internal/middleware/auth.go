@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/codepnw/blog-api/internal/handlers"
 	jwttoken "github.com/codepnw/blog-api/internal/utils/jwt"
@@ -45,6 +47,25 @@ func (m *AppMiddleware) Authorized() fiber.Handler {
 	}
 }
 
+// OptionalAuthorized sets the user context when a valid bearer token is sent,
+// and lets anonymous requests through untouched.
+func (m *AppMiddleware) OptionalAuthorized() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		parts := strings.Split(ctx.Get("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return ctx.Next()
+		}
+
+		claims, err := m.token.VerifyAccessToken(parts[1])
+		if err != nil {
+			return ctx.Next()
+		}
+
+		ctx.Locals(UserContextKey, claims)
+		return ctx.Next()
+	}
+}
+
 func (m *AppMiddleware) RoleRequired(roles ...string) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		userCtx := ctx.Locals(UserContextKey)
@@ -70,6 +91,48 @@ func (m *AppMiddleware) RoleRequired(roles ...string) fiber.Handler {
 	}
 }
 
+// IfMatchRequired rejects writes that don't say which version of the
+// resource they were based on.
+func (m *AppMiddleware) IfMatchRequired() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if ctx.Get(fiber.HeaderIfMatch) == "" {
+			return handlers.PreconditionRequired(ctx, "If-Match header is required")
+		}
+		return ctx.Next()
+	}
+}
+
+// CachePolicy sets Cache-Control on GET responses of a route group. Anonymous
+// successful responses may be kept by shared caches for maxAge, while anything
+// sent with credentials stays private and must be revalidated. A policy set
+// closer to the handler wins over the one of an enclosing group.
+func (m *AppMiddleware) CachePolicy(maxAge time.Duration) fiber.Handler {
+	public := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+
+	return func(ctx *fiber.Ctx) error {
+		if ctx.Method() != fiber.MethodGet && ctx.Method() != fiber.MethodHead {
+			return ctx.Next()
+		}
+
+		err := ctx.Next()
+		ctx.Vary(fiber.HeaderAuthorization)
+		if len(ctx.Response().Header.Peek(fiber.HeaderCacheControl)) > 0 {
+			return err
+		}
+
+		status := ctx.Response().StatusCode()
+		switch {
+		case ctx.Get(fiber.HeaderAuthorization) != "" || ctx.Locals(UserContextKey) != nil:
+			ctx.Set(fiber.HeaderCacheControl, "private, no-cache")
+		case err == nil && (status == fiber.StatusOK || status == fiber.StatusNotModified):
+			ctx.Set(fiber.HeaderCacheControl, public)
+		default:
+			ctx.Set(fiber.HeaderCacheControl, "no-store")
+		}
+		return err
+	}
+}
+
 func GetCurrentUser(ctx *fiber.Ctx) (*jwttoken.UserClaims, error) {
 	userCtx := ctx.Locals(UserContextKey)
 	if userCtx == nil {
@@ -0,0 +1,39 @@
+// Package notify tells users about things that need their attention, such
+// as a post waiting for review. Notifier is the extension point for mail,
+// chat or push delivery; the log notifier is used until one is plugged in.
+package notify
+
+import (
+	"context"
+
+	"github.com/codepnw/blog-api/internal/utils/logger"
+)
+
+type Notification struct {
+	// Event names what happened, e.g. "post.submitted".
+	Event string
+	// Recipients are the IDs of the users to notify.
+	Recipients []string
+	Subject    string
+	Message    string
+	// PostID is the post the notification is about, if any.
+	PostID string
+}
+
+// Notifier delivers notifications. Delivery happens after the change it
+// reports was saved, so a failure is logged rather than undoing the change.
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification) error
+}
+
+type logNotifier struct{}
+
+// NewLogNotifier writes notifications to the log instead of delivering them.
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(ctx context.Context, n *Notification) error {
+	logger.Info("notify: "+n.Subject, "event", n.Event, "post_id", n.PostID, "recipients", n.Recipients)
+	return nil
+}
@@ -0,0 +1,112 @@
+package analyticsrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	analyticsdomain "github.com/codepnw/blog-api/internal/domains/analytics"
+)
+
+const dateLayout = "2006-01-02"
+
+type Repository interface {
+	InsertViews(ctx context.Context, views []*analyticsdomain.View) error
+	DailyViews(ctx context.Context, postID string, from, to time.Time) ([]*analyticsdomain.DailyViews, error)
+	Referrers(ctx context.Context, postID string, from, to time.Time, limit int) ([]*analyticsdomain.ReferrerViews, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// InsertViews writes a batch in one statement. Repeat visits of the same day
+// and views of posts deleted in the meantime are skipped.
+func (r *repository) InsertViews(ctx context.Context, views []*analyticsdomain.View) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	var (
+		sb   strings.Builder
+		args = make([]any, 0, len(views)*4)
+	)
+	sb.WriteString(`
+		INSERT INTO post_views (post_id, day, visitor_hash, referrer)
+		SELECT v.post_id, v.day, v.visitor_hash, v.referrer
+		FROM (VALUES `)
+
+	for i, v := range views {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		n := i * 4
+		sb.WriteString(fmt.Sprintf("($%d::UUID, $%d::DATE, $%d, $%d)", n+1, n+2, n+3, n+4))
+		args = append(args, v.PostID, v.Day.Format(dateLayout), v.VisitorHash, v.Referrer)
+	}
+
+	sb.WriteString(`) AS v(post_id, day, visitor_hash, referrer)
+		JOIN posts p ON p.id = v.post_id
+		ON CONFLICT DO NOTHING
+	`)
+
+	_, err := r.db.ExecContext(ctx, sb.String(), args...)
+	return err
+}
+
+// DailyViews returns the days between from and to that have views.
+func (r *repository) DailyViews(ctx context.Context, postID string, from, to time.Time) ([]*analyticsdomain.DailyViews, error) {
+	query := `
+		SELECT TO_CHAR(day, 'YYYY-MM-DD'), COUNT(*) FROM post_views
+		WHERE post_id = $1 AND day BETWEEN $2 AND $3
+		GROUP BY day
+		ORDER BY day
+	`
+	rows, err := r.db.QueryContext(ctx, query, postID, from.Format(dateLayout), to.Format(dateLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []*analyticsdomain.DailyViews
+	for rows.Next() {
+		d := new(analyticsdomain.DailyViews)
+		if err := rows.Scan(&d.Date, &d.Views); err != nil {
+			return nil, err
+		}
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
+
+// Referrers counts visitor days by the referrer of their first view that day.
+func (r *repository) Referrers(ctx context.Context, postID string, from, to time.Time, limit int) ([]*analyticsdomain.ReferrerViews, error) {
+	query := `
+		SELECT referrer, COUNT(*) FROM post_views
+		WHERE post_id = $1 AND day BETWEEN $2 AND $3
+		GROUP BY referrer
+		ORDER BY COUNT(*) DESC, referrer
+		LIMIT $4
+	`
+	rows, err := r.db.QueryContext(ctx, query, postID, from.Format(dateLayout), to.Format(dateLayout), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var referrers []*analyticsdomain.ReferrerViews
+	for rows.Next() {
+		ref := new(analyticsdomain.ReferrerViews)
+		if err := rows.Scan(&ref.Referrer, &ref.Views); err != nil {
+			return nil, err
+		}
+		referrers = append(referrers, ref)
+	}
+	return referrers, rows.Err()
+}
@@ -0,0 +1,116 @@
+package bookmarkrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	bookmarkdomain "github.com/codepnw/blog-api/internal/domains/bookmark"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	// Bookmarks
+	AddBookmark(ctx context.Context, userID, postID string) error
+	RemoveBookmark(ctx context.Context, userID, postID string) error
+	// ListBookmarks returns the bookmarked post ids, newest first.
+	ListBookmarks(ctx context.Context, userID string) ([]string, error)
+	FindBookmarked(ctx context.Context, userID string, postIDs []string) (map[string]bool, error)
+
+	// Reading Lists
+	InsertList(ctx context.Context, input *bookmarkdomain.ReadingList) (*bookmarkdomain.ReadingList, error)
+	FindListByID(ctx context.Context, id string) (*bookmarkdomain.ReadingList, error)
+	FindListByToken(ctx context.Context, token string) (*bookmarkdomain.ReadingList, error)
+	ListByOwner(ctx context.Context, ownerID string) ([]*bookmarkdomain.ReadingList, error)
+	UpdateList(ctx context.Context, input *bookmarkdomain.ReadingList) (*bookmarkdomain.ReadingList, error)
+	DeleteList(ctx context.Context, id string) error
+	AddListItem(ctx context.Context, listID, postID string) error
+	RemoveListItem(ctx context.Context, listID, postID string) error
+	// ListItems returns the post ids of a list in the order they were added.
+	ListItems(ctx context.Context, listID string) ([]string, error)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewBookmarkRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) AddBookmark(ctx context.Context, userID, postID string) error {
+	query := `
+		INSERT INTO bookmarks (user_id, post_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, postID)
+	return postError(err)
+}
+
+func (r *repository) RemoveBookmark(ctx context.Context, userID, postID string) error {
+	query := `DELETE FROM bookmarks WHERE user_id = $1 AND post_id = $2`
+	_, err := r.db.ExecContext(ctx, query, userID, postID)
+	return err
+}
+
+func (r *repository) ListBookmarks(ctx context.Context, userID string) ([]string, error) {
+	query := `
+		SELECT post_id FROM bookmarks
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	return r.queryIDs(ctx, query, userID)
+}
+
+func (r *repository) FindBookmarked(ctx context.Context, userID string, postIDs []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT post_id FROM bookmarks
+		WHERE user_id = $1 AND post_id = ANY($2::UUID[])
+	`
+	ids, err := r.queryIDs(ctx, query, userID, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+func (r *repository) queryIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// postError maps a foreign key violation on post_id, a post deleted in the
+// meantime, to errs.ErrPostNotFound.
+func postError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return errs.ErrPostNotFound
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+package bookmarkrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	bookmarkdomain "github.com/codepnw/blog-api/internal/domains/bookmark"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+const listColumns = `l.id, l.owner_id, l.name, l.description, l.is_public, l.share_token,
+	(SELECT COUNT(*) FROM reading_list_items i WHERE i.list_id = l.id), l.created_at, l.updated_at`
+
+func (r *repository) InsertList(ctx context.Context, input *bookmarkdomain.ReadingList) (*bookmarkdomain.ReadingList, error) {
+	query := `
+		INSERT INTO reading_lists (owner_id, name, description, is_public, share_token)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	list := *input
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		list.OwnerID,
+		list.Name,
+		list.Description,
+		list.Public,
+		list.ShareToken,
+	).Scan(&list.ID, &list.CreatedAt, &list.UpdatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *repository) FindListByID(ctx context.Context, id string) (*bookmarkdomain.ReadingList, error) {
+	query := `SELECT ` + listColumns + ` FROM reading_lists l WHERE l.id = $1`
+	return r.findList(ctx, query, id)
+}
+
+func (r *repository) FindListByToken(ctx context.Context, token string) (*bookmarkdomain.ReadingList, error) {
+	query := `SELECT ` + listColumns + ` FROM reading_lists l WHERE l.share_token = $1`
+	return r.findList(ctx, query, token)
+}
+
+func (r *repository) findList(ctx context.Context, query string, arg string) (*bookmarkdomain.ReadingList, error) {
+	list, err := r.scanList(r.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrReadingListNotFound
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repository) ListByOwner(ctx context.Context, ownerID string) ([]*bookmarkdomain.ReadingList, error) {
+	query := `
+		SELECT ` + listColumns + ` FROM reading_lists l
+		WHERE l.owner_id = $1
+		ORDER BY l.created_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lists []*bookmarkdomain.ReadingList
+	for rows.Next() {
+		list, err := r.scanList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return lists, rows.Err()
+}
+
+func (r *repository) UpdateList(ctx context.Context, input *bookmarkdomain.ReadingList) (*bookmarkdomain.ReadingList, error) {
+	query := `
+		UPDATE reading_lists l
+		SET name = $1, description = $2, is_public = $3, updated_at = NOW()
+		WHERE l.id = $4
+		RETURNING ` + listColumns
+
+	list, err := r.scanList(r.db.QueryRowContext(ctx, query, input.Name, input.Description, input.Public, input.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrReadingListNotFound
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *repository) DeleteList(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM reading_lists WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrReadingListNotFound
+	}
+	return nil
+}
+
+func (r *repository) AddListItem(ctx context.Context, listID, postID string) error {
+	query := `
+		INSERT INTO reading_list_items (list_id, post_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, listID, postID)
+	return postError(err)
+}
+
+func (r *repository) RemoveListItem(ctx context.Context, listID, postID string) error {
+	query := `DELETE FROM reading_list_items WHERE list_id = $1 AND post_id = $2`
+	_, err := r.db.ExecContext(ctx, query, listID, postID)
+	return err
+}
+
+func (r *repository) ListItems(ctx context.Context, listID string) ([]string, error) {
+	query := `
+		SELECT post_id FROM reading_list_items
+		WHERE list_id = $1
+		ORDER BY created_at
+	`
+	return r.queryIDs(ctx, query, listID)
+}
+
+func (r *repository) scanList(row rowScanner) (*bookmarkdomain.ReadingList, error) {
+	list := new(bookmarkdomain.ReadingList)
+	err := row.Scan(
+		&list.ID,
+		&list.OwnerID,
+		&list.Name,
+		&list.Description,
+		&list.Public,
+		&list.ShareToken,
+		&list.PostCount,
+		&list.CreatedAt,
+		&list.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
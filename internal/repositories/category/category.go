@@ -8,14 +8,15 @@ import (
 	"strings"
 
 	categorydomain "github.com/codepnw/blog-api/internal/domains/category"
+	"github.com/codepnw/blog-api/internal/utils/errs"
 )
 
 type Repository interface {
 	Insert(ctx context.Context, input *categorydomain.Category) error
 	FindByID(ctx context.Context, id string) (*categorydomain.Category, error)
 	List(ctx context.Context) ([]*categorydomain.Category, error)
-	Update(ctx context.Context, input *categorydomain.Category) error
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, input *categorydomain.Category) (*categorydomain.Category, error)
+	Delete(ctx context.Context, id string, version int) error
 }
 
 type repository struct {
@@ -26,28 +27,33 @@ func NewCategoryRepository(db *sql.DB) Repository {
 	return &repository{db: db}
 }
 
+// Insert fills in the ID and version of the new category on input.
 func (r *repository) Insert(ctx context.Context, input *categorydomain.Category) error {
 	query := `
 		INSERT INTO categories (name, description)
 		VALUES ($1, $2)
+		RETURNING id, version
 	`
-	_, err := r.db.ExecContext(ctx, query, input.Name, input.Description)
-	return err
+	return r.db.QueryRowContext(ctx, query, input.Name, input.Description).Scan(&input.ID, &input.Version)
 }
 
 func (r *repository) FindByID(ctx context.Context, id string) (*categorydomain.Category, error) {
 	c := new(categorydomain.Category)
 
 	query := `
-		SELECT id, name, description
+		SELECT id, name, description, version
 		FROM categories WHERE id = $1 LIMIT 1
 	`
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&c.ID,
 		&c.Name,
 		&c.Description,
+		&c.Version,
 	)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrCategoryNotFound
+		}
 		return nil, err
 	}
 
@@ -55,7 +61,7 @@ func (r *repository) FindByID(ctx context.Context, id string) (*categorydomain.C
 }
 
 func (r *repository) List(ctx context.Context) ([]*categorydomain.Category, error) {
-	query := `SELECT id, name, description FROM categories`
+	query := `SELECT id, name, description, version FROM categories`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -69,6 +75,7 @@ func (r *repository) List(ctx context.Context) ([]*categorydomain.Category, erro
 			&c.ID,
 			&c.Name,
 			&c.Description,
+			&c.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -79,7 +86,9 @@ func (r *repository) List(ctx context.Context) ([]*categorydomain.Category, erro
 	return categories, rows.Err()
 }
 
-func (r *repository) Update(ctx context.Context, input *categorydomain.Category) error {
+// Update applies the non-empty fields of input. A non-zero input.Version makes
+// it a compare-and-swap that fails with errs.ErrVersionConflict when stale.
+func (r *repository) Update(ctx context.Context, input *categorydomain.Category) (*categorydomain.Category, error) {
 	var (
 		sb   strings.Builder
 		args []any
@@ -94,17 +103,39 @@ func (r *repository) Update(ctx context.Context, input *categorydomain.Category)
 	}
 
 	if input.Description != "" {
-		sb.WriteString(fmt.Sprintf(" description = $%d", idx))
+		sb.WriteString(fmt.Sprintf(" description = $%d,", idx))
 		args = append(args, input.Description)
 		idx++
 	}
 
-	sb.WriteString(fmt.Sprintf(" WHERE id = $%d", idx))
-	args = append(args, input.ID)
+	sb.WriteString(fmt.Sprintf(`
+		version = version + 1
+		WHERE id = $%d AND ($%d = 0 OR version = $%d)
+		RETURNING id, name, description, version
+	`, idx, idx+1, idx+1))
+	args = append(args, input.ID, input.Version)
 
 	query := sb.String()
 
-	res, err := r.db.ExecContext(ctx, query, args...)
+	c := new(categorydomain.Category)
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&c.ID,
+		&c.Name,
+		&c.Description,
+		&c.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.notFoundOrConflict(ctx, input.ID)
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string, version int) error {
+	query := "DELETE FROM categories WHERE id = $1 AND ($2 = 0 OR version = $2)"
+	res, err := r.db.ExecContext(ctx, query, id, version)
 	if err != nil {
 		return err
 	}
@@ -115,24 +146,21 @@ func (r *repository) Update(ctx context.Context, input *categorydomain.Category)
 	}
 
 	if rows == 0 {
-		return errors.New("category not found")
+		return r.notFoundOrConflict(ctx, id)
 	}
 	return nil
 }
 
-func (r *repository) Delete(ctx context.Context, id string) error {
-	res, err := r.db.ExecContext(ctx, "DELETE FROM categories WHERE id = $1", id)
-	if err != nil {
+// notFoundOrConflict tells a missing category apart from a stale version
+// after a compare-and-swap matched no row.
+func (r *repository) notFoundOrConflict(ctx context.Context, id string) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)`
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
 		return err
 	}
-
-	rows, err := res.RowsAffected()
-	if err != nil {
-		return err
+	if exists {
+		return errs.ErrVersionConflict
 	}
-
-	if rows == 0 {
-		return errors.New("category not found")
-	}
-	return nil
+	return errs.ErrCategoryNotFound
 }
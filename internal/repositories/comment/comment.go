@@ -11,12 +11,14 @@ import (
 )
 
 type CommentModel struct {
-	ID        int64     `db:"id"`
-	PostID    string    `db:"post_id"`
-	UserID    string    `db:"user_id"`
-	Content   string    `db:"content"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID        int64      `db:"id"`
+	PostID    string     `db:"post_id"`
+	UserID    string     `db:"user_id"`
+	Content   string     `db:"content"`
+	Version   int        `db:"version"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+	DeletedAt *time.Time `db:"deleted_at"`
 }
 
 type Repository interface {
@@ -24,7 +26,13 @@ type Repository interface {
 	FindByID(ctx context.Context, id int64) (*commentdomain.Comment, error)
 	ListByPost(ctx context.Context, postID string) ([]*commentdomain.Comment, error)
 	Update(ctx context.Context, input *commentdomain.Comment) (*commentdomain.Comment, error)
-	Delete(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64, version int) error
+
+	// Trash
+	FindDeletedByID(ctx context.Context, id int64) (*commentdomain.Comment, error)
+	ListDeleted(ctx context.Context, userID string) ([]*commentdomain.Comment, error)
+	Restore(ctx context.Context, id int64) (*commentdomain.Comment, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 type repository struct {
@@ -39,10 +47,11 @@ func (r *repository) Insert(ctx context.Context, input *commentdomain.Comment) (
 	m := r.inputToModel(input)
 	query := `
 		INSERT INTO comments (post_id, user_id, content)
-		VALUES ($1, $2, $3) RETURNING id, created_at, updated_at
+		VALUES ($1, $2, $3) RETURNING id, version, created_at, updated_at
 	`
 	err := r.db.QueryRowContext(ctx, query, m.PostID, m.UserID, m.Content).Scan(
 		&m.ID,
+		&m.Version,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 	)
@@ -55,14 +64,16 @@ func (r *repository) Insert(ctx context.Context, input *commentdomain.Comment) (
 func (r *repository) FindByID(ctx context.Context, id int64) (*commentdomain.Comment, error) {
 	comment := new(commentdomain.Comment)
 	query := `
-		SELECT id, post_id, user_id, content, created_at, updated_at
-		FROM comments WHERE id = $1
+		SELECT c.id, c.post_id, c.user_id, c.content, c.version, c.created_at, c.updated_at
+		FROM comments c JOIN posts p ON p.id = c.post_id
+		WHERE c.id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
 	`
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&comment.ID,
 		&comment.PostID,
 		&comment.UserID,
 		&comment.Content,
+		&comment.Version,
 		&comment.CreatedAt,
 		&comment.UpdatedAt,
 	)
@@ -77,8 +88,10 @@ func (r *repository) FindByID(ctx context.Context, id int64) (*commentdomain.Com
 
 func (r *repository) ListByPost(ctx context.Context, postID string) ([]*commentdomain.Comment, error) {
 	query := `
-		SELECT id, post_id, user_id, content, created_at, updated_at
-		FROM comments WHERE post_id = $1
+		SELECT c.id, c.post_id, c.user_id, c.content, c.version, c.created_at, c.updated_at
+		FROM comments c JOIN posts p ON p.id = c.post_id
+		WHERE c.post_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+		ORDER BY c.created_at
 	`
 	rows, err := r.db.QueryContext(ctx, query, postID)
 	if err != nil {
@@ -94,6 +107,7 @@ func (r *repository) ListByPost(ctx context.Context, postID string) ([]*commentd
 			&c.PostID,
 			&c.UserID,
 			&c.Content,
+			&c.Version,
 			&c.CreatedAt,
 			&c.UpdatedAt,
 		)
@@ -105,32 +119,43 @@ func (r *repository) ListByPost(ctx context.Context, postID string) ([]*commentd
 	return comments, rows.Err()
 }
 
+// Update replaces the content. A non-zero input.Version makes it a
+// compare-and-swap that fails with errs.ErrVersionConflict when stale.
 func (r *repository) Update(ctx context.Context, input *commentdomain.Comment) (*commentdomain.Comment, error) {
 	m := r.inputToModel(input)
 	query := `
-		UPDATE comments SET content = $1
-		WHERE id = $2 AND post_id = $3
-		RETURNING id, post_id, user_id, content, created_at, updated_at
+		UPDATE comments c SET content = $1, version = c.version + 1, updated_at = NOW()
+		FROM posts p
+		WHERE c.id = $2 AND c.post_id = $3 AND p.id = c.post_id
+			AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+			AND ($4 = 0 OR c.version = $4)
+		RETURNING c.id, c.post_id, c.user_id, c.content, c.version, c.created_at, c.updated_at
 	`
-	err := r.db.QueryRowContext(ctx, query, m.Content, m.ID, m.PostID).Scan(
+	err := r.db.QueryRowContext(ctx, query, m.Content, m.ID, m.PostID, m.Version).Scan(
 		&m.ID,
 		&m.PostID,
 		&m.UserID,
 		&m.Content,
+		&m.Version,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errs.ErrCommentNotFound
+			return nil, r.notFoundOrConflict(ctx, m.ID)
 		}
 		return nil, err
 	}
 	return r.modelToDomain(m), nil
 }
 
-func (r *repository) Delete(ctx context.Context, id int64) error {
-	res, err := r.db.ExecContext(ctx, "DELETE FROM comments WHERE id = $1", id)
+// Delete moves the comment to the trash.
+func (r *repository) Delete(ctx context.Context, id int64, version int) error {
+	query := `
+		UPDATE comments SET deleted_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)
+	`
+	res, err := r.db.ExecContext(ctx, query, id, version)
 	if err != nil {
 		return err
 	}
@@ -141,19 +166,30 @@ func (r *repository) Delete(ctx context.Context, id int64) error {
 	}
 
 	if rows == 0 {
-		return errs.ErrCommentNotFound
+		return r.notFoundOrConflict(ctx, id)
 	}
 	return nil
 }
 
+// notFoundOrConflict tells a missing comment apart from a stale version after
+// a compare-and-swap matched no row.
+func (r *repository) notFoundOrConflict(ctx context.Context, id int64) error {
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return errs.ErrVersionConflict
+}
+
 func (r *repository) inputToModel(input *commentdomain.Comment) *CommentModel {
 	return &CommentModel{
 		ID:        input.ID,
 		PostID:    input.PostID,
 		UserID:    input.UserID,
 		Content:   input.Content,
+		Version:   input.Version,
 		CreatedAt: input.CreatedAt,
 		UpdatedAt: input.UpdatedAt,
+		DeletedAt: input.DeletedAt,
 	}
 }
 
@@ -163,7 +199,9 @@ func (r *repository) modelToDomain(input *CommentModel) *commentdomain.Comment {
 		PostID:    input.PostID,
 		UserID:    input.UserID,
 		Content:   input.Content,
+		Version:   input.Version,
 		CreatedAt: input.CreatedAt,
 		UpdatedAt: input.UpdatedAt,
+		DeletedAt: input.DeletedAt,
 	}
 }
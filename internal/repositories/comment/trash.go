@@ -0,0 +1,105 @@
+package commentrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	commentdomain "github.com/codepnw/blog-api/internal/domains/comment"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+func (r *repository) FindDeletedByID(ctx context.Context, id int64) (*commentdomain.Comment, error) {
+	m := new(CommentModel)
+	query := `
+		SELECT id, post_id, user_id, content, created_at, updated_at, deleted_at
+		FROM comments WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&m.ID,
+		&m.PostID,
+		&m.UserID,
+		&m.Content,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+		&m.DeletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return r.modelToDomain(m), nil
+}
+
+// ListDeleted returns the trashed comments of userID, or of everyone when
+// userID is empty.
+func (r *repository) ListDeleted(ctx context.Context, userID string) ([]*commentdomain.Comment, error) {
+	query := `
+		SELECT id, post_id, user_id, content, created_at, updated_at, deleted_at
+		FROM comments
+		WHERE deleted_at IS NOT NULL AND ($1 = '' OR user_id::TEXT = $1)
+		ORDER BY deleted_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*commentdomain.Comment
+	for rows.Next() {
+		m := new(CommentModel)
+		err = rows.Scan(
+			&m.ID,
+			&m.PostID,
+			&m.UserID,
+			&m.Content,
+			&m.CreatedAt,
+			&m.UpdatedAt,
+			&m.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, r.modelToDomain(m))
+	}
+	return comments, rows.Err()
+}
+
+func (r *repository) Restore(ctx context.Context, id int64) (*commentdomain.Comment, error) {
+	m := new(CommentModel)
+	query := `
+		UPDATE comments SET deleted_at = NULL, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id, post_id, user_id, content, version, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&m.ID,
+		&m.PostID,
+		&m.UserID,
+		&m.Content,
+		&m.Version,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return r.modelToDomain(m), nil
+}
+
+// Purge hard-deletes comments that have been in the trash since before
+// deletedBefore.
+func (r *repository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM comments WHERE deleted_at < $1", deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,218 @@
+package mediarepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	mediadomain "github.com/codepnw/blog-api/internal/domains/media"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+const mediaColumns = `id, uploader_id, storage_key, filename, mime_type, size_bytes,
+	width, height, variants, created_at`
+
+type mediaModel struct {
+	ID         string    `db:"id"`
+	UploaderID string    `db:"uploader_id"`
+	StorageKey string    `db:"storage_key"`
+	Filename   string    `db:"filename"`
+	MimeType   string    `db:"mime_type"`
+	Size       int64     `db:"size_bytes"`
+	Width      int       `db:"width"`
+	Height     int       `db:"height"`
+	Variants   []byte    `db:"variants"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// variantModel is how a variant is kept in the variants JSON column. URLs
+// are not stored, they depend on the storage configuration.
+type variantModel struct {
+	Name       string `json:"name"`
+	StorageKey string `json:"storage_key"`
+	MimeType   string `json:"mime_type"`
+	Size       int64  `json:"size"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+}
+
+type Repository interface {
+	Insert(ctx context.Context, input *mediadomain.Media) (*mediadomain.Media, error)
+	FindByID(ctx context.Context, id string) (*mediadomain.Media, error)
+	ListByUploader(ctx context.Context, uploaderID string) ([]*mediadomain.Media, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewMediaRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Insert(ctx context.Context, input *mediadomain.Media) (*mediadomain.Media, error) {
+	m := r.inputToModel(input)
+	query := `
+		INSERT INTO media (id, uploader_id, storage_key, filename, mime_type, size_bytes,
+			width, height, variants)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at
+	`
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		m.ID,
+		m.UploaderID,
+		m.StorageKey,
+		m.Filename,
+		m.MimeType,
+		m.Size,
+		m.Width,
+		m.Height,
+		m.Variants,
+	).Scan(&m.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+	return r.modelToDomain(m), nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*mediadomain.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE id = $1`
+
+	media, err := r.scanMedia(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrMediaNotFound
+		}
+		return nil, err
+	}
+	return media, nil
+}
+
+func (r *repository) ListByUploader(ctx context.Context, uploaderID string) ([]*mediadomain.Media, error) {
+	query := `
+		SELECT ` + mediaColumns + ` FROM media
+		WHERE uploader_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, uploaderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var media []*mediadomain.Media
+	for rows.Next() {
+		m, err := r.scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		media = append(media, m)
+	}
+	return media, rows.Err()
+}
+
+// Delete removes the row only. Posts using it as cover are left without one.
+func (r *repository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM media WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errs.ErrMediaNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *repository) scanMedia(row rowScanner) (*mediadomain.Media, error) {
+	m := new(mediaModel)
+	err := row.Scan(
+		&m.ID,
+		&m.UploaderID,
+		&m.StorageKey,
+		&m.Filename,
+		&m.MimeType,
+		&m.Size,
+		&m.Width,
+		&m.Height,
+		&m.Variants,
+		&m.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r.modelToDomain(m), nil
+}
+
+func (r *repository) inputToModel(input *mediadomain.Media) *mediaModel {
+	variants := make([]variantModel, 0, len(input.Variants))
+	for _, v := range input.Variants {
+		variants = append(variants, variantModel{
+			Name:       v.Name,
+			StorageKey: v.StorageKey,
+			MimeType:   v.MimeType,
+			Size:       v.Size,
+			Width:      v.Width,
+			Height:     v.Height,
+		})
+	}
+	variantsJSON, _ := json.Marshal(variants)
+
+	return &mediaModel{
+		ID:         input.ID,
+		UploaderID: input.UploaderID,
+		StorageKey: input.StorageKey,
+		Filename:   input.Filename,
+		MimeType:   input.MimeType,
+		Size:       input.Size,
+		Width:      input.Width,
+		Height:     input.Height,
+		Variants:   variantsJSON,
+		CreatedAt:  input.CreatedAt,
+	}
+}
+
+func (r *repository) modelToDomain(input *mediaModel) *mediadomain.Media {
+	media := &mediadomain.Media{
+		ID:         input.ID,
+		UploaderID: input.UploaderID,
+		StorageKey: input.StorageKey,
+		Filename:   input.Filename,
+		MimeType:   input.MimeType,
+		Size:       input.Size,
+		Width:      input.Width,
+		Height:     input.Height,
+		Variants:   []mediadomain.Variant{},
+		CreatedAt:  input.CreatedAt,
+	}
+
+	var variants []variantModel
+	if len(input.Variants) > 0 {
+		_ = json.Unmarshal(input.Variants, &variants)
+	}
+	for _, v := range variants {
+		media.Variants = append(media.Variants, mediadomain.Variant{
+			Name:       v.Name,
+			StorageKey: v.StorageKey,
+			MimeType:   v.MimeType,
+			Size:       v.Size,
+			Width:      v.Width,
+			Height:     v.Height,
+		})
+	}
+	return media
+}
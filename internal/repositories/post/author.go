@@ -0,0 +1,63 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+// SetAuthors replaces the bylines of a post, in the given order. Changing
+// the authors is a new version of the post; a non-zero version makes it a
+// compare-and-swap.
+func (r *repository) SetAuthors(ctx context.Context, id string, authors []postdomain.Byline, version int) (*postdomain.Post, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var postID string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE posts SET version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)
+		RETURNING id
+	`, id, version).Scan(&postID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, r.notFoundOrConflict(ctx, id)
+		}
+		return nil, err
+	}
+
+	if err := r.keepSitemapPages(ctx, tx, id, scopeAuthor); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_authors WHERE post_id = $1`, id); err != nil {
+		return nil, err
+	}
+	for i, author := range authors {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO post_authors (post_id, user_id, role, position)
+			VALUES ($1, $2, $3, $4)
+		`, id, author.UserID, author.Role, i+1)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				return nil, errs.ErrUserNotFound
+			}
+			return nil, err
+		}
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM posts WHERE id = $1`, postColumns)
+	post, err := r.scanPost(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+	return post, tx.Commit()
+}
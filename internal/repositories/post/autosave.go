@@ -0,0 +1,89 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+// SaveAutosave replaces the autosave of a user for a post. The base version
+// is only taken from input when the autosave is new or rebase is set, so
+// saving again doesn't hide edits made to the post in between.
+func (r *repository) SaveAutosave(ctx context.Context, input *postdomain.Autosave, rebase bool) (*postdomain.Autosave, error) {
+	query := `
+		INSERT INTO post_autosaves (post_id, user_id, title, content, content_format, base_version)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (post_id, user_id) DO UPDATE
+		SET title = EXCLUDED.title, content = EXCLUDED.content, content_format = EXCLUDED.content_format,
+			base_version = CASE WHEN $7 THEN EXCLUDED.base_version ELSE post_autosaves.base_version END,
+			saved_at = NOW()
+		RETURNING base_version, saved_at
+	`
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		input.PostID,
+		input.UserID,
+		input.Title,
+		input.Content,
+		input.ContentFormat,
+		input.BaseVersion,
+		rebase,
+	).Scan(&input.BaseVersion, &input.SavedAt)
+	if err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+func (r *repository) FindAutosave(ctx context.Context, postID, userID string) (*postdomain.Autosave, error) {
+	query := `
+		SELECT post_id, user_id, title, content, content_format, base_version, saved_at
+		FROM post_autosaves WHERE post_id = $1 AND user_id = $2
+	`
+	a := new(postdomain.Autosave)
+	err := r.db.QueryRowContext(ctx, query, postID, userID).Scan(
+		&a.PostID,
+		&a.UserID,
+		&a.Title,
+		&a.Content,
+		&a.ContentFormat,
+		&a.BaseVersion,
+		&a.SavedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrAutosaveNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *repository) DeleteAutosave(ctx context.Context, postID, userID string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM post_autosaves WHERE post_id = $1 AND user_id = $2`, postID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errs.ErrAutosaveNotFound
+	}
+	return nil
+}
+
+// PurgeAutosaves deletes the autosaves last saved before savedBefore.
+func (r *repository) PurgeAutosaves(ctx context.Context, savedBefore time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM post_autosaves WHERE saved_at < $1", savedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
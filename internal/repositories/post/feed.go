@@ -0,0 +1,42 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+// ListPublished returns the latest listed posts matching filter, newest
+// first. Tags match case-insensitively.
+func (r *repository) ListPublished(ctx context.Context, filter postdomain.FeedFilter, limit int) ([]*postdomain.Post, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM posts
+		WHERE %s AND deleted_at IS NULL
+			AND ($1 = '' OR category_id::TEXT = $1)
+			AND ($2 = '' OR %s)
+			AND ($3 = '' OR EXISTS (
+				SELECT 1 FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.post_id = posts.id AND LOWER(t.name) = LOWER($3)
+			))
+		ORDER BY published_at DESC
+		LIMIT $4
+	`, postColumns, listedCond, fmt.Sprintf(isAuthorCond, 2))
+
+	return r.queryPosts(ctx, query, filter.CategoryID, filter.AuthorID, filter.Tag, limit)
+}
+
+// FindTag returns the stored spelling of the tag called name.
+func (r *repository) FindTag(ctx context.Context, name string) (string, error) {
+	var tag string
+	err := r.db.QueryRowContext(ctx, `SELECT name FROM tags WHERE LOWER(name) = LOWER($1) LIMIT 1`, name).Scan(&tag)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errs.ErrTagNotFound
+		}
+		return "", err
+	}
+	return tag, nil
+}
@@ -0,0 +1,67 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+// AcquireLock takes the edit lease of a post for ttl, or renews it when
+// userID already holds it. A lease held by someone else fails with
+// errs.ErrPostLocked until it expires.
+func (r *repository) AcquireLock(ctx context.Context, postID, userID string, ttl time.Duration) (*postdomain.Lock, error) {
+	query := `
+		INSERT INTO post_locks (post_id, user_id, expires_at)
+		VALUES ($1, $2, NOW() + make_interval(secs => $3))
+		ON CONFLICT (post_id) DO UPDATE
+		SET user_id = EXCLUDED.user_id, expires_at = EXCLUDED.expires_at,
+			acquired_at = CASE
+				WHEN post_locks.user_id = EXCLUDED.user_id AND post_locks.expires_at > NOW() THEN post_locks.acquired_at
+				ELSE NOW()
+			END
+		WHERE post_locks.user_id = EXCLUDED.user_id OR post_locks.expires_at <= NOW()
+		RETURNING post_id
+	`
+	var id string
+	if err := r.db.QueryRowContext(ctx, query, postID, userID, ttl.Seconds()).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrPostLocked
+		}
+		return nil, err
+	}
+	return r.FindLock(ctx, postID)
+}
+
+// FindLock returns the lease of a post, if it hasn't expired.
+func (r *repository) FindLock(ctx context.Context, postID string) (*postdomain.Lock, error) {
+	query := `
+		SELECT l.post_id, l.user_id, u.first_name, u.last_name, l.acquired_at, l.expires_at
+		FROM post_locks l JOIN users u ON u.id = l.user_id
+		WHERE l.post_id = $1 AND l.expires_at > NOW()
+	`
+	lock := new(postdomain.Lock)
+	err := r.db.QueryRowContext(ctx, query, postID).Scan(
+		&lock.PostID,
+		&lock.UserID,
+		&lock.FirstName,
+		&lock.LastName,
+		&lock.AcquiredAt,
+		&lock.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrLockNotFound
+		}
+		return nil, err
+	}
+	return lock, nil
+}
+
+func (r *repository) DeleteLock(ctx context.Context, postID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM post_locks WHERE post_id = $1`, postID)
+	return err
+}
@@ -0,0 +1,137 @@
+package postrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+const pinColumns = `post_id, category_id, position, expires_at, pinned_by, created_at, updated_at`
+
+// pinScopeCond matches the pins of the category in the given parameter, or
+// the site-wide pins when it is empty.
+const pinScopeCond = `COALESCE(category_id::TEXT, '') = $%d`
+
+// activePinCond leaves out the expired pins.
+const activePinCond = `(expires_at IS NULL OR expires_at > NOW())`
+
+// UpsertPin pins the post in the scope of input.CategoryID, or moves and
+// renews an existing pin. Pins at or after the position move down by one;
+// a zero position puts the post after the last pin.
+func (r *repository) UpsertPin(ctx context.Context, input *postdomain.Pin) (*postdomain.Pin, error) {
+	scope := ""
+	if input.CategoryID != nil {
+		scope = *input.CategoryID
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Pinning twice at once would hand out the same position.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('post_pins:' || $1))`, scope); err != nil {
+		return nil, err
+	}
+
+	position := input.Position
+	if position == 0 {
+		query := `SELECT COALESCE(MAX(position), 0) + 1 FROM post_pins WHERE ` + fmt.Sprintf(pinScopeCond, 1) + ` AND post_id <> $2`
+		if err := tx.QueryRowContext(ctx, query, scope, input.PostID).Scan(&position); err != nil {
+			return nil, err
+		}
+	} else {
+		query := `UPDATE post_pins SET position = position + 1 WHERE ` + fmt.Sprintf(pinScopeCond, 1) + ` AND position >= $2 AND post_id <> $3`
+		if _, err := tx.ExecContext(ctx, query, scope, position, input.PostID); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		INSERT INTO post_pins (post_id, category_id, position, expires_at, pinned_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ((COALESCE(category_id::TEXT, '')), post_id) DO UPDATE
+		SET position = EXCLUDED.position, expires_at = EXCLUDED.expires_at,
+			pinned_by = EXCLUDED.pinned_by, updated_at = NOW()
+		RETURNING ` + pinColumns
+
+	pin, err := r.scanPin(tx.QueryRowContext(ctx, query, input.PostID, r.validateCategoryID(input.CategoryID), position, input.ExpiresAt, input.PinnedBy))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			switch pqErr.Constraint {
+			case "post_pins_post_id_fkey":
+				return nil, errs.ErrPostNotFound
+			case "post_pins_category_id_fkey":
+				return nil, errs.ErrCategoryNotFound
+			}
+		}
+		return nil, err
+	}
+	return pin, tx.Commit()
+}
+
+// DeletePin unpins the post from the category, or from the homepage when
+// categoryID is empty.
+func (r *repository) DeletePin(ctx context.Context, postID, categoryID string) error {
+	query := `DELETE FROM post_pins WHERE post_id = $2 AND ` + fmt.Sprintf(pinScopeCond, 1)
+	res, err := r.db.ExecContext(ctx, query, categoryID, postID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errs.ErrPinNotFound
+	}
+	return nil
+}
+
+// ListPins returns the pins of the category, or the site-wide pins when
+// categoryID is empty, in display order. Expired pins are left out.
+func (r *repository) ListPins(ctx context.Context, categoryID string) ([]*postdomain.Pin, error) {
+	query := `
+		SELECT ` + pinColumns + ` FROM post_pins
+		WHERE ` + fmt.Sprintf(pinScopeCond, 1) + ` AND ` + activePinCond + `
+		ORDER BY position, updated_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pins []*postdomain.Pin
+	for rows.Next() {
+		pin, err := r.scanPin(rows)
+		if err != nil {
+			return nil, err
+		}
+		pins = append(pins, pin)
+	}
+	return pins, rows.Err()
+}
+
+func (r *repository) scanPin(row rowScanner) (*postdomain.Pin, error) {
+	pin := new(postdomain.Pin)
+	err := row.Scan(
+		&pin.PostID,
+		&pin.CategoryID,
+		&pin.Position,
+		&pin.ExpiresAt,
+		&pin.PinnedBy,
+		&pin.CreatedAt,
+		&pin.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pin, nil
+}
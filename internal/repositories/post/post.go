@@ -3,31 +3,170 @@ package postrepo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	postdomain "github.com/codepnw/blog-api/internal/domains/post"
 	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
 )
 
+// authorsColumn aggregates the bylines of the post in the current row.
+const authorsColumn = `(SELECT COALESCE(jsonb_agg(jsonb_build_object(
+		'user_id', pa.user_id, 'first_name', u.first_name, 'last_name', u.last_name, 'role', pa.role
+	) ORDER BY pa.position), '[]')
+	FROM post_authors pa JOIN users u ON u.id = pa.user_id
+	WHERE pa.post_id = posts.id)`
+
+// tagsColumn lists the tag names of the post in the current row.
+const tagsColumn = `(SELECT COALESCE(array_agg(t.name ORDER BY t.name), '{}')
+	FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+	WHERE pt.post_id = posts.id)`
+
+// localesColumn lists the locales the post in the current row is available
+// in, its own first.
+const localesColumn = `ARRAY[posts.locale] || (SELECT COALESCE(array_agg(tr.locale ORDER BY tr.locale), '{}')
+	FROM post_translations tr WHERE tr.post_id = posts.id)`
+
+// localeCond matches the posts written in or translated into the locale in
+// the given parameter, or every post when it is empty.
+const localeCond = `($%[1]d = '' OR locale = $%[1]d
+	OR EXISTS (SELECT 1 FROM post_translations tr WHERE tr.post_id = posts.id AND tr.locale = $%[1]d))`
+
+// listedCond matches the published posts that appear in listings, leaving
+// out unlisted and private ones.
+const listedCond = `(status = 'published' AND visibility IN ('public', 'members'))`
+
+// isAuthorCond matches the posts the user in the given parameter owns or
+// co-authors.
+const isAuthorCond = `(author_id::TEXT = $%[1]d
+	OR EXISTS (SELECT 1 FROM post_authors pa WHERE pa.post_id = posts.id AND pa.user_id::TEXT = $%[1]d))`
+
+const postColumns = `id, author_id, title, content, content_format, content_html, content_toc,
+	excerpt, word_count, reading_time_minutes, category_id, cover_media_id, status, published_at,
+	reaction_counts, reacted_at, version, created_at, updated_at, deleted_at, ` + authorsColumn + `,
+	slug, ` + tagsColumn + `, locale, ` + localesColumn + `, visibility`
+
+// summaryColumns leave out the body for list endpoints.
+const summaryColumns = `id, author_id, title, excerpt, word_count, reading_time_minutes,
+	category_id, cover_media_id, status, published_at, reaction_counts, reacted_at,
+	version, created_at, updated_at, ` + authorsColumn + `, locale, visibility`
+
 type postModel struct {
-	ID         string    `db:"id"`
-	AuthorID   string    `db:"author_id"`
-	Title      string    `db:"title"`
-	Content    string    `db:"content"`
-	CategoryID *string   `db:"category_id"`
-	CreatedAt  time.Time `db:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at"`
+	ID            string     `db:"id"`
+	AuthorID      string     `db:"author_id"`
+	Title         string     `db:"title"`
+	Content       string     `db:"content"`
+	ContentFormat string     `db:"content_format"`
+	ContentHTML   *string    `db:"content_html"`
+	ContentTOC    []byte     `db:"content_toc"`
+	Excerpt       string     `db:"excerpt"`
+	WordCount     int        `db:"word_count"`
+	ReadingTime   int        `db:"reading_time_minutes"`
+	CategoryID    *string    `db:"category_id"`
+	CoverMediaID  *string    `db:"cover_media_id"`
+	Status        string     `db:"status"`
+	PublishedAt   *time.Time `db:"published_at"`
+	Reactions     []byte     `db:"reaction_counts"`
+	ReactedAt     *time.Time `db:"reacted_at"`
+	Version       int        `db:"version"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+	DeletedAt     *time.Time `db:"deleted_at"`
+	Authors       []byte     `db:"authors"`
+	Slug          *string    `db:"slug"`
+	Tags          []string   `db:"tags"`
+	Locale        string     `db:"locale"`
+	Locales       []string   `db:"locales"`
+	Visibility    string     `db:"visibility"`
 }
 
 type Repository interface {
 	Insert(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error)
 	FindByID(ctx context.Context, id string) (*postdomain.Post, error)
-	FindByAuthorID(ctx context.Context, authorID string) ([]*postdomain.Post, error)
-	List(ctx context.Context) ([]*postdomain.Post, error)
-	Update(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error)
-	Delete(ctx context.Context, id string) error
+	FindByAuthorID(ctx context.Context, authorID string, publishedOnly bool, locale string) ([]*postdomain.Summary, error)
+	List(ctx context.Context, filter postdomain.ListFilter) ([]*postdomain.Summary, error)
+	FindByIDs(ctx context.Context, ids []string, viewerID string) ([]*postdomain.Summary, error)
+	// Update edits the post. withdrawReview takes a submitted, approved,
+	// scheduled or published post back to draft in the same statement.
+	Update(ctx context.Context, input *postdomain.Post, editorID, note string, withdrawReview bool) (*postdomain.Post, error)
+	PublishScheduled(ctx context.Context) ([]string, error)
+	ListUnrendered(ctx context.Context, limit int) ([]*postdomain.Post, error)
+	UpdateRendered(ctx context.Context, input *postdomain.Post) error
+	Delete(ctx context.Context, id string, version int) error
+	// FindMediaUploaderID returns who uploaded the media a post would use as
+	// its cover.
+	FindMediaUploaderID(ctx context.Context, mediaID string) (string, error)
+
+	// Authors
+	SetAuthors(ctx context.Context, id string, authors []postdomain.Byline, version int) (*postdomain.Post, error)
+
+	// Import and export
+	FindIDBySlug(ctx context.Context, slug string) (string, error)
+	ListAll(ctx context.Context) ([]*postdomain.Post, error)
+
+	// Feeds
+	ListPublished(ctx context.Context, filter postdomain.FeedFilter, limit int) ([]*postdomain.Post, error)
+	FindTag(ctx context.Context, name string) (string, error)
+
+	// Pins
+	UpsertPin(ctx context.Context, input *postdomain.Pin) (*postdomain.Pin, error)
+	DeletePin(ctx context.Context, postID, categoryID string) error
+	ListPins(ctx context.Context, categoryID string) ([]*postdomain.Pin, error)
+
+	// Translations. withdrawReview takes the post back to draft as Update
+	// does.
+	UpsertTranslation(ctx context.Context, input *postdomain.Translation, version int, withdrawReview bool) (*postdomain.Post, error)
+	DeleteTranslation(ctx context.Context, postID, locale string, version int, withdrawReview bool) (*postdomain.Post, error)
+	FindTranslation(ctx context.Context, postID, locale string) (*postdomain.Translation, error)
+	// FindBySlug returns the post outside the trash whose own slug, or the
+	// slug of one of its translations, is slug, and the locale of that slug.
+	// Translations in different locales may share a slug; the first of
+	// locales wins.
+	FindBySlug(ctx context.Context, slug string, locales []string) (id, locale string, err error)
+	ListTranslations(ctx context.Context, postID string) ([]*postdomain.Translation, error)
+	// FindTranslationTitles returns the title and excerpt of the posts in
+	// ids in each of locales, keyed by post then locale.
+	FindTranslationTitles(ctx context.Context, ids, locales []string) (map[string]map[string]*postdomain.Translation, error)
+
+	// Reviews and the lifecycle. Transition is the only way the status of a
+	// single post changes outside of an edit.
+	Transition(ctx context.Context, review *postdomain.Review, from []string, to string, publishedAt *time.Time, version int) (*postdomain.Post, error)
+	ListReviews(ctx context.Context, postID string) ([]*postdomain.Review, error)
+	// ListReviewerIDs returns the editors and admins.
+	ListReviewerIDs(ctx context.Context) ([]string, error)
+
+	// Locks
+	AcquireLock(ctx context.Context, postID, userID string, ttl time.Duration) (*postdomain.Lock, error)
+	FindLock(ctx context.Context, postID string) (*postdomain.Lock, error)
+	DeleteLock(ctx context.Context, postID string) error
+
+	// Autosaves
+	// SaveAutosave replaces the autosave of a user for a post. An existing
+	// autosave keeps its base version unless rebase is set.
+	SaveAutosave(ctx context.Context, input *postdomain.Autosave, rebase bool) (*postdomain.Autosave, error)
+	FindAutosave(ctx context.Context, postID, userID string) (*postdomain.Autosave, error)
+	DeleteAutosave(ctx context.Context, postID, userID string) error
+	PurgeAutosaves(ctx context.Context, savedBefore time.Time) (int64, error)
+
+	// Related
+	InvalidateRelated(ctx context.Context) error
+	ListStaleRelated(ctx context.Context, limit int) ([]string, error)
+	ComputeRelated(ctx context.Context, id string, limit int) error
+	FindRelated(ctx context.Context, id string, limit int) ([]string, error)
+
+	// Trash
+	FindDeletedByID(ctx context.Context, id string) (*postdomain.Post, error)
+	ListDeleted(ctx context.Context, authorID string) ([]*postdomain.Post, error)
+	Restore(ctx context.Context, id string) (*postdomain.Post, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+
+	// Revisions
+	ListRevisions(ctx context.Context, postID string) ([]*postdomain.Revision, error)
+	FindRevision(ctx context.Context, postID string, revision int) (*postdomain.Revision, error)
 }
 
 type repository struct {
@@ -42,42 +181,73 @@ func (r *repository) Insert(ctx context.Context, input *postdomain.Post) (*postd
 	m := r.inputToModel(input)
 	// Fixed CategoryID (UUID Type) is empty
 	categoryID := r.validateCategoryID(m.CategoryID)
+	coverMediaID := r.validateCategoryID(m.CoverMediaID)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
 	query := `
-		INSERT INTO posts (author_id, title, content, category_id)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at, updated_at
+		INSERT INTO posts (author_id, title, content, content_format, content_html, content_toc,
+			excerpt, word_count, reading_time_minutes, category_id, cover_media_id, status, published_at, slug, locale, visibility)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id, version, created_at, updated_at
 	`
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		m.AuthorID,
 		m.Title,
 		m.Content,
+		m.ContentFormat,
+		m.ContentHTML,
+		m.ContentTOC,
+		m.Excerpt,
+		m.WordCount,
+		m.ReadingTime,
 		categoryID,
-	).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+		coverMediaID,
+		m.Status,
+		m.PublishedAt,
+		m.Slug,
+		m.Locale,
+		m.Visibility,
+	).Scan(&m.ID, &m.Version, &m.CreatedAt, &m.UpdatedAt)
 
 	if err != nil {
+		return nil, r.insertError(err)
+	}
+	if err := r.insertTags(ctx, tx, m.ID, m.Tags); err != nil {
 		return nil, err
 	}
-	return r.modelToDomain(m), nil
+	tagsQuery := `SELECT ` + tagsColumn + ` FROM posts WHERE id = $1`
+	if err := tx.QueryRowContext(ctx, tagsQuery, m.ID).Scan(pq.Array(&m.Tags)); err != nil {
+		return nil, err
+	}
+
+	// The owner is always the first byline.
+	bylineQuery := `INSERT INTO post_authors (post_id, user_id, role, position) VALUES ($1, $2, 'author', 1)`
+	if _, err := tx.ExecContext(ctx, bylineQuery, m.ID, m.AuthorID); err != nil {
+		return nil, err
+	}
+	authorsQuery := `SELECT ` + authorsColumn + ` FROM posts WHERE id = $1`
+	if err := tx.QueryRowContext(ctx, authorsQuery, m.ID).Scan(&m.Authors); err != nil {
+		return nil, err
+	}
+
+	post := r.modelToDomain(m)
+	if err := r.insertRevision(ctx, tx, post, post.AuthorID, "initial version"); err != nil {
+		return nil, err
+	}
+	return post, tx.Commit()
 }
 
 func (r *repository) FindByID(ctx context.Context, id string) (*postdomain.Post, error) {
-	post := new(postdomain.Post)
-	query := `
-		SELECT id, author_id, title, content, category_id, created_at, updated_at
-		FROM posts WHERE id = $1 LIMIT 1
-	`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&post.ID,
-		&post.AuthorID,
-		&post.Title,
-		&post.Content,
-		&post.CategoryID,
-		&post.CreatedAt,
-		&post.UpdatedAt,
-	)
+	query := fmt.Sprintf(`SELECT %s FROM posts WHERE id = $1 AND deleted_at IS NULL LIMIT 1`, postColumns)
+
+	post, err := r.scanPost(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errs.ErrPostNotFound
@@ -87,69 +257,52 @@ func (r *repository) FindByID(ctx context.Context, id string) (*postdomain.Post,
 	return post, nil
 }
 
-func (r *repository) FindByAuthorID(ctx context.Context, authorID string) ([]*postdomain.Post, error) {
-	query := `
-		SELECT id, author_id, title, content, category_id, created_at, updated_at
-		FROM posts WHERE author_id = $1
-	`
-	rows, err := r.db.QueryContext(ctx, query, authorID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+func (r *repository) FindByAuthorID(ctx context.Context, authorID string, publishedOnly bool, locale string) ([]*postdomain.Summary, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM posts
+		WHERE %s AND deleted_at IS NULL
+			AND ($2 = FALSE OR %s) AND %s
+		ORDER BY COALESCE(published_at, created_at) DESC
+	`, summaryColumns, fmt.Sprintf(isAuthorCond, 1), listedCond, fmt.Sprintf(localeCond, 3))
 
-	var posts []*postdomain.Post
-	for rows.Next() {
-		p := new(postdomain.Post)
-		err = rows.Scan(
-			&p.ID,
-			&p.AuthorID,
-			&p.Title,
-			&p.Content,
-			&p.CategoryID,
-			&p.CreatedAt,
-			&p.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		posts = append(posts, p)
-	}
-	return posts, rows.Err()
+	return r.querySummaries(ctx, query, authorID, publishedOnly, locale)
 }
 
-func (r *repository) List(ctx context.Context) ([]*postdomain.Post, error) {
-	query := `
-		SELECT id, author_id, title, content, category_id, created_at, updated_at
-		FROM posts
-	`
-	rows, err := r.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// List returns listed published posts, plus every post filter.ViewerID owns
+// or co-authors when it is not empty.
+func (r *repository) List(ctx context.Context, filter postdomain.ListFilter) ([]*postdomain.Summary, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM posts
+		WHERE deleted_at IS NULL
+			AND (%s OR ($1 <> '' AND %s)) AND %s
+		ORDER BY COALESCE(published_at, created_at) DESC
+	`, summaryColumns, listedCond, fmt.Sprintf(isAuthorCond, 1), fmt.Sprintf(localeCond, 2))
 
-	var posts []*postdomain.Post
-	for rows.Next() {
-		p := new(postdomain.Post)
-		err = rows.Scan(
-			&p.ID,
-			&p.AuthorID,
-			&p.Title,
-			&p.Content,
-			&p.CategoryID,
-			&p.CreatedAt,
-			&p.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		posts = append(posts, p)
+	return r.querySummaries(ctx, query, filter.ViewerID, filter.Locale)
+}
+
+// FindByIDs returns the posts in the order of ids, leaving out the ones
+// viewerID can't read. Unlike List, it keeps unlisted posts, since the ids
+// were saved by someone who had the link.
+func (r *repository) FindByIDs(ctx context.Context, ids []string, viewerID string) ([]*postdomain.Summary, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
-	return posts, rows.Err()
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM posts
+		WHERE id = ANY($1::UUID[]) AND deleted_at IS NULL
+			AND ((status = 'published' AND visibility <> 'private') OR ($2 <> '' AND %s))
+		ORDER BY array_position($1::UUID[], id)
+	`, summaryColumns, fmt.Sprintf(isAuthorCond, 2))
+
+	return r.querySummaries(ctx, query, pq.Array(ids), viewerID)
 }
 
-func (r *repository) Update(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error) {
+// Update applies the non-empty fields of input and records the resulting
+// title and content as a new revision. A non-zero input.Version makes it a
+// compare-and-swap that fails with errs.ErrVersionConflict when stale.
+func (r *repository) Update(ctx context.Context, input *postdomain.Post, editorID, note string, withdrawReview bool) (*postdomain.Post, error) {
 	var (
 		sb   strings.Builder
 		args []any
@@ -170,84 +323,377 @@ func (r *repository) Update(ctx context.Context, input *postdomain.Post) (*postd
 		idx++
 	}
 
+	// The rendered HTML and what is computed from it always travel with
+	// the format.
+	if input.ContentFormat != "" {
+		m := r.inputToModel(input)
+		sb.WriteString(fmt.Sprintf(`content_format = $%d, content_html = $%d, content_toc = $%d,
+			excerpt = $%d, word_count = $%d, reading_time_minutes = $%d,`,
+			idx, idx+1, idx+2, idx+3, idx+4, idx+5))
+		args = append(args, m.ContentFormat, m.ContentHTML, m.ContentTOC, m.Excerpt, m.WordCount, m.ReadingTime)
+		idx += 6
+	}
+
 	if input.CategoryID != nil {
 		sb.WriteString(fmt.Sprintf("category_id = $%d,", idx))
 		args = append(args, input.CategoryID)
 		idx++
 	}
 
+	// An empty slug removes it.
+	if input.Slug != nil {
+		sb.WriteString(fmt.Sprintf("slug = NULLIF($%d, ''),", idx))
+		args = append(args, *input.Slug)
+		idx++
+	}
+
+	if input.Visibility != "" {
+		sb.WriteString(fmt.Sprintf("visibility = $%d,", idx))
+		args = append(args, input.Visibility)
+		idx++
+	}
+
+	// An empty id removes the cover image.
+	if input.CoverMediaID != nil {
+		sb.WriteString(fmt.Sprintf("cover_media_id = $%d,", idx))
+		args = append(args, r.validateCategoryID(input.CoverMediaID))
+		idx++
+	}
+
+	// Checked against the row being written, so a publish can't slip in
+	// between reading the status and the edit.
+	if withdrawReview {
+		sb.WriteString(withdrawReviewSet)
+	}
+
 	final := fmt.Sprintf(`
-		 updated_at = NOW() 
-		 WHERE id = $%d
-		 RETURNING id, author_id, title, content, category_id, created_at, updated_at
-	`, idx)
+		 version = version + 1, updated_at = NOW() 
+		 WHERE id = $%d AND deleted_at IS NULL AND ($%d = 0 OR version = $%d)
+		 RETURNING %s
+	`, idx, idx+1, idx+1, postColumns)
 
 	sb.WriteString(final)
-	args = append(args, input.ID)
+	args = append(args, input.ID, input.Version)
 
 	query := sb.String()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if input.CategoryID != nil {
+		if err := r.keepSitemapPages(ctx, tx, input.ID, scopeCategory); err != nil {
+			return nil, err
+		}
+	}
+
+	post, err := r.scanPost(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, r.notFoundOrConflict(ctx, input.ID)
+		}
+		return nil, r.insertError(err)
+	}
+
+	if err := r.insertRevision(ctx, tx, post, editorID, note); err != nil {
+		return nil, err
+	}
+	return post, tx.Commit()
+}
+
+// PublishScheduled flips every scheduled post whose time has come to
+// published and returns their IDs. The single UPDATE takes a row lock, so
+// when several instances run it at once each post is published exactly once.
+func (r *repository) PublishScheduled(ctx context.Context) ([]string, error) {
+	query := `
+		UPDATE posts SET status = 'published', version = version + 1, updated_at = NOW()
+		WHERE status = 'scheduled' AND published_at <= NOW() AND deleted_at IS NULL
+		RETURNING id
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListUnrendered returns posts whose HTML cache is empty, e.g. rows that
+// existed before rendering was introduced.
+func (r *repository) ListUnrendered(ctx context.Context, limit int) ([]*postdomain.Post, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM posts
+		WHERE content_html IS NULL AND deleted_at IS NULL
+		ORDER BY created_at
+		LIMIT $1
+	`, postColumns)
+
+	return r.queryPosts(ctx, query, limit)
+}
+
+// UpdateRendered refreshes the HTML cache and the metadata computed from it.
+// It is not an edit, so it neither bumps updated_at nor records a revision,
+// but the representation changes and so does the version.
+func (r *repository) UpdateRendered(ctx context.Context, input *postdomain.Post) error {
 	m := r.inputToModel(input)
+	query := `
+		UPDATE posts SET content_html = $1, content_toc = $2, excerpt = $3, word_count = $4,
+			reading_time_minutes = $5, version = version + 1
+		WHERE id = $6
+	`
+	_, err := r.db.ExecContext(ctx, query, m.ContentHTML, m.ContentTOC, m.Excerpt, m.WordCount, m.ReadingTime, m.ID)
+	return err
+}
+
+// Delete moves the post to the trash. Its comments stay untouched but are
+// hidden for as long as the post is.
+func (r *repository) Delete(ctx context.Context, id string, version int) error {
+	query := `
+		UPDATE posts SET deleted_at = NOW(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)
+	`
+	res, err := r.db.ExecContext(ctx, query, id, version)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return r.notFoundOrConflict(ctx, id)
+	}
+	return nil
+}
 
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+// notFoundOrConflict tells a missing post apart from a stale version after a
+// compare-and-swap matched no row.
+func (r *repository) notFoundOrConflict(ctx context.Context, id string) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM posts WHERE id = $1 AND deleted_at IS NULL)`
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return errs.ErrVersionConflict
+	}
+	return errs.ErrPostNotFound
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *repository) scanPost(row rowScanner) (*postdomain.Post, error) {
+	m := new(postModel)
+	err := row.Scan(
 		&m.ID,
 		&m.AuthorID,
 		&m.Title,
 		&m.Content,
+		&m.ContentFormat,
+		&m.ContentHTML,
+		&m.ContentTOC,
+		&m.Excerpt,
+		&m.WordCount,
+		&m.ReadingTime,
 		&m.CategoryID,
+		&m.CoverMediaID,
+		&m.Status,
+		&m.PublishedAt,
+		&m.Reactions,
+		&m.ReactedAt,
+		&m.Version,
 		&m.CreatedAt,
 		&m.UpdatedAt,
+		&m.DeletedAt,
+		&m.Authors,
+		&m.Slug,
+		pq.Array(&m.Tags),
+		&m.Locale,
+		pq.Array(&m.Locales),
+		&m.Visibility,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errs.ErrPostNotFound
-		}
 		return nil, err
 	}
-
 	return r.modelToDomain(m), nil
 }
 
-func (r *repository) Delete(ctx context.Context, id string) error {
-	res, err := r.db.ExecContext(ctx, "DELETE FROM posts WHERE id = $1", id)
+func (r *repository) queryPosts(ctx context.Context, query string, args ...any) ([]*postdomain.Post, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer rows.Close()
 
-	rows, err := res.RowsAffected()
+	var posts []*postdomain.Post
+	for rows.Next() {
+		p, err := r.scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+func (r *repository) scanSummary(row rowScanner) (*postdomain.Summary, error) {
+	var (
+		s         = new(postdomain.Summary)
+		reactions []byte
+		authors   []byte
+	)
+	err := row.Scan(
+		&s.ID,
+		&s.AuthorID,
+		&s.Title,
+		&s.Excerpt,
+		&s.WordCount,
+		&s.ReadingTime,
+		&s.CategoryID,
+		&s.CoverMediaID,
+		&s.Status,
+		&s.PublishedAt,
+		&reactions,
+		&s.ReactedAt,
+		&s.Version,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+		&authors,
+		&s.Locale,
+		&s.Visibility,
+	)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	s.Reactions = decodeReactions(reactions)
+	s.Authors = decodeAuthors(authors)
+	return s, nil
+}
 
-	if rows == 0 {
-		return errs.ErrPostNotFound
+func (r *repository) querySummaries(ctx context.Context, query string, args ...any) ([]*postdomain.Summary, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	defer rows.Close()
+
+	var posts []*postdomain.Summary
+	for rows.Next() {
+		s, err := r.scanSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, s)
+	}
+	return posts, rows.Err()
 }
 
 func (r *repository) inputToModel(input *postdomain.Post) *postModel {
+	toc := input.TOC
+	if toc == nil {
+		toc = []postdomain.Heading{}
+	}
+	tocJSON, _ := json.Marshal(toc)
+
 	return &postModel{
-		ID:         input.ID,
-		AuthorID:   input.AuthorID,
-		Title:      input.Title,
-		Content:    input.Content,
-		CategoryID: input.CategoryID,
-		CreatedAt:  input.CreatedAt,
-		UpdatedAt:  input.UpdatedAt,
+		ID:            input.ID,
+		AuthorID:      input.AuthorID,
+		Title:         input.Title,
+		Content:       input.Content,
+		ContentFormat: input.ContentFormat,
+		ContentHTML:   &input.ContentHTML,
+		ContentTOC:    tocJSON,
+		Excerpt:       input.Excerpt,
+		WordCount:     input.WordCount,
+		ReadingTime:   input.ReadingTime,
+		CategoryID:    input.CategoryID,
+		CoverMediaID:  input.CoverMediaID,
+		Status:        input.Status,
+		PublishedAt:   input.PublishedAt,
+		Slug:          input.Slug,
+		Tags:          input.Tags,
+		Locale:        input.Locale,
+		Visibility:    input.Visibility,
+		Version:       input.Version,
+		CreatedAt:     input.CreatedAt,
+		UpdatedAt:     input.UpdatedAt,
 	}
 }
 
 func (r *repository) modelToDomain(input *postModel) *postdomain.Post {
-	return &postdomain.Post{
-		ID:         input.ID,
-		AuthorID:   input.AuthorID,
-		Title:      input.Title,
-		Content:    input.Content,
-		CategoryID: input.CategoryID,
-		CreatedAt:  input.CreatedAt,
-		UpdatedAt:  input.UpdatedAt,
+	post := &postdomain.Post{
+		ID:            input.ID,
+		AuthorID:      input.AuthorID,
+		Title:         input.Title,
+		Content:       input.Content,
+		ContentFormat: input.ContentFormat,
+		Excerpt:       input.Excerpt,
+		WordCount:     input.WordCount,
+		ReadingTime:   input.ReadingTime,
+		CategoryID:    input.CategoryID,
+		CoverMediaID:  input.CoverMediaID,
+		Status:        input.Status,
+		PublishedAt:   input.PublishedAt,
+		Authors:       decodeAuthors(input.Authors),
+		Reactions:     decodeReactions(input.Reactions),
+		ReactedAt:     input.ReactedAt,
+		Version:       input.Version,
+		CreatedAt:     input.CreatedAt,
+		UpdatedAt:     input.UpdatedAt,
+		DeletedAt:     input.DeletedAt,
+		Slug:          input.Slug,
+		Tags:          input.Tags,
+		Locale:        input.Locale,
+		Locales:       input.Locales,
+		Visibility:    input.Visibility,
+	}
+	if post.Locales == nil {
+		post.Locales = []string{input.Locale}
+	}
+	if input.ContentHTML != nil {
+		post.ContentHTML = *input.ContentHTML
 	}
+	if len(input.ContentTOC) > 0 {
+		_ = json.Unmarshal(input.ContentTOC, &post.TOC)
+	}
+	return post
 }
 
+// decodeReactions reads the reaction_counts column. A new post has no row
+// data yet and gets an empty set.
+func decodeReactions(data []byte) postdomain.Reactions {
+	reactions := postdomain.Reactions{}
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &reactions)
+	}
+	return reactions
+}
+
+func decodeAuthors(data []byte) []postdomain.Byline {
+	authors := []postdomain.Byline{}
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &authors)
+	}
+	return authors
+}
+
+// validateCategoryID turns a missing or empty UUID into NULL. It is used for
+// every optional UUID column.
 func (r *repository) validateCategoryID(catID *string) (categoryID any) {
 	if catID != nil && *catID != "" {
 		categoryID = catID
@@ -256,3 +702,25 @@ func (r *repository) validateCategoryID(catID *string) (categoryID any) {
 	}
 	return
 }
+
+func (r *repository) FindMediaUploaderID(ctx context.Context, mediaID string) (string, error) {
+	var uploaderID string
+	err := r.db.QueryRowContext(ctx, `SELECT uploader_id FROM media WHERE id = $1`, mediaID).Scan(&uploaderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", errs.ErrMediaNotFound
+		}
+		return "", err
+	}
+	return uploaderID, nil
+}
+
+// coverMediaError reports a cover image that doesn't exist as such instead of
+// a foreign key violation.
+func (r *repository) coverMediaError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "posts_cover_media_id_fkey" {
+		return errs.ErrMediaNotFound
+	}
+	return err
+}
@@ -0,0 +1,170 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// relatedScore ranks candidate $3 against source post $1. A shared category
+// counts 1, every shared tag 0.5, and trigram similarity of the titles and
+// excerpts adds up to 2 and 1. The excerpt stands in for the content, whose
+// trigram sets are too large to compare for every pair of posts.
+const relatedScore = `
+	(CASE WHEN c.category_id IS NOT NULL AND c.category_id = s.category_id THEN 1 ELSE 0 END)
+	+ 0.5 * (
+		SELECT COUNT(*) FROM post_tags a JOIN post_tags b ON b.tag_id = a.tag_id
+		WHERE a.post_id = s.id AND b.post_id = c.id
+	)
+	+ 2 * similarity(c.title, s.title)
+	+ similarity(c.excerpt, s.excerpt)
+`
+
+// minRelatedScore keeps out posts that only share the odd trigram.
+const minRelatedScore = 0.2
+
+// InvalidateRelated flags the lists that point at posts no longer listed,
+// and drops the lists of those posts so they are recomputed if they return.
+func (r *repository) InvalidateRelated(ctx context.Context) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := []string{
+		`UPDATE posts SET related_dirty = TRUE
+		WHERE NOT related_dirty AND id IN (
+			SELECT rp.post_id FROM related_posts rp JOIN posts p ON p.id = rp.related_id
+			WHERE p.status <> 'published' OR p.deleted_at IS NOT NULL
+				OR p.visibility NOT IN ('public', 'members')
+		)`,
+		`DELETE FROM related_posts rp USING posts p
+		WHERE p.id = rp.post_id AND (p.status <> 'published' OR p.deleted_at IS NOT NULL)`,
+		`UPDATE posts SET related_at = NULL, related_dirty = FALSE
+		WHERE related_at IS NOT NULL AND (status <> 'published' OR deleted_at IS NOT NULL)`,
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListStaleRelated returns published posts edited since their related posts
+// were computed, or flagged because a neighbour changed.
+func (r *repository) ListStaleRelated(ctx context.Context, limit int) ([]string, error) {
+	query := `
+		SELECT id FROM posts
+		WHERE status = 'published' AND deleted_at IS NULL
+			AND (related_at IS NULL OR updated_at > related_at OR related_dirty)
+		ORDER BY updated_at DESC
+		LIMIT $1
+	`
+	return r.queryIDs(ctx, query, limit)
+}
+
+// ComputeRelated replaces the stored related posts of id with the limit best
+// scoring listed posts. When the post itself changed since the last run,
+// the posts it was and now is related to are flagged too, as their own lists
+// may gain or lose it. Another instance already computing id makes it a
+// no-op.
+func (r *repository) ComputeRelated(ctx context.Context, id string, limit int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('related_posts:' || $1))`, id).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return nil
+	}
+
+	var (
+		updatedAt time.Time
+		changed   bool
+	)
+	query := `SELECT updated_at, related_at IS NULL OR updated_at > related_at FROM posts WHERE id = $1`
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&updatedAt, &changed); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	flagNeighbours := `
+		UPDATE posts SET related_dirty = TRUE
+		WHERE id IN (SELECT post_id FROM related_posts WHERE related_id = $1)
+			OR id IN (SELECT related_id FROM related_posts WHERE post_id = $1)
+	`
+	if changed {
+		if _, err := tx.ExecContext(ctx, flagNeighbours, id); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM related_posts WHERE post_id = $1`, id); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO related_posts (post_id, related_id, score, rank)
+		SELECT $1, id, score, ROW_NUMBER() OVER (ORDER BY score DESC, published_at DESC)
+		FROM (
+			SELECT c.id, c.published_at, ` + relatedScore + ` AS score
+			FROM posts c, posts s
+			WHERE s.id = $1 AND c.id <> s.id
+				AND c.status = 'published' AND c.deleted_at IS NULL
+				AND c.visibility IN ('public', 'members')
+		) candidates
+		WHERE score >= $2
+		ORDER BY score DESC, published_at DESC
+		LIMIT $3
+	`
+	if _, err := tx.ExecContext(ctx, query, id, minRelatedScore, limit); err != nil {
+		return err
+	}
+
+	if changed {
+		if _, err := tx.ExecContext(ctx, flagNeighbours, id); err != nil {
+			return err
+		}
+	}
+
+	// An edit committed meanwhile has a later updated_at and is picked up by
+	// the next run.
+	query = `UPDATE posts SET related_at = $2, related_dirty = FALSE WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, id, updatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindRelated returns the ids of the stored related posts of id, best first.
+func (r *repository) FindRelated(ctx context.Context, id string, limit int) ([]string, error) {
+	query := `SELECT related_id FROM related_posts WHERE post_id = $1 ORDER BY rank LIMIT $2`
+	return r.queryIDs(ctx, query, id, limit)
+}
+
+func (r *repository) queryIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
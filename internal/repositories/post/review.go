@@ -0,0 +1,95 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+// withdrawReviewSet is the SET clause of an edit no reviewer has seen: a post
+// that was submitted, approved, scheduled or published goes back to draft,
+// and off the schedule or the site, until it is reviewed again. Both CASEs
+// read the status from before the edit.
+const withdrawReviewSet = `
+	status = CASE WHEN status IN ('in_review', 'approved', 'scheduled', 'published') THEN 'draft' ELSE status END,
+	published_at = CASE WHEN status IN ('scheduled', 'published') THEN NULL ELSE published_at END,`
+
+// Transition moves the post to status to and records the step. The post must
+// be in one of the statuses in from, checked in the same UPDATE so that two
+// reviewers can't both act on it, and a non-zero version must be current.
+// Publishing and scheduling set published_at, going back to draft clears it,
+// and every other step keeps it.
+func (r *repository) Transition(ctx context.Context, review *postdomain.Review, from []string, to string, publishedAt *time.Time, version int) (*postdomain.Post, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`
+		UPDATE posts SET status = $2,
+			published_at = CASE
+				WHEN $2::TEXT IN ('published', 'scheduled') THEN $4
+				WHEN $2::TEXT = 'draft' THEN NULL
+				ELSE published_at
+			END,
+			version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND status::TEXT = ANY($3) AND ($5 = 0 OR version = $5)
+		RETURNING %s
+	`, postColumns)
+
+	row := tx.QueryRowContext(ctx, query, review.PostID, to, pq.Array(from), publishedAt, version)
+	post, err := r.scanPost(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			current, err := r.FindByID(ctx, review.PostID)
+			if err != nil {
+				return nil, err
+			}
+			if version != 0 && current.Version != version {
+				return nil, errs.ErrVersionConflict
+			}
+			return nil, errs.ErrPostInvalidTransition
+		}
+		return nil, err
+	}
+
+	query = `INSERT INTO post_reviews (post_id, actor_id, action, comment) VALUES ($1, $2, $3, $4)`
+	if _, err := tx.ExecContext(ctx, query, review.PostID, review.ActorID, review.Action, review.Comment); err != nil {
+		return nil, err
+	}
+	return post, tx.Commit()
+}
+
+func (r *repository) ListReviews(ctx context.Context, postID string) ([]*postdomain.Review, error) {
+	query := `
+		SELECT id, post_id, actor_id, action, comment, created_at FROM post_reviews
+		WHERE post_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*postdomain.Review{}
+	for rows.Next() {
+		rv := new(postdomain.Review)
+		if err := rows.Scan(&rv.ID, &rv.PostID, &rv.ActorID, &rv.Action, &rv.Comment, &rv.CreatedAt); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *repository) ListReviewerIDs(ctx context.Context) ([]string, error) {
+	return r.queryIDs(ctx, `SELECT id FROM users WHERE role IN ('editor', 'admin') ORDER BY created_at`)
+}
@@ -0,0 +1,86 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertRevision snapshots the post as its next revision. It must run in the
+// same transaction as the write that changed the post: the row lock taken by
+// that write serialises concurrent editors, so revision numbers never clash.
+func (r *repository) insertRevision(ctx context.Context, tx execer, post *postdomain.Post, editorID, note string) error {
+	query := `
+		INSERT INTO post_revisions (post_id, revision, title, content, content_format, editor_id, note)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6
+		FROM post_revisions WHERE post_id = $1
+	`
+	_, err := tx.ExecContext(ctx, query, post.ID, post.Title, post.Content, post.ContentFormat, editorID, note)
+	return err
+}
+
+func (r *repository) ListRevisions(ctx context.Context, postID string) ([]*postdomain.Revision, error) {
+	query := `
+		SELECT id, post_id, revision, title, content_format, editor_id, note, created_at
+		FROM post_revisions WHERE post_id = $1
+		ORDER BY revision DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*postdomain.Revision
+	for rows.Next() {
+		rev := new(postdomain.Revision)
+		err = rows.Scan(
+			&rev.ID,
+			&rev.PostID,
+			&rev.Revision,
+			&rev.Title,
+			&rev.Format,
+			&rev.EditorID,
+			&rev.Note,
+			&rev.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *repository) FindRevision(ctx context.Context, postID string, revision int) (*postdomain.Revision, error) {
+	rev := new(postdomain.Revision)
+	query := `
+		SELECT id, post_id, revision, title, content, content_format, editor_id, note, created_at
+		FROM post_revisions WHERE post_id = $1 AND revision = $2
+	`
+	err := r.db.QueryRowContext(ctx, query, postID, revision).Scan(
+		&rev.ID,
+		&rev.PostID,
+		&rev.Revision,
+		&rev.Title,
+		&rev.Content,
+		&rev.Format,
+		&rev.EditorID,
+		&rev.Note,
+		&rev.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return rev, nil
+}
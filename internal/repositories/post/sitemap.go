@@ -0,0 +1,42 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Scopes of the sitemap pages a post is listed on besides its own.
+const (
+	scopeCategory = "category"
+	scopeAuthor   = "author"
+)
+
+// previousScopes select the pages of a scope the post in $1 is listed on,
+// as rows of sitemap_previous_scopes. Only posts in the sitemap are listed
+// anywhere.
+var previousScopes = map[string]string{
+	scopeCategory: `
+		SELECT id, 'category', category_id::TEXT FROM posts
+		WHERE id = $1 AND sitemap_at IS NOT NULL AND category_id IS NOT NULL
+	`,
+	scopeAuthor: `
+		SELECT p.id, 'author', p.author_id::TEXT FROM posts p
+		WHERE p.id = $1 AND p.sitemap_at IS NOT NULL
+		UNION SELECT pa.post_id, 'author', pa.user_id::TEXT FROM post_authors pa
+		JOIN posts p ON p.id = pa.post_id
+		WHERE pa.post_id = $1 AND p.sitemap_at IS NOT NULL
+	`,
+}
+
+// keepSitemapPages records the pages of scope the post is listed on before a
+// change may move it off them, so that the next sitemap sync refreshes them
+// along with the new ones. The post is locked first: a sync skips locked
+// posts, and can't forget the record before it sees the change.
+func (r *repository) keepSitemapPages(ctx context.Context, tx *sql.Tx, postID, scope string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM posts WHERE id = $1 FOR UPDATE`, postID); err != nil {
+		return err
+	}
+	query := `INSERT INTO sitemap_previous_scopes (post_id, scope, key) ` + previousScopes[scope] + ` ON CONFLICT DO NOTHING`
+	_, err := tx.ExecContext(ctx, query, postID)
+	return err
+}
@@ -0,0 +1,72 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+// FindIDBySlug looks through every post, trashed ones included, since the
+// slug stays taken until the post is purged.
+func (r *repository) FindIDBySlug(ctx context.Context, slug string) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, `SELECT id FROM posts WHERE slug = $1`, slug).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errs.ErrPostNotFound
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+// ListAll returns every post outside the trash, oldest first.
+func (r *repository) ListAll(ctx context.Context) ([]*postdomain.Post, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM posts
+		WHERE deleted_at IS NULL
+		ORDER BY COALESCE(published_at, created_at), created_at
+	`, postColumns)
+
+	return r.queryPosts(ctx, query)
+}
+
+// insertTags attaches the named tags to a new post, creating the ones that
+// don't exist yet. Names match case-insensitively.
+func (r *repository) insertTags(ctx context.Context, tx execer, postID string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO tags (name)
+		SELECT DISTINCT ON (LOWER(n)) n FROM UNNEST($1::TEXT[]) AS n
+		WHERE NOT EXISTS (SELECT 1 FROM tags t WHERE LOWER(t.name) = LOWER(n))
+	`
+	if _, err := tx.ExecContext(ctx, query, pq.Array(names)); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO post_tags (post_id, tag_id)
+		SELECT $1, MIN(id) FROM tags
+		WHERE LOWER(name) IN (SELECT LOWER(n) FROM UNNEST($2::TEXT[]) AS n)
+		GROUP BY LOWER(name)
+	`
+	_, err := tx.ExecContext(ctx, query, postID, pq.Array(names))
+	return err
+}
+
+// insertError maps the constraint violations a new post can run into.
+func (r *repository) insertError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_posts_slug" {
+		return errs.ErrPostSlugTaken
+	}
+	return r.coverMediaError(err)
+}
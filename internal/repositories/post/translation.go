@@ -0,0 +1,251 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+const translationColumns = `post_id, locale, title, slug, content, content_format, content_html,
+	content_toc, excerpt, word_count, reading_time_minutes, created_at, updated_at`
+
+// UpsertTranslation creates or replaces the translation of a post into
+// input.Locale. Like changing the authors, it is a new version of the post;
+// a non-zero version makes it a compare-and-swap.
+func (r *repository) UpsertTranslation(ctx context.Context, input *postdomain.Translation, version int, withdrawReview bool) (*postdomain.Post, error) {
+	toc := input.TOC
+	if toc == nil {
+		toc = []postdomain.Heading{}
+	}
+	tocJSON, err := json.Marshal(toc)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.bumpVersion(ctx, tx, input.PostID, version, withdrawReview); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO post_translations (post_id, locale, title, slug, content, content_format, content_html,
+			content_toc, excerpt, word_count, reading_time_minutes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (post_id, locale) DO UPDATE
+		SET title = EXCLUDED.title, slug = EXCLUDED.slug, content = EXCLUDED.content,
+			content_format = EXCLUDED.content_format, content_html = EXCLUDED.content_html,
+			content_toc = EXCLUDED.content_toc, excerpt = EXCLUDED.excerpt,
+			word_count = EXCLUDED.word_count, reading_time_minutes = EXCLUDED.reading_time_minutes,
+			updated_at = NOW()
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		input.PostID,
+		input.Locale,
+		input.Title,
+		input.Slug,
+		input.Content,
+		input.ContentFormat,
+		input.ContentHTML,
+		tocJSON,
+		input.Excerpt,
+		input.WordCount,
+		input.ReadingTime,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_post_translations_slug" {
+			return nil, errs.ErrPostSlugTaken
+		}
+		return nil, err
+	}
+
+	query = fmt.Sprintf(`SELECT %s FROM posts WHERE id = $1`, postColumns)
+	post, err := r.scanPost(tx.QueryRowContext(ctx, query, input.PostID))
+	if err != nil {
+		return nil, err
+	}
+	return post, tx.Commit()
+}
+
+// DeleteTranslation removes the translation of a post into locale, making a
+// new version of the post.
+func (r *repository) DeleteTranslation(ctx context.Context, postID, locale string, version int, withdrawReview bool) (*postdomain.Post, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.bumpVersion(ctx, tx, postID, version, withdrawReview); err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM post_translations WHERE post_id = $1 AND locale = $2`, postID, locale)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, errs.ErrTranslationNotFound
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM posts WHERE id = $1`, postColumns)
+	post, err := r.scanPost(tx.QueryRowContext(ctx, query, postID))
+	if err != nil {
+		return nil, err
+	}
+	return post, tx.Commit()
+}
+
+func (r *repository) FindTranslation(ctx context.Context, postID, locale string) (*postdomain.Translation, error) {
+	query := `SELECT ` + translationColumns + ` FROM post_translations WHERE post_id = $1 AND locale = $2`
+
+	tr, err := r.scanTranslation(r.db.QueryRowContext(ctx, query, postID, locale))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrTranslationNotFound
+		}
+		return nil, err
+	}
+	return tr, nil
+}
+
+func (r *repository) FindBySlug(ctx context.Context, slug string, locales []string) (string, string, error) {
+	query := `
+		SELECT id, locale FROM (
+			SELECT id, locale, 0 AS own FROM posts WHERE slug = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT tr.post_id, tr.locale, 1 FROM post_translations tr
+			JOIN posts p ON p.id = tr.post_id
+			WHERE tr.slug = $1 AND p.deleted_at IS NULL
+		) AS s
+		ORDER BY own, array_position($2::TEXT[], locale::TEXT) NULLS LAST, locale
+		LIMIT 1
+	`
+	var id, locale string
+	err := r.db.QueryRowContext(ctx, query, slug, pq.Array(locales)).Scan(&id, &locale)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", errs.ErrPostNotFound
+		}
+		return "", "", err
+	}
+	return id, locale, nil
+}
+
+func (r *repository) ListTranslations(ctx context.Context, postID string) ([]*postdomain.Translation, error) {
+	query := `SELECT ` + translationColumns + ` FROM post_translations WHERE post_id = $1 ORDER BY locale`
+
+	rows, err := r.db.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []*postdomain.Translation{}
+	for rows.Next() {
+		tr, err := r.scanTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, tr)
+	}
+	return translations, rows.Err()
+}
+
+func (r *repository) FindTranslationTitles(ctx context.Context, ids, locales []string) (map[string]map[string]*postdomain.Translation, error) {
+	result := make(map[string]map[string]*postdomain.Translation)
+	if len(ids) == 0 || len(locales) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT post_id, locale, title, excerpt, word_count, reading_time_minutes FROM post_translations
+		WHERE post_id = ANY($1::UUID[]) AND locale = ANY($2)
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), pq.Array(locales))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tr := new(postdomain.Translation)
+		if err := rows.Scan(&tr.PostID, &tr.Locale, &tr.Title, &tr.Excerpt, &tr.WordCount, &tr.ReadingTime); err != nil {
+			return nil, err
+		}
+		if result[tr.PostID] == nil {
+			result[tr.PostID] = make(map[string]*postdomain.Translation)
+		}
+		result[tr.PostID][tr.Locale] = tr
+	}
+	return result, rows.Err()
+}
+
+// bumpVersion makes a new version of the post for a change stored outside
+// the posts row.
+func (r *repository) bumpVersion(ctx context.Context, tx *sql.Tx, id string, version int, withdrawReview bool) error {
+	set := ""
+	if withdrawReview {
+		set = withdrawReviewSet
+	}
+	query := fmt.Sprintf(`
+		UPDATE posts SET %s version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)
+		RETURNING id
+	`, set)
+
+	var postID string
+	err := tx.QueryRowContext(ctx, query, id, version).Scan(&postID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return r.notFoundOrConflict(ctx, id)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *repository) scanTranslation(row rowScanner) (*postdomain.Translation, error) {
+	var (
+		tr  = new(postdomain.Translation)
+		toc []byte
+	)
+	err := row.Scan(
+		&tr.PostID,
+		&tr.Locale,
+		&tr.Title,
+		&tr.Slug,
+		&tr.Content,
+		&tr.ContentFormat,
+		&tr.ContentHTML,
+		&toc,
+		&tr.Excerpt,
+		&tr.WordCount,
+		&tr.ReadingTime,
+		&tr.CreatedAt,
+		&tr.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tr.TOC = []postdomain.Heading{}
+	_ = json.Unmarshal(toc, &tr.TOC)
+	return tr, nil
+}
@@ -0,0 +1,63 @@
+package postrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+func (r *repository) FindDeletedByID(ctx context.Context, id string) (*postdomain.Post, error) {
+	query := fmt.Sprintf(`SELECT %s FROM posts WHERE id = $1 AND deleted_at IS NOT NULL LIMIT 1`, postColumns)
+
+	post, err := r.scanPost(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errs.ErrPostNotFound
+		}
+		return nil, err
+	}
+	return post, nil
+}
+
+// ListDeleted returns the trashed posts of authorID, or of everyone when
+// authorID is empty.
+func (r *repository) ListDeleted(ctx context.Context, authorID string) ([]*postdomain.Post, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM posts
+		WHERE deleted_at IS NOT NULL AND ($1 = '' OR author_id::TEXT = $1)
+		ORDER BY deleted_at DESC
+	`, postColumns)
+
+	return r.queryPosts(ctx, query, authorID)
+}
+
+func (r *repository) Restore(ctx context.Context, id string) (*postdomain.Post, error) {
+	query := fmt.Sprintf(`
+		UPDATE posts SET deleted_at = NULL, version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING %s
+	`, postColumns)
+
+	post, err := r.scanPost(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errs.ErrPostNotFound
+		}
+		return nil, err
+	}
+	return post, nil
+}
+
+// Purge hard-deletes posts that have been in the trash since before
+// deletedBefore. Comments, tags and revisions go with them via ON DELETE CASCADE.
+func (r *repository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM posts WHERE deleted_at < $1", deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,109 @@
+package previewrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	previewdomain "github.com/codepnw/blog-api/internal/domains/preview"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+const linkColumns = `id, post_id, created_by, note, expires_at, revoked_at, created_at`
+
+type Repository interface {
+	Insert(ctx context.Context, input *previewdomain.Link) (*previewdomain.Link, error)
+	FindByID(ctx context.Context, id string) (*previewdomain.Link, error)
+	// ListByPost returns the links of a post, newest first, including
+	// expired and revoked ones.
+	ListByPost(ctx context.Context, postID string) ([]*previewdomain.Link, error)
+	// Revoke disables a link of the post. Revoking it again keeps the first
+	// revocation time.
+	Revoke(ctx context.Context, postID, id string) (*previewdomain.Link, error)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewPreviewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Insert(ctx context.Context, input *previewdomain.Link) (*previewdomain.Link, error) {
+	query := `
+		INSERT INTO post_preview_links (post_id, created_by, note, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + linkColumns
+	return r.scanLink(r.db.QueryRowContext(ctx, query, input.PostID, input.CreatedBy, input.Note, input.ExpiresAt))
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*previewdomain.Link, error) {
+	query := `SELECT ` + linkColumns + ` FROM post_preview_links WHERE id = $1`
+
+	link, err := r.scanLink(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrPreviewLinkNotFound
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+func (r *repository) ListByPost(ctx context.Context, postID string) ([]*previewdomain.Link, error) {
+	query := `SELECT ` + linkColumns + ` FROM post_preview_links WHERE post_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []*previewdomain.Link{}
+	for rows.Next() {
+		link, err := r.scanLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+func (r *repository) Revoke(ctx context.Context, postID, id string) (*previewdomain.Link, error) {
+	query := `
+		UPDATE post_preview_links SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1 AND post_id = $2
+		RETURNING ` + linkColumns
+
+	link, err := r.scanLink(r.db.QueryRowContext(ctx, query, id, postID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrPreviewLinkNotFound
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+func (r *repository) scanLink(row rowScanner) (*previewdomain.Link, error) {
+	link := new(previewdomain.Link)
+	err := row.Scan(
+		&link.ID,
+		&link.PostID,
+		&link.CreatedBy,
+		&link.Note,
+		&link.ExpiresAt,
+		&link.RevokedAt,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
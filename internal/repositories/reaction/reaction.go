@@ -0,0 +1,159 @@
+package reactionrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	reactiondomain "github.com/codepnw/blog-api/internal/domains/reaction"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	// Add and Remove report whether anything changed. The counters on posts
+	// are updated in the same transaction.
+	Add(ctx context.Context, postID, userID, reaction string) (bool, error)
+	Remove(ctx context.Context, postID, userID, reaction string) (bool, error)
+	Find(ctx context.Context, postID, userID string) (*reactiondomain.Reactions, error)
+	// FindByUser returns the reactions of a user, keyed by post id, for the
+	// given posts.
+	FindByUser(ctx context.Context, userID string, postIDs []string) (map[string][]string, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewReactionRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Add(ctx context.Context, postID, userID, reaction string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO post_reactions (post_id, user_id, reaction)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, postID, userID, reaction)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return false, errs.ErrPostNotFound
+		}
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	// The row lock taken by UPDATE serializes concurrent reactions, and the
+	// expression is evaluated against the latest counts.
+	_, err = tx.ExecContext(ctx, `
+		UPDATE posts SET
+			reaction_counts = jsonb_set(reaction_counts, ARRAY[$2::TEXT],
+				to_jsonb(COALESCE((reaction_counts->>$2)::INT, 0) + 1)),
+			reacted_at = NOW()
+		WHERE id = $1
+	`, postID, reaction)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *repository) Remove(ctx context.Context, postID, userID, reaction string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		DELETE FROM post_reactions
+		WHERE post_id = $1 AND user_id = $2 AND reaction = $3
+	`, postID, userID, reaction)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	// Types nobody uses anymore are dropped instead of kept at zero.
+	_, err = tx.ExecContext(ctx, `
+		UPDATE posts SET
+			reaction_counts = CASE
+				WHEN COALESCE((reaction_counts->>$2)::INT, 0) <= 1 THEN reaction_counts - $2::TEXT
+				ELSE jsonb_set(reaction_counts, ARRAY[$2::TEXT], to_jsonb((reaction_counts->>$2)::INT - 1))
+			END,
+			reacted_at = NOW()
+		WHERE id = $1
+	`, postID, reaction)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *repository) Find(ctx context.Context, postID, userID string) (*reactiondomain.Reactions, error) {
+	var counts []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT reaction_counts FROM posts WHERE id = $1 AND deleted_at IS NULL
+	`, postID).Scan(&counts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrPostNotFound
+		}
+		return nil, err
+	}
+
+	result := &reactiondomain.Reactions{PostID: postID, Counts: map[string]int{}, Mine: []string{}}
+	if err := json.Unmarshal(counts, &result.Counts); err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return result, nil
+	}
+
+	mine, err := r.FindByUser(ctx, userID, []string{postID})
+	if err != nil {
+		return nil, err
+	}
+	if mine[postID] != nil {
+		result.Mine = mine[postID]
+	}
+	return result, nil
+}
+
+func (r *repository) FindByUser(ctx context.Context, userID string, postIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT post_id, reaction FROM post_reactions
+		WHERE user_id = $1 AND post_id = ANY($2::UUID[])
+		ORDER BY post_id, reaction
+	`, userID, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, reaction string
+		if err := rows.Scan(&postID, &reaction); err != nil {
+			return nil, err
+		}
+		result[postID] = append(result[postID], reaction)
+	}
+	return result, rows.Err()
+}
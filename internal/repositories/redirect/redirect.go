@@ -0,0 +1,58 @@
+package redirectrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	// Insert points the paths at the post and returns how many were new. A
+	// path that already leads somewhere keeps its target.
+	Insert(ctx context.Context, postID string, paths []string) (int64, error)
+	// FindPostID returns the published post the path leads to.
+	FindPostID(ctx context.Context, path string) (string, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRedirectRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Insert(ctx context.Context, postID string, paths []string) (int64, error) {
+	if len(paths) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		INSERT INTO redirects (path, post_id)
+		SELECT p, $1 FROM UNNEST($2::TEXT[]) AS p
+		ON CONFLICT (path) DO NOTHING
+	`
+	res, err := r.db.ExecContext(ctx, query, postID, pq.Array(paths))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *repository) FindPostID(ctx context.Context, path string) (string, error) {
+	var postID string
+	query := `
+		SELECT r.post_id FROM redirects r JOIN posts p ON p.id = r.post_id
+		WHERE r.path = $1 AND p.status = 'published' AND p.deleted_at IS NULL
+	`
+	if err := r.db.QueryRowContext(ctx, query, path).Scan(&postID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", errs.ErrRedirectNotFound
+		}
+		return "", err
+	}
+	return postID, nil
+}
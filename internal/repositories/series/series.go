@@ -0,0 +1,235 @@
+package seriesrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	seriesdomain "github.com/codepnw/blog-api/internal/domains/series"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+const seriesColumns = `id, author_id, title, description, created_at, updated_at`
+
+type Repository interface {
+	Insert(ctx context.Context, input *seriesdomain.Series) (*seriesdomain.Series, error)
+	FindByID(ctx context.Context, id string) (*seriesdomain.Series, error)
+	// FindByPost returns the series the post belongs to.
+	FindByPost(ctx context.Context, postID string) (*seriesdomain.Series, error)
+	Update(ctx context.Context, input *seriesdomain.Series) (*seriesdomain.Series, error)
+	Delete(ctx context.Context, id string) error
+
+	// Parts
+	ListParts(ctx context.Context, seriesID string) ([]*seriesdomain.Part, error)
+	AddPart(ctx context.Context, seriesID, postID string) error
+	RemovePart(ctx context.Context, seriesID, postID string) error
+	// Reorder numbers the parts in the order of postIDs, which must hold
+	// exactly the parts ListParts returns.
+	Reorder(ctx context.Context, seriesID string, postIDs []string) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewSeriesRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Insert(ctx context.Context, input *seriesdomain.Series) (*seriesdomain.Series, error) {
+	query := `
+		INSERT INTO series (author_id, title, description)
+		VALUES ($1, $2, $3)
+		RETURNING ` + seriesColumns
+
+	return r.scanSeries(r.db.QueryRowContext(ctx, query, input.AuthorID, input.Title, input.Description))
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*seriesdomain.Series, error) {
+	query := `SELECT ` + seriesColumns + ` FROM series WHERE id = $1`
+	return r.findSeries(ctx, query, id)
+}
+
+func (r *repository) FindByPost(ctx context.Context, postID string) (*seriesdomain.Series, error) {
+	query := `
+		SELECT ` + seriesColumns + ` FROM series
+		WHERE id = (SELECT series_id FROM series_posts WHERE post_id = $1)
+	`
+	return r.findSeries(ctx, query, postID)
+}
+
+func (r *repository) findSeries(ctx context.Context, query, arg string) (*seriesdomain.Series, error) {
+	series, err := r.scanSeries(r.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrSeriesNotFound
+		}
+		return nil, err
+	}
+	return series, nil
+}
+
+func (r *repository) Update(ctx context.Context, input *seriesdomain.Series) (*seriesdomain.Series, error) {
+	query := `
+		UPDATE series SET title = $1, description = $2, updated_at = NOW()
+		WHERE id = $3
+		RETURNING ` + seriesColumns
+
+	series, err := r.scanSeries(r.db.QueryRowContext(ctx, query, input.Title, input.Description, input.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrSeriesNotFound
+		}
+		return nil, err
+	}
+	return series, nil
+}
+
+// Delete removes the series only; its posts are kept.
+func (r *repository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM series WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errs.ErrSeriesNotFound
+	}
+	return nil
+}
+
+// ListParts returns the parts in order, leaving out deleted posts.
+func (r *repository) ListParts(ctx context.Context, seriesID string) ([]*seriesdomain.Part, error) {
+	query := `
+		SELECT p.id, p.title, p.status, p.visibility FROM series_posts sp
+		JOIN posts p ON p.id = sp.post_id
+		WHERE sp.series_id = $1 AND p.deleted_at IS NULL
+		ORDER BY sp.position
+	`
+	rows, err := r.db.QueryContext(ctx, query, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var parts []*seriesdomain.Part
+	for rows.Next() {
+		part := new(seriesdomain.Part)
+		if err := rows.Scan(&part.PostID, &part.Title, &part.Status, &part.Visibility); err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, rows.Err()
+}
+
+// AddPart appends the post to the end of the series.
+func (r *repository) AddPart(ctx context.Context, seriesID, postID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the series so concurrent appends don't pick the same position.
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM series WHERE id = $1 FOR UPDATE`, seriesID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO series_posts (series_id, post_id, position)
+		SELECT $1, $2, COALESCE(MAX(position), 0) + 1 FROM series_posts WHERE series_id = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, seriesID, postID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "series_posts_pkey" {
+			return errs.ErrPostInSeries
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemovePart takes the post out of the series and closes the gap it leaves.
+func (r *repository) RemovePart(ctx context.Context, seriesID, postID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var position int
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM series_posts WHERE series_id = $1 AND post_id = $2
+		RETURNING position
+	`, seriesID, postID).Scan(&position)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrPostNotInSeries
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE series_posts SET position = position - 1
+		WHERE series_id = $1 AND position > $2
+	`, seriesID, position)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repository) Reorder(ctx context.Context, seriesID string, postIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM series WHERE id = $1 FOR UPDATE`, seriesID); err != nil {
+		return err
+	}
+
+	// Every part ListParts shows must be listed exactly once.
+	var parts, matched int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE sp.post_id = ANY($2::UUID[]))
+		FROM series_posts sp
+		JOIN posts p ON p.id = sp.post_id
+		WHERE sp.series_id = $1 AND p.deleted_at IS NULL
+	`, seriesID, pq.Array(postIDs)).Scan(&parts, &matched)
+	if err != nil {
+		return err
+	}
+	if parts != len(postIDs) || matched != len(postIDs) {
+		return errs.ErrInvalidSeriesOrder
+	}
+
+	// Parts of posts in the trash keep their relative order after the rest.
+	_, err = tx.ExecContext(ctx, `
+		UPDATE series_posts
+		SET position = COALESCE(array_position($2::UUID[], post_id), cardinality($2::UUID[]) + position)
+		WHERE series_id = $1
+	`, seriesID, pq.Array(postIDs))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *repository) scanSeries(row *sql.Row) (*seriesdomain.Series, error) {
+	series := new(seriesdomain.Series)
+	err := row.Scan(
+		&series.ID,
+		&series.AuthorID,
+		&series.Title,
+		&series.Description,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
@@ -0,0 +1,226 @@
+package sitemaprepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sitemapdomain "github.com/codepnw/blog-api/internal/domains/sitemap"
+	"github.com/lib/pq"
+)
+
+// publishedCond matches the posts search engines may list: published, and
+// neither unlisted nor private.
+const publishedCond = `p.status = 'published' AND p.deleted_at IS NULL
+	AND p.visibility IN ('public', 'members')`
+
+// refreshScope recomputes the entries of the category, author or tag pages
+// that the posts in $1 belong to, or belonged to before an edit moved them
+// (see sitemap_previous_scopes). A page's lastmod is the latest updated_at of
+// its published posts, and it leaves the sitemap when it has none left.
+//
+// %[1]s is the path prefix, %[2]s selects the affected pages as "key", and
+// %[3]s is the lastmod of page s.key.
+const refreshScope = `
+	WITH scope AS (%[2]s),
+	stats AS (
+		SELECT %[1]s || s.key AS path, (%[3]s) AS lastmod FROM scope s
+	),
+	removed AS (
+		DELETE FROM sitemap_urls WHERE path IN (SELECT path FROM stats WHERE lastmod IS NULL)
+	)
+	INSERT INTO sitemap_urls (path, lastmod)
+	SELECT path, lastmod FROM stats WHERE lastmod IS NOT NULL
+	ON CONFLICT (path) DO UPDATE SET lastmod = EXCLUDED.lastmod
+`
+
+var scopeQueries = []string{
+	// Categories
+	fmt.Sprintf(refreshScope, `'/categories/'`,
+		`SELECT category_id AS key FROM posts WHERE id = ANY($1::UUID[]) AND category_id IS NOT NULL
+		UNION SELECT key::UUID FROM sitemap_previous_scopes WHERE post_id = ANY($1::UUID[]) AND scope = 'category'`,
+		`SELECT MAX(p.updated_at) FROM posts p WHERE p.category_id = s.key AND `+publishedCond,
+	),
+	// Authors, including co-authors
+	fmt.Sprintf(refreshScope, `'/users/'`,
+		`SELECT author_id AS key FROM posts WHERE id = ANY($1::UUID[])
+		UNION SELECT user_id FROM post_authors WHERE post_id = ANY($1::UUID[])
+		UNION SELECT key::UUID FROM sitemap_previous_scopes WHERE post_id = ANY($1::UUID[]) AND scope = 'author'`,
+		`SELECT MAX(p.updated_at) FROM posts p
+		WHERE (p.author_id = s.key OR EXISTS (
+			SELECT 1 FROM post_authors pa WHERE pa.post_id = p.id AND pa.user_id = s.key
+		)) AND `+publishedCond,
+	),
+	// Tags, which are set when the post is created and don't move
+	fmt.Sprintf(refreshScope, `'/tags/'`,
+		`SELECT DISTINCT t.name AS key FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.post_id = ANY($1::UUID[])`,
+		`SELECT MAX(p.updated_at) FROM post_tags pt
+		JOIN tags t ON t.id = pt.tag_id JOIN posts p ON p.id = pt.post_id
+		WHERE t.name = s.key AND `+publishedCond,
+	),
+}
+
+type Repository interface {
+	// SyncPosts brings the sitemap up to date with at most limit posts that
+	// were published, edited, unpublished or trashed since the last sync,
+	// along with their category, author and tag pages. It returns how many
+	// posts it handled.
+	SyncPosts(ctx context.Context, limit int) (int, error)
+	// ListFiles splits the sitemap into files of size URLs.
+	ListFiles(ctx context.Context, size int) ([]*sitemapdomain.File, error)
+	ListURLs(ctx context.Context, page, size int) ([]*sitemapdomain.URL, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewSitemapRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) SyncPosts(ctx context.Context, limit int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED lets several instances sync different posts at once.
+	removed, err := queryIDs(ctx, tx, `
+		UPDATE posts SET sitemap_at = NULL
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE sitemap_at IS NOT NULL AND (status <> 'published' OR deleted_at IS NOT NULL
+				OR visibility NOT IN ('public', 'members'))
+			LIMIT $1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(removed) > 0 {
+		query := `DELETE FROM sitemap_urls WHERE path IN (SELECT '/posts/' || id FROM UNNEST($1::UUID[]) AS id)`
+		if _, err := tx.ExecContext(ctx, query, pq.Array(removed)); err != nil {
+			return 0, err
+		}
+	}
+
+	changed, err := queryIDs(ctx, tx, `
+		UPDATE posts SET sitemap_at = updated_at
+		WHERE id IN (
+			SELECT id FROM posts
+			WHERE status = 'published' AND deleted_at IS NULL
+				AND visibility IN ('public', 'members')
+				AND (sitemap_at IS NULL OR updated_at > sitemap_at)
+			LIMIT $1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id
+	`, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(changed) > 0 {
+		query := `
+			INSERT INTO sitemap_urls (path, lastmod)
+			SELECT '/posts/' || id, updated_at FROM posts WHERE id = ANY($1::UUID[])
+			ON CONFLICT (path) DO UPDATE SET lastmod = EXCLUDED.lastmod
+		`
+		if _, err := tx.ExecContext(ctx, query, pq.Array(changed)); err != nil {
+			return 0, err
+		}
+	}
+
+	touched := append(removed, changed...)
+	if len(touched) == 0 {
+		return 0, nil
+	}
+	for _, query := range scopeQueries {
+		if _, err := tx.ExecContext(ctx, query, pq.Array(touched)); err != nil {
+			return 0, err
+		}
+	}
+	// The posts are locked by the updates above, so no edit can have
+	// recorded a page since.
+	query := `DELETE FROM sitemap_previous_scopes WHERE post_id = ANY($1::UUID[])`
+	if _, err := tx.ExecContext(ctx, query, pq.Array(touched)); err != nil {
+		return 0, err
+	}
+	return len(touched), tx.Commit()
+}
+
+func (r *repository) ListFiles(ctx context.Context, size int) ([]*sitemapdomain.File, error) {
+	query := `
+		SELECT page, MAX(lastmod) FROM (
+			SELECT (ROW_NUMBER() OVER (ORDER BY id) - 1) / $1 + 1 AS page, lastmod FROM sitemap_urls
+		) urls
+		GROUP BY page
+		ORDER BY page
+	`
+	rows, err := r.db.QueryContext(ctx, query, size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []*sitemapdomain.File
+	for rows.Next() {
+		file := new(sitemapdomain.File)
+		if err := rows.Scan(&file.Page, &file.LastMod); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+func (r *repository) ListURLs(ctx context.Context, page, size int) ([]*sitemapdomain.URL, error) {
+	// Post paths are /posts/<id>; the CASE keeps other paths from being
+	// cast to a UUID.
+	query := `
+		SELECT su.path, su.lastmod,
+			CASE WHEN p.id IS NULL THEN '{}' ELSE ARRAY[p.locale] || (
+				SELECT COALESCE(array_agg(tr.locale ORDER BY tr.locale), '{}')
+				FROM post_translations tr WHERE tr.post_id = p.id
+			) END
+		FROM sitemap_urls su
+		LEFT JOIN posts p ON p.id = CASE WHEN su.path LIKE '/posts/%' THEN SUBSTRING(su.path FROM 8)::UUID END
+		ORDER BY su.id LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, size, (page-1)*size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []*sitemapdomain.URL
+	for rows.Next() {
+		url := new(sitemapdomain.URL)
+		if err := rows.Scan(&url.Path, &url.LastMod, pq.Array(&url.Locales)); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+	return urls, rows.Err()
+}
+
+func queryIDs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -18,6 +18,7 @@ type UserModel struct {
 	Email        string    `db:"email"`
 	PasswordHash string    `db:"password_hash"`
 	Role         string    `db:"role"`
+	Version      int       `db:"version"`
 	CreatedAt    time.Time `db:"created_at"`
 	UpdatedAt    time.Time `db:"updated_at"`
 }
@@ -28,7 +29,9 @@ type Repository interface {
 	FindByEmail(ctx context.Context, id string) (*userdomain.User, error)
 	List(ctx context.Context) ([]*userdomain.User, error)
 	Update(ctx context.Context, input *userdomain.User) (*userdomain.User, error)
-	Delete(ctx context.Context, id string) error
+	// Delete removes the user, taking their reactions off the counters on
+	// posts in the same transaction.
+	Delete(ctx context.Context, id string, version int) error
 }
 
 type repository struct {
@@ -44,7 +47,7 @@ func (r *repository) Insert(ctx context.Context, input *userdomain.User) (*userd
 	query := `
 		INSERT INTO users (first_name, last_name, email, password_hash, role)
 		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at, updated_at
+		RETURNING id, version, created_at, updated_at
 	`
 	err := r.db.QueryRowContext(
 		ctx,
@@ -54,7 +57,7 @@ func (r *repository) Insert(ctx context.Context, input *userdomain.User) (*userd
 		m.Email,
 		m.PasswordHash,
 		m.Role,
-	).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+	).Scan(&m.ID, &m.Version, &m.CreatedAt, &m.UpdatedAt)
 
 	if err != nil {
 		return nil, err
@@ -65,7 +68,7 @@ func (r *repository) Insert(ctx context.Context, input *userdomain.User) (*userd
 func (r *repository) FindByID(ctx context.Context, id string) (*userdomain.User, error) {
 	m := new(UserModel)
 	query := `
-		SELECT id, first_name, last_name, email, role, created_at, updated_at
+		SELECT id, first_name, last_name, email, role, version, created_at, updated_at
 		FROM users WHERE id = $1 LIMIT 1
 	`
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
@@ -74,6 +77,7 @@ func (r *repository) FindByID(ctx context.Context, id string) (*userdomain.User,
 		&m.LastName,
 		&m.Email,
 		&m.Role,
+		&m.Version,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 	)
@@ -89,7 +93,7 @@ func (r *repository) FindByID(ctx context.Context, id string) (*userdomain.User,
 func (r *repository) FindByEmail(ctx context.Context, email string) (*userdomain.User, error) {
 	m := new(UserModel)
 	query := `
-		SELECT id, first_name, last_name, email, password_hash, role, created_at, updated_at
+		SELECT id, first_name, last_name, email, password_hash, role, version, created_at, updated_at
 		FROM users WHERE email = $1 LIMIT 1
 	`
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
@@ -99,6 +103,7 @@ func (r *repository) FindByEmail(ctx context.Context, email string) (*userdomain
 		&m.Email,
 		&m.PasswordHash,
 		&m.Role,
+		&m.Version,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 	)
@@ -113,7 +118,7 @@ func (r *repository) FindByEmail(ctx context.Context, email string) (*userdomain
 
 func (r *repository) List(ctx context.Context) ([]*userdomain.User, error) {
 	query := `
-		SELECT id, first_name, last_name, email, role, created_at, updated_at
+		SELECT id, first_name, last_name, email, role, version, created_at, updated_at
 		FROM users
 	`
 	rows, err := r.db.QueryContext(ctx, query)
@@ -131,6 +136,7 @@ func (r *repository) List(ctx context.Context) ([]*userdomain.User, error) {
 			&u.LastName,
 			&u.Email,
 			&u.Role,
+			&u.Version,
 			&u.CreatedAt,
 			&u.UpdatedAt,
 		)
@@ -162,11 +168,18 @@ func (r *repository) Update(ctx context.Context, input *userdomain.User) (*userd
 		idx++
 	}
 
+	if input.Role != "" {
+		sb.WriteString(fmt.Sprintf("role = $%d,", idx))
+		args = append(args, input.Role)
+		idx++
+	}
+
 	sb.WriteString(fmt.Sprintf(`
-	 	updated_at = NOW() WHERE id = $%d
-		RETURNING id, first_name, last_name, email, role, created_at, updated_at
-	`, idx))
-	args = append(args, input.ID)
+	 	version = version + 1, updated_at = NOW()
+		WHERE id = $%d AND ($%d = 0 OR version = $%d)
+		RETURNING id, first_name, last_name, email, role, version, created_at, updated_at
+	`, idx, idx+1, idx+1))
+	args = append(args, input.ID, input.Version)
 
 	m := new(UserModel)
 	query := sb.String()
@@ -176,20 +189,32 @@ func (r *repository) Update(ctx context.Context, input *userdomain.User) (*userd
 		&m.LastName,
 		&m.Email,
 		&m.Role,
+		&m.Version,
 		&m.CreatedAt,
 		&m.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errs.ErrUserNotFound
+			return nil, r.notFoundOrConflict(ctx, input.ID)
 		}
 		return nil, err
 	}
 	return r.modelToDomain(m), nil
 }
 
-func (r *repository) Delete(ctx context.Context, id string) error {
-	res, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE id = $1", id)
+func (r *repository) Delete(ctx context.Context, id string, version int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.removeReactions(ctx, tx, id); err != nil {
+		return err
+	}
+
+	query := "DELETE FROM users WHERE id = $1 AND ($2 = 0 OR version = $2)"
+	res, err := tx.ExecContext(ctx, query, id, version)
 	if err != nil {
 		return err
 	}
@@ -198,9 +223,45 @@ func (r *repository) Delete(ctx context.Context, id string) error {
 		return err
 	}
 	if rows == 0 {
-		return errs.ErrUserNotFound
+		return r.notFoundOrConflict(ctx, id)
+	}
+	return tx.Commit()
+}
+
+// removeReactions takes the reactions of a user off the counters on posts
+// before the cascade deletes them, the way removing each of them would.
+// Decrementing rather than recounting keeps concurrent reactions to the same
+// posts from being lost.
+func (r *repository) removeReactions(ctx context.Context, tx *sql.Tx, userID string) error {
+	_, err := tx.ExecContext(ctx, `
+		WITH removed AS (
+			DELETE FROM post_reactions WHERE user_id = $1
+			RETURNING post_id, reaction
+		), by_post AS (
+			SELECT post_id, array_agg(reaction::TEXT) AS reactions FROM removed GROUP BY post_id
+		)
+		UPDATE posts p SET
+			reaction_counts = (
+				SELECT COALESCE(jsonb_object_agg(c.key, c.n), '{}') FROM (
+					SELECT key, value::INT - CASE WHEN key = ANY(b.reactions) THEN 1 ELSE 0 END AS n
+					FROM jsonb_each_text(p.reaction_counts)
+				) AS c
+				WHERE c.n > 0
+			),
+			reacted_at = NOW()
+		FROM by_post b
+		WHERE p.id = b.post_id
+	`, userID)
+	return err
+}
+
+// notFoundOrConflict tells a missing user apart from a stale version after
+// a compare-and-swap matched no row.
+func (r *repository) notFoundOrConflict(ctx context.Context, id string) error {
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return err
 	}
-	return nil
+	return errs.ErrVersionConflict
 }
 
 func (r *repository) inputToModel(input *userdomain.User) *UserModel {
@@ -211,6 +272,7 @@ func (r *repository) inputToModel(input *userdomain.User) *UserModel {
 		Email:        input.Email,
 		PasswordHash: input.PasswordHash,
 		Role:         input.Role,
+		Version:      input.Version,
 		CreatedAt:    input.CreatedAt,
 		UpdatedAt:    input.UpdatedAt,
 	}
@@ -224,6 +286,7 @@ func (r *repository) modelToDomain(input *UserModel) *userdomain.User {
 		Email:        input.Email,
 		PasswordHash: input.PasswordHash,
 		Role:         input.Role,
+		Version:      input.Version,
 		CreatedAt:    input.CreatedAt,
 		UpdatedAt:    input.UpdatedAt,
 	}
@@ -0,0 +1,198 @@
+package wordpressrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	wordpressdomain "github.com/codepnw/blog-api/internal/domains/wordpress"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/lib/pq"
+)
+
+const (
+	sourceWordPress = "wordpress"
+
+	RefPost    = "post"
+	RefComment = "comment"
+)
+
+const jobColumns = `id, site, status, total, processed, counts, errors, error, created_by,
+	created_at, updated_at, finished_at`
+
+type Repository interface {
+	// Jobs
+	InsertJob(ctx context.Context, input *wordpressdomain.ImportJob) (*wordpressdomain.ImportJob, error)
+	// UpdateJob saves the progress and outcome of a job.
+	UpdateJob(ctx context.Context, input *wordpressdomain.ImportJob) error
+	FindJob(ctx context.Context, id string) (*wordpressdomain.ImportJob, error)
+
+	// FindRef returns what the WordPress item became, or an empty string
+	// when it hasn't been imported.
+	FindRef(ctx context.Context, site, kind string, wpID int64) (string, error)
+	SaveRef(ctx context.Context, site, kind string, wpID int64, localID string) error
+	// InsertComment creates the comment with its original date and records
+	// it as imported in one transaction. It reports false when an earlier run
+	// already imported it.
+	InsertComment(ctx context.Context, site string, input *wordpressdomain.Comment) (bool, error)
+	// InsertTags creates the tags that don't exist yet and returns how many
+	// it created. Names match case-insensitively.
+	InsertTags(ctx context.Context, names []string) (int64, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewWordPressRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) InsertJob(ctx context.Context, input *wordpressdomain.ImportJob) (*wordpressdomain.ImportJob, error) {
+	query := `
+		INSERT INTO import_jobs (source, site, total, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + jobColumns
+
+	return r.scanJob(r.db.QueryRowContext(ctx, query, sourceWordPress, input.Site, input.Total, input.CreatedBy))
+}
+
+func (r *repository) UpdateJob(ctx context.Context, input *wordpressdomain.ImportJob) error {
+	counts, err := json.Marshal(input.Counts)
+	if err != nil {
+		return err
+	}
+	errorList, err := json.Marshal(input.Errors)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE import_jobs SET status = $1, processed = $2, counts = $3, errors = $4, error = $5,
+			finished_at = $6, updated_at = NOW()
+		WHERE id = $7
+	`
+	_, err = r.db.ExecContext(ctx, query, input.Status, input.Processed, counts, errorList, input.Error, input.FinishedAt, input.ID)
+	return err
+}
+
+func (r *repository) FindJob(ctx context.Context, id string) (*wordpressdomain.ImportJob, error) {
+	query := `SELECT ` + jobColumns + ` FROM import_jobs WHERE id = $1 AND source = $2`
+
+	job, err := r.scanJob(r.db.QueryRowContext(ctx, query, id, sourceWordPress))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrImportJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *repository) FindRef(ctx context.Context, site, kind string, wpID int64) (string, error) {
+	var localID string
+	query := `SELECT local_id FROM wordpress_refs WHERE site = $1 AND kind = $2 AND wp_id = $3`
+	err := r.db.QueryRowContext(ctx, query, site, kind, wpID).Scan(&localID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	return localID, nil
+}
+
+func (r *repository) SaveRef(ctx context.Context, site, kind string, wpID int64, localID string) error {
+	query := `
+		INSERT INTO wordpress_refs (site, kind, wp_id, local_id) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (site, kind, wp_id) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, site, kind, wpID, localID)
+	return err
+}
+
+func (r *repository) InsertComment(ctx context.Context, site string, input *wordpressdomain.Comment) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// The reference is claimed first, so a second run racing on the same
+	// comment waits for this one and then finds it taken.
+	query := `
+		INSERT INTO wordpress_refs (site, kind, wp_id, local_id) VALUES ($1, $2, $3, '')
+		ON CONFLICT (site, kind, wp_id) DO NOTHING
+	`
+	res, err := tx.ExecContext(ctx, query, site, RefComment, input.WPID)
+	if err != nil {
+		return false, err
+	}
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+		return false, err
+	}
+
+	var id int64
+	query = `
+		INSERT INTO comments (post_id, user_id, content, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		RETURNING id
+	`
+	if err := tx.QueryRowContext(ctx, query, input.PostID, input.UserID, input.Content, input.CreatedAt).Scan(&id); err != nil {
+		return false, err
+	}
+
+	query = `UPDATE wordpress_refs SET local_id = $1 WHERE site = $2 AND kind = $3 AND wp_id = $4`
+	if _, err := tx.ExecContext(ctx, query, strconv.FormatInt(id, 10), site, RefComment, input.WPID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *repository) InsertTags(ctx context.Context, names []string) (int64, error) {
+	if len(names) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		INSERT INTO tags (name)
+		SELECT DISTINCT ON (LOWER(n)) n FROM UNNEST($1::TEXT[]) AS n
+		WHERE NOT EXISTS (SELECT 1 FROM tags t WHERE LOWER(t.name) = LOWER(n))
+	`
+	res, err := r.db.ExecContext(ctx, query, pq.Array(names))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *repository) scanJob(row rowScanner) (*wordpressdomain.ImportJob, error) {
+	var (
+		job               = new(wordpressdomain.ImportJob)
+		counts, errorList []byte
+	)
+	err := row.Scan(
+		&job.ID,
+		&job.Site,
+		&job.Status,
+		&job.Total,
+		&job.Processed,
+		&counts,
+		&errorList,
+		&job.Error,
+		&job.CreatedBy,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	_ = json.Unmarshal(counts, &job.Counts)
+	job.Errors = []wordpressdomain.ImportError{}
+	_ = json.Unmarshal(errorList, &job.Errors)
+	return job, nil
+}
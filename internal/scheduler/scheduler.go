@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepnw/blog-api/internal/utils/logger"
+)
+
+type JobFunc func(ctx context.Context) error
+
+// Every runs fn in the background once per interval until ctx is cancelled.
+// A failed run is logged and retried on the next tick.
+func Every(ctx context.Context, name string, interval time.Duration, fn JobFunc) {
+	if interval <= 0 {
+		logger.Warn("scheduler.Every: job disabled", "job", name)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					logger.Error("scheduler.Every: job failed", "job", name, "error", err)
+				}
+			}
+		}
+	}()
+}
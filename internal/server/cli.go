@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/codepnw/blog-api/internal/config"
+	transferdomain "github.com/codepnw/blog-api/internal/domains/transfer"
+	"github.com/codepnw/blog-api/internal/notify"
+	categoryrepo "github.com/codepnw/blog-api/internal/repositories/category"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	userrepo "github.com/codepnw/blog-api/internal/repositories/user"
+	categoryusecase "github.com/codepnw/blog-api/internal/usecases/category"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	transferusecase "github.com/codepnw/blog-api/internal/usecases/transfer"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	jwttoken "github.com/codepnw/blog-api/internal/utils/jwt"
+)
+
+// Import runs the import subcommand:
+//
+//	import [-dry-run] [-author email] <dir|file.zip>
+func Import(envPath string, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without creating anything")
+	author := flags.String("author", "", "email of the author of files that don't name one")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import [-dry-run] [-author email] <dir|file.zip>")
+	}
+
+	files, err := readSource(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cfg, db, err := setup(envPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	uc, userUc, err := newTransferUsecase(cfg, db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	opts := transferdomain.ImportOptions{DryRun: *dryRun}
+	if *author != "" {
+		user, err := userUc.GetUserByEmail(ctx, *author)
+		if err != nil {
+			return fmt.Errorf("author %s: %w", *author, err)
+		}
+		opts.AuthorID = user.ID
+	}
+
+	report, err := uc.Import(ctx, files, opts)
+	if err != nil {
+		return err
+	}
+	printReport(report)
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d files failed", report.Failed, len(report.Files))
+	}
+	return nil
+}
+
+// Export runs the export subcommand:
+//
+//	export <dir|file.zip>
+func Export(envPath string, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: export <dir|file.zip>")
+	}
+	target := args[0]
+
+	cfg, db, err := setup(envPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	uc, _, err := newTransferUsecase(cfg, db)
+	if err != nil {
+		return err
+	}
+	files, err := uc.Export(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if isZip(target) {
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		if err := transferusecase.WriteZip(out, files); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	} else if err := transferusecase.WriteDir(target, files); err != nil {
+		return err
+	}
+
+	fmt.Printf("exported %d posts to %s\n", len(files), target)
+	return nil
+}
+
+func newTransferUsecase(cfg *config.EnvConfig, db *sql.DB) (transferusecase.Usecase, userusecase.Usecase, error) {
+	token, err := jwttoken.InitJWT(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userUc := userusecase.NewUserUsecase(userrepo.NewUserRepository(db), token)
+	uc := transferusecase.NewTransferUsecase(
+		postusecase.NewPostUsecase(postrepo.NewPostRepository(db), notify.NewLogNotifier()),
+		categoryusecase.NewCategoryUsecase(categoryrepo.NewCategoryRepository(db)),
+		userUc,
+	)
+	return uc, userUc, nil
+}
+
+func readSource(source string) ([]*transferdomain.File, error) {
+	if !isZip(source) {
+		return transferusecase.ReadDir(source)
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+	return transferusecase.ReadZip(data)
+}
+
+func isZip(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".zip")
+}
+
+func printReport(report *transferdomain.ImportReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tRESULT\tSTATUS\tSLUG\tDETAIL")
+	for _, item := range report.Files {
+		detail := item.PostID
+		if item.Error != "" {
+			detail = item.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", item.File, item.Result, item.PostStatus, item.Slug, detail)
+	}
+	w.Flush()
+
+	verb := "created"
+	if report.DryRun {
+		verb = "to create"
+	}
+	fmt.Printf("\n%d %s, %d skipped, %d failed\n", report.Created, verb, report.Skipped, report.Failed)
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/codepnw/blog-api/internal/config"
+	"github.com/codepnw/blog-api/internal/notify"
+	commentrepo "github.com/codepnw/blog-api/internal/repositories/comment"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	sitemaprepo "github.com/codepnw/blog-api/internal/repositories/sitemap"
+	userrepo "github.com/codepnw/blog-api/internal/repositories/user"
+	"github.com/codepnw/blog-api/internal/scheduler"
+	commentusecase "github.com/codepnw/blog-api/internal/usecases/comment"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	sitemapusecase "github.com/codepnw/blog-api/internal/usecases/sitemap"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	jwttoken "github.com/codepnw/blog-api/internal/utils/jwt"
+)
+
+// startJobs launches the in-process background jobs. Every job must be safe
+// to run on several API instances at the same time.
+func startJobs(ctx context.Context, cfg *config.EnvConfig, db *sql.DB, token *jwttoken.JWTToken, notifier notify.Notifier) {
+	userUc := userusecase.NewUserUsecase(userrepo.NewUserRepository(db), token)
+	postUc := postusecase.NewPostUsecase(postrepo.NewPostRepository(db), notifier)
+	sitemapUc := sitemapusecase.NewSitemapUsecase(sitemaprepo.NewSitemapRepository(db))
+	commentUc := commentusecase.NewCommentUsecase(commentrepo.NewCommentRepository(db), userUc, postUc)
+
+	scheduler.Every(ctx, "publish-scheduled-posts", cfg.Job.PublishInterval, postUc.PublishScheduled)
+	scheduler.Every(ctx, "render-post-content", cfg.Job.RenderInterval, postUc.RenderPending)
+	scheduler.Every(ctx, "refresh-related-posts", cfg.Job.RelatedInterval, postUc.RefreshRelated)
+	scheduler.Every(ctx, "refresh-sitemap", cfg.Job.SitemapInterval, sitemapUc.Refresh)
+	scheduler.Every(ctx, "purge-trash", cfg.Job.PurgeInterval, func(ctx context.Context) error {
+		if err := commentUc.PurgeTrash(ctx, cfg.Job.TrashRetention); err != nil {
+			return err
+		}
+		return postUc.PurgeTrash(ctx, cfg.Job.TrashRetention)
+	})
+	scheduler.Every(ctx, "purge-autosaves", cfg.Job.PurgeInterval, func(ctx context.Context) error {
+		return postUc.PurgeAutosaves(ctx, cfg.Job.AutosaveRetention)
+	})
+}
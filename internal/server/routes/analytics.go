@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	analyticshandler "github.com/codepnw/blog-api/internal/handlers/analytics"
+)
+
+func (cfg *RouteConfig) AnalyticsRoutes() {
+	handler := analyticshandler.NewAnalyticsHandler(cfg.Analytics)
+
+	statsPath := fmt.Sprintf("%s/posts/:%s/stats", cfg.Prefix, handlers.ParamKeyPostID)
+	cfg.APP.Get(statsPath, cfg.Mid.Authorized(), handler.GetPostStats)
+}
@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	bookmarkhandler "github.com/codepnw/blog-api/internal/handlers/bookmark"
+	bookmarkrepo "github.com/codepnw/blog-api/internal/repositories/bookmark"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	bookmarkusecase "github.com/codepnw/blog-api/internal/usecases/bookmark"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+)
+
+// BookmarkRoutes must be registered before UserRoutes, whose admin group
+// guards everything under /users.
+func (cfg *RouteConfig) BookmarkRoutes() {
+	postUc := postusecase.NewPostUsecase(postrepo.NewPostRepository(cfg.DB), cfg.Notifier)
+	uc := bookmarkusecase.NewBookmarkUsecase(bookmarkrepo.NewBookmarkRepository(cfg.DB), postUc)
+
+	var (
+		sharePath    = fmt.Sprintf("%s/reading-lists", cfg.Prefix)
+		bookmarkPath = fmt.Sprintf("%s/users/me/bookmarks", cfg.Prefix)
+		listsPath    = fmt.Sprintf("%s/users/me/reading-lists", cfg.Prefix)
+		listIDPath   = fmt.Sprintf("%s/:%s", listsPath, handlers.ParamKeyListID)
+		postIDPath   = fmt.Sprintf("/:%s", handlers.ParamKeyPostID)
+	)
+	handler := bookmarkhandler.NewBookmarkHandler(uc, sharePath)
+
+	// Public
+	shareTokenPath := fmt.Sprintf("%s/:%s", sharePath, handlers.ParamKeyShareToken)
+	cfg.APP.Get(shareTokenPath, cfg.Mid.CachePolicy(cfg.Cache.PostsMaxAge), handler.GetSharedList)
+
+	// Bookmarks
+	auth := cfg.Mid.Authorized()
+	cfg.APP.Get(bookmarkPath, auth, handler.GetBookmarks)
+	cfg.APP.Put(bookmarkPath+postIDPath, auth, handler.AddBookmark)
+	cfg.APP.Delete(bookmarkPath+postIDPath, auth, handler.RemoveBookmark)
+
+	// Reading Lists
+	cfg.APP.Get(listsPath, auth, handler.GetMyLists)
+	cfg.APP.Post(listsPath, auth, handler.CreateList)
+	cfg.APP.Get(listIDPath, auth, handler.GetList)
+	cfg.APP.Patch(listIDPath, auth, handler.UpdateList)
+	cfg.APP.Delete(listIDPath, auth, handler.DeleteList)
+	cfg.APP.Put(listIDPath+"/posts"+postIDPath, auth, handler.AddToList)
+	cfg.APP.Delete(listIDPath+"/posts"+postIDPath, auth, handler.RemoveFromList)
+}
@@ -21,13 +21,13 @@ func (cfg *RouteConfig) CategoryRoutes() {
 	)
 
 	// Public
-	public := cfg.APP.Group(basePath)
+	public := cfg.APP.Group(basePath, cfg.Mid.CachePolicy(cfg.Cache.CategoriesMaxAge))
 	public.Get("/", handler.GetAll)
 	public.Get(categoryIDPath, handler.GetByID)
 
 	// Admin Only
 	admin := cfg.APP.Group(basePath, cfg.Mid.Authorized(), cfg.Mid.RoleRequired(string(userusecase.RoleAdmin)))
 	admin.Post("/", handler.Create)
-	admin.Patch(categoryIDPath, handler.Update)
-	admin.Delete(categoryIDPath, handler.Delete)
+	admin.Patch(categoryIDPath, cfg.ifMatch(), handler.Update)
+	admin.Delete(categoryIDPath, cfg.ifMatch(), handler.Delete)
 }
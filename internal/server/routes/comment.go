@@ -20,7 +20,7 @@ func (cfg *RouteConfig) CommentRoutes() {
 
 	// Post Usecase
 	postRepo := postrepo.NewPostRepository(cfg.DB)
-	postUc := postusecase.NewPostUsecase(postRepo)
+	postUc := postusecase.NewPostUsecase(postRepo, cfg.Notifier)
 
 	// Comment
 	repo := commentrepo.NewCommentRepository(cfg.DB)
@@ -33,11 +33,15 @@ func (cfg *RouteConfig) CommentRoutes() {
 	)
 
 	// Public
-	cfg.APP.Get(basePath, handler.GetCommentByPost)
+	cfg.APP.Get(basePath, cfg.Mid.CachePolicy(cfg.Cache.CommentsMaxAge), handler.GetCommentByPost)
 
 	// Private
 	private := cfg.APP.Group(basePath, cfg.Mid.Authorized())
 	private.Post("/", handler.CreateComment)
-	private.Patch(commentIDPath, handler.EditComment)
-	private.Delete(commentIDPath, handler.DeleteComment)
+	private.Patch(commentIDPath, cfg.ifMatch(), handler.EditComment)
+	private.Delete(commentIDPath, cfg.ifMatch(), handler.DeleteComment)
+	private.Post(commentIDPath+"/restore", handler.RestoreComment)
+
+	// Trash
+	cfg.APP.Get(cfg.Prefix+"/trash/comments", cfg.Mid.Authorized(), handler.GetTrash)
 }
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	feedhandler "github.com/codepnw/blog-api/internal/handlers/feed"
+	categoryrepo "github.com/codepnw/blog-api/internal/repositories/category"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	userrepo "github.com/codepnw/blog-api/internal/repositories/user"
+	categoryusecase "github.com/codepnw/blog-api/internal/usecases/category"
+	feedusecase "github.com/codepnw/blog-api/internal/usecases/feed"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+)
+
+// FeedRoutes serves the feeds from the site root, where readers and
+// aggregators look for them, rather than under the API prefix.
+func (cfg *RouteConfig) FeedRoutes() {
+	uc := feedusecase.NewFeedUsecase(
+		postusecase.NewPostUsecase(postrepo.NewPostRepository(cfg.DB), cfg.Notifier),
+		categoryusecase.NewCategoryUsecase(categoryrepo.NewCategoryRepository(cfg.DB)),
+		userusecase.NewUserUsecase(userrepo.NewUserRepository(cfg.DB), cfg.Token),
+		cfg.Feed,
+	)
+	handler := feedhandler.NewFeedHandler(uc, cfg.Feed, cfg.SiteURL, cfg.Prefix)
+
+	scopes := []string{
+		"",
+		fmt.Sprintf("/categories/:%s", handlers.ParamKeyCategoryID),
+		fmt.Sprintf("/users/:%s", handlers.ParamKeyUserID),
+		fmt.Sprintf("/tags/:%s", handlers.ParamKeyTag),
+	}
+
+	cache := cfg.Mid.CachePolicy(cfg.Cache.PostsMaxAge)
+	for _, scope := range scopes {
+		cfg.APP.Get(scope+"/feed.xml", cache, handler.RSS)
+		cfg.APP.Get(scope+"/atom.xml", cache, handler.Atom)
+		cfg.APP.Get(scope+"/feed.json", cache, handler.JSON)
+	}
+}
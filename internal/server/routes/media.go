@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	mediahandler "github.com/codepnw/blog-api/internal/handlers/media"
+	mediarepo "github.com/codepnw/blog-api/internal/repositories/media"
+	"github.com/codepnw/blog-api/internal/storage"
+	mediausecase "github.com/codepnw/blog-api/internal/usecases/media"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (cfg *RouteConfig) MediaRoutes() {
+	repo := mediarepo.NewMediaRepository(cfg.DB)
+	uc := mediausecase.NewMediaUsecase(repo, cfg.Storage, cfg.Media)
+	handler := mediahandler.NewMediaHandler(uc, cfg.Media.MaxSize)
+
+	var (
+		basePath    = fmt.Sprintf("%s/media", cfg.Prefix)
+		mediaIDPath = fmt.Sprintf("/:%s", handlers.ParamKeyMediaID)
+	)
+
+	// Files kept on the local disk are served by the API itself.
+	if cfg.Media.Storage == storage.DriverLocal && strings.HasPrefix(cfg.Media.BaseURL, "/") {
+		cfg.APP.Static(cfg.Media.BaseURL, cfg.Media.LocalDir, fiber.Static{
+			MaxAge: 365 * 24 * 60 * 60,
+		})
+	}
+
+	// Public
+	cfg.APP.Get(basePath+mediaIDPath, handler.GetByID)
+
+	// Authorized
+	auth := cfg.APP.Group(basePath, cfg.Mid.Authorized())
+	auth.Post("/", handler.Upload)
+	auth.Get("/", handler.GetMine)
+	auth.Delete(mediaIDPath, handler.Delete)
+}
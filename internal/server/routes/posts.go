@@ -5,14 +5,26 @@ import (
 
 	"github.com/codepnw/blog-api/internal/handlers"
 	posthandler "github.com/codepnw/blog-api/internal/handlers/post"
+	reactionhandler "github.com/codepnw/blog-api/internal/handlers/reaction"
+	bookmarkrepo "github.com/codepnw/blog-api/internal/repositories/bookmark"
 	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	reactionrepo "github.com/codepnw/blog-api/internal/repositories/reaction"
+	seriesrepo "github.com/codepnw/blog-api/internal/repositories/series"
+	bookmarkusecase "github.com/codepnw/blog-api/internal/usecases/bookmark"
 	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	reactionusecase "github.com/codepnw/blog-api/internal/usecases/reaction"
+	seriesusecase "github.com/codepnw/blog-api/internal/usecases/series"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
 )
 
 func (cfg *RouteConfig) PostRoutes() {
 	repo := postrepo.NewPostRepository(cfg.DB)
-	uc := postusecase.NewPostUsecase(repo)
-	handler := posthandler.NewPostHandler(uc)
+	uc := postusecase.NewPostUsecase(repo, cfg.Notifier)
+	reactionUc := reactionusecase.NewReactionUsecase(reactionrepo.NewReactionRepository(cfg.DB), uc, cfg.Reaction.Types)
+	bookmarkUc := bookmarkusecase.NewBookmarkUsecase(bookmarkrepo.NewBookmarkRepository(cfg.DB), uc)
+	seriesUc := seriesusecase.NewSeriesUsecase(seriesrepo.NewSeriesRepository(cfg.DB), uc)
+	handler := posthandler.NewPostHandler(uc, cfg.Analytics, reactionUc, bookmarkUc, seriesUc, cfg.Locale, cfg.SiteURL, cfg.Prefix)
+	reactionHandler := reactionhandler.NewReactionHandler(reactionUc)
 
 	var (
 		basePath     = fmt.Sprintf("%s/posts", cfg.Prefix)
@@ -20,16 +32,69 @@ func (cfg *RouteConfig) PostRoutes() {
 		userPostPath = fmt.Sprintf("%s/users/:%s/posts", cfg.Prefix, handlers.ParamKeyAuthorID)
 	)
 
-	// Public
-	public := cfg.APP.Group(basePath)
+	// Public (optional auth lets authors see their own drafts)
+	cache := cfg.Mid.CachePolicy(cfg.Cache.PostsMaxAge)
+	public := cfg.APP.Group(basePath, cfg.Mid.OptionalAuthorized(), cache)
 	public.Get("/", handler.GetAll)
+	public.Get("/featured", handler.GetFeatured)
+	public.Get(fmt.Sprintf("/by-slug/:%s", handlers.ParamKeySlug), handler.GetBySlug)
 	public.Get(postIDPath, handler.GetByID)
+	public.Get(postIDPath+"/reactions", reactionHandler.GetByPost)
+	public.Get(postIDPath+"/related", handler.GetRelated)
+	public.Get(postIDPath+"/translations", handler.GetTranslations)
 	// Get By UserID Path
-	cfg.APP.Get(userPostPath, handler.GetByUserID)
+	cfg.APP.Get(userPostPath, cfg.Mid.OptionalAuthorized(), cache, handler.GetByUserID)
 
 	// Authorized
 	auth := cfg.APP.Group(cfg.Prefix+"/posts", cfg.Mid.Authorized())
 	auth.Post("/", handler.Create)
-	auth.Patch(postIDPath, handler.Update)
-	auth.Delete(postIDPath, handler.Delete)
+	auth.Patch(postIDPath, cfg.ifMatch(), handler.Update)
+	auth.Delete(postIDPath, cfg.ifMatch(), handler.Delete)
+	auth.Post(postIDPath+"/publish", cfg.ifMatch(), handler.Publish)
+	auth.Post(postIDPath+"/unpublish", cfg.ifMatch(), handler.Unpublish)
+	auth.Post(postIDPath+"/archive", cfg.ifMatch(), handler.Archive)
+	auth.Post(postIDPath+"/restore", handler.Restore)
+	auth.Put(postIDPath+"/authors", cfg.ifMatch(), handler.SetAuthors)
+
+	// Translations
+	translationPath := fmt.Sprintf("%s/translations/:%s", postIDPath, handlers.ParamKeyLocale)
+	auth.Put(translationPath, cfg.ifMatch(), handler.SetTranslation)
+	auth.Delete(translationPath, cfg.ifMatch(), handler.DeleteTranslation)
+
+	// Pins (curation, not ownership)
+	curator := cfg.Mid.RoleRequired(string(userusecase.RoleAdmin), string(userusecase.RoleEditor))
+	auth.Put(postIDPath+"/pin", curator, handler.Pin)
+	auth.Delete(postIDPath+"/pin", curator, handler.Unpin)
+
+	// Review
+	auth.Post(postIDPath+"/submit", handler.Submit)
+	auth.Post(postIDPath+"/approve", curator, handler.Approve)
+	auth.Post(postIDPath+"/reject", curator, handler.Reject)
+	auth.Get(postIDPath+"/reviews", handler.GetReviews)
+
+	// Edit locks
+	auth.Post(postIDPath+"/lock", handler.Lock)
+	auth.Get(postIDPath+"/lock", handler.GetLock)
+	auth.Delete(postIDPath+"/lock", handler.Unlock)
+
+	// Autosaves
+	auth.Put(postIDPath+"/autosave", handler.SaveAutosave)
+	auth.Get(postIDPath+"/autosave", handler.GetAutosave)
+	auth.Delete(postIDPath+"/autosave", handler.DiscardAutosave)
+	auth.Post(postIDPath+"/autosave/apply", handler.ApplyAutosave)
+
+	// Reactions
+	reactionPath := fmt.Sprintf("%s/reactions/:%s", postIDPath, handlers.ParamKeyReaction)
+	auth.Put(reactionPath, reactionHandler.React)
+	auth.Delete(reactionPath, reactionHandler.Unreact)
+
+	// Trash
+	cfg.APP.Get(cfg.Prefix+"/trash/posts", cfg.Mid.Authorized(), handler.GetTrash)
+
+	// Revisions
+	revisionPath := fmt.Sprintf("%s/revisions/:%s", postIDPath, handlers.ParamKeyRevision)
+	auth.Get(postIDPath+"/revisions", handler.GetRevisions)
+	auth.Get(postIDPath+"/revisions/diff", handler.DiffRevisions)
+	auth.Get(revisionPath, handler.GetRevision)
+	auth.Post(revisionPath+"/restore", cfg.ifMatch(), handler.RestoreRevision)
 }
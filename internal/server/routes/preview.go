@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	previewhandler "github.com/codepnw/blog-api/internal/handlers/preview"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	previewrepo "github.com/codepnw/blog-api/internal/repositories/preview"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	previewusecase "github.com/codepnw/blog-api/internal/usecases/preview"
+)
+
+func (cfg *RouteConfig) PreviewRoutes() {
+	postUc := postusecase.NewPostUsecase(postrepo.NewPostRepository(cfg.DB), cfg.Notifier)
+	uc := previewusecase.NewPreviewUsecase(previewrepo.NewPreviewRepository(cfg.DB), postUc, cfg.Preview)
+
+	var (
+		previewPath = fmt.Sprintf("%s/preview", cfg.Prefix)
+		linksPath   = fmt.Sprintf("%s/posts/:%s/preview-links", cfg.Prefix, handlers.ParamKeyPostID)
+		linkIDPath  = fmt.Sprintf("%s/:%s", linksPath, handlers.ParamKeyLinkID)
+	)
+	handler := previewhandler.NewPreviewHandler(uc, previewPath)
+
+	// Public, the token is the credential
+	cfg.APP.Get(fmt.Sprintf("%s/:%s", previewPath, handlers.ParamKeyToken), handler.Open)
+
+	// Authors and admins
+	auth := cfg.Mid.Authorized()
+	cfg.APP.Post(linksPath, auth, handler.Create)
+	cfg.APP.Get(linksPath, auth, handler.GetByPost)
+	cfg.APP.Delete(linkIDPath, auth, handler.Revoke)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	redirecthandler "github.com/codepnw/blog-api/internal/handlers/redirect"
+	redirectrepo "github.com/codepnw/blog-api/internal/repositories/redirect"
+	redirectusecase "github.com/codepnw/blog-api/internal/usecases/redirect"
+)
+
+// RedirectRoutes catches every GET no other route matched, so it must be
+// registered last.
+func (cfg *RouteConfig) RedirectRoutes() {
+	uc := redirectusecase.NewRedirectUsecase(redirectrepo.NewRedirectRepository(cfg.DB))
+	handler := redirecthandler.NewRedirectHandler(uc, cfg.SiteURL, cfg.Prefix)
+
+	cfg.APP.Get("/*", cfg.Mid.CachePolicy(cfg.Cache.PostsMaxAge), handler.Redirect)
+}
@@ -1,12 +1,17 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
+	"github.com/codepnw/blog-api/internal/config"
 	"github.com/codepnw/blog-api/internal/handlers/docs"
 	_ "github.com/codepnw/blog-api/internal/handlers/post"
 	"github.com/codepnw/blog-api/internal/middleware"
+	"github.com/codepnw/blog-api/internal/notify"
+	"github.com/codepnw/blog-api/internal/storage"
+	analyticsusecase "github.com/codepnw/blog-api/internal/usecases/analytics"
 	jwttoken "github.com/codepnw/blog-api/internal/utils/jwt"
 	"github.com/codepnw/blog-api/internal/utils/validate"
 	"github.com/gofiber/fiber/v2"
@@ -14,12 +19,30 @@ import (
 )
 
 type RouteConfig struct {
-	Mode   string                    `validate:"required"`
-	Prefix string                    `validate:"required"`
-	APP    *fiber.App                `validate:"required"`
-	DB     *sql.DB                   `validate:"required"`
-	Token  *jwttoken.JWTToken        `validate:"required"`
-	Mid    *middleware.AppMiddleware `validate:"required"`
+	Mode    string                    `validate:"required"`
+	Prefix  string                    `validate:"required"`
+	APP     *fiber.App                `validate:"required"`
+	DB      *sql.DB                   `validate:"required"`
+	Token   *jwttoken.JWTToken        `validate:"required"`
+	Mid     *middleware.AppMiddleware `validate:"required"`
+	Storage storage.Storage           `validate:"required"`
+	// Notifier delivers the notifications of the editorial workflow.
+	Notifier notify.Notifier `validate:"required"`
+	// Analytics is shared because its view writer runs in the background.
+	Analytics analyticsusecase.Usecase `validate:"required"`
+	// Jobs is the context of work requests leave running in the background,
+	// cancelled when the server stops.
+	Jobs context.Context `validate:"required"`
+
+	RequireIfMatch bool
+	SiteURL        string
+	Cache          config.CacheConfig
+	Media          config.MediaConfig
+	Reaction       config.ReactionConfig
+	Feed           config.FeedConfig
+	Robots         config.RobotsConfig
+	Locale         config.LocaleConfig
+	Preview        config.PreviewConfig
 }
 
 func RegisterRoutes(cfg *RouteConfig) (*RouteConfig, error) {
@@ -43,3 +66,12 @@ func RegisterRoutes(cfg *RouteConfig) (*RouteConfig, error) {
 
 	return cfg, nil
 }
+
+// ifMatch enforces If-Match on PATCH and DELETE routes when enabled in config.
+// Handlers always honour the header when it is sent.
+func (cfg *RouteConfig) ifMatch() fiber.Handler {
+	if !cfg.RequireIfMatch {
+		return func(ctx *fiber.Ctx) error { return ctx.Next() }
+	}
+	return cfg.Mid.IfMatchRequired()
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	serieshandler "github.com/codepnw/blog-api/internal/handlers/series"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	seriesrepo "github.com/codepnw/blog-api/internal/repositories/series"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	seriesusecase "github.com/codepnw/blog-api/internal/usecases/series"
+)
+
+func (cfg *RouteConfig) SeriesRoutes() {
+	postUc := postusecase.NewPostUsecase(postrepo.NewPostRepository(cfg.DB), cfg.Notifier)
+	uc := seriesusecase.NewSeriesUsecase(seriesrepo.NewSeriesRepository(cfg.DB), postUc)
+	handler := serieshandler.NewSeriesHandler(uc)
+
+	var (
+		basePath     = fmt.Sprintf("%s/series", cfg.Prefix)
+		seriesIDPath = fmt.Sprintf("/:%s", handlers.ParamKeySeriesID)
+	)
+
+	// Public (optional auth lets authors see their unpublished parts)
+	public := cfg.APP.Group(basePath, cfg.Mid.OptionalAuthorized(), cfg.Mid.CachePolicy(cfg.Cache.PostsMaxAge))
+	public.Get(seriesIDPath, handler.GetByID)
+
+	// Authorized
+	auth := cfg.APP.Group(basePath, cfg.Mid.Authorized())
+	auth.Post("/", handler.Create)
+	auth.Patch(seriesIDPath, handler.Update)
+	auth.Delete(seriesIDPath, handler.Delete)
+	auth.Post(seriesIDPath+"/posts", handler.AddPost)
+	auth.Delete(fmt.Sprintf("%s/posts/:%s", seriesIDPath, handlers.ParamKeyPostID), handler.RemovePost)
+	auth.Put(seriesIDPath+"/order", handler.Reorder)
+}
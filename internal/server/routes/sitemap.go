@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	sitemaphandler "github.com/codepnw/blog-api/internal/handlers/sitemap"
+	sitemaprepo "github.com/codepnw/blog-api/internal/repositories/sitemap"
+	sitemapusecase "github.com/codepnw/blog-api/internal/usecases/sitemap"
+)
+
+// SitemapRoutes serves the sitemap and robots.txt from the site root, where
+// crawlers look for them.
+func (cfg *RouteConfig) SitemapRoutes() {
+	uc := sitemapusecase.NewSitemapUsecase(sitemaprepo.NewSitemapRepository(cfg.DB))
+	handler := sitemaphandler.NewSitemapHandler(uc, cfg.Robots, cfg.SiteURL, cfg.Prefix)
+
+	cache := cfg.Mid.CachePolicy(cfg.Cache.PostsMaxAge)
+	cfg.APP.Get("/sitemap.xml", cache, handler.Index)
+	cfg.APP.Get(fmt.Sprintf("/sitemaps/:%s.xml", handlers.ParamKeyPage), cache, handler.File)
+	cfg.APP.Get("/robots.txt", cache, handler.Robots)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	transferhandler "github.com/codepnw/blog-api/internal/handlers/transfer"
+	categoryrepo "github.com/codepnw/blog-api/internal/repositories/category"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	userrepo "github.com/codepnw/blog-api/internal/repositories/user"
+	categoryusecase "github.com/codepnw/blog-api/internal/usecases/category"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	transferusecase "github.com/codepnw/blog-api/internal/usecases/transfer"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+)
+
+func (cfg *RouteConfig) TransferRoutes() {
+	uc := transferusecase.NewTransferUsecase(
+		postusecase.NewPostUsecase(postrepo.NewPostRepository(cfg.DB), cfg.Notifier),
+		categoryusecase.NewCategoryUsecase(categoryrepo.NewCategoryRepository(cfg.DB)),
+		userusecase.NewUserUsecase(userrepo.NewUserRepository(cfg.DB), cfg.Token),
+	)
+	handler := transferhandler.NewTransferHandler(uc)
+
+	// Admin Only
+	admin := cfg.APP.Group(cfg.Prefix+"/admin", cfg.Mid.Authorized(), cfg.Mid.RoleRequired(string(userusecase.RoleAdmin)))
+	admin.Post("/import", handler.Import)
+	admin.Get("/export", handler.Export)
+}
@@ -30,6 +30,6 @@ func (cfg *RouteConfig) UserRoutes() {
 	admin.Post("/", handler.CreateUser)
 	admin.Get("/", handler.GetAllUsers)
 	admin.Get(userID, handler.GetUser)
-	admin.Patch(userID, handler.UpdateUser)
-	admin.Delete(userID, handler.DeleteUser)
+	admin.Patch(userID, cfg.ifMatch(), handler.UpdateUser)
+	admin.Delete(userID, cfg.ifMatch(), handler.DeleteUser)
 }
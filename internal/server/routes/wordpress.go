@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"fmt"
+
+	"github.com/codepnw/blog-api/internal/handlers"
+	wordpresshandler "github.com/codepnw/blog-api/internal/handlers/wordpress"
+	categoryrepo "github.com/codepnw/blog-api/internal/repositories/category"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	redirectrepo "github.com/codepnw/blog-api/internal/repositories/redirect"
+	userrepo "github.com/codepnw/blog-api/internal/repositories/user"
+	wordpressrepo "github.com/codepnw/blog-api/internal/repositories/wordpress"
+	categoryusecase "github.com/codepnw/blog-api/internal/usecases/category"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	redirectusecase "github.com/codepnw/blog-api/internal/usecases/redirect"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	wordpressusecase "github.com/codepnw/blog-api/internal/usecases/wordpress"
+)
+
+func (cfg *RouteConfig) WordPressRoutes() {
+	uc := wordpressusecase.NewWordPressUsecase(
+		cfg.Jobs,
+		wordpressrepo.NewWordPressRepository(cfg.DB),
+		postusecase.NewPostUsecase(postrepo.NewPostRepository(cfg.DB), cfg.Notifier),
+		categoryusecase.NewCategoryUsecase(categoryrepo.NewCategoryRepository(cfg.DB)),
+		userusecase.NewUserUsecase(userrepo.NewUserRepository(cfg.DB), cfg.Token),
+		redirectusecase.NewRedirectUsecase(redirectrepo.NewRedirectRepository(cfg.DB)),
+	)
+	handler := wordpresshandler.NewWordPressHandler(uc)
+
+	// Admin Only
+	admin := cfg.APP.Group(cfg.Prefix+"/admin/import", cfg.Mid.Authorized(), cfg.Mid.RoleRequired(string(userusecase.RoleAdmin)))
+	admin.Post("/wordpress", handler.Import)
+	admin.Get(fmt.Sprintf("/jobs/:%s", handlers.ParamKeyJobID), handler.GetJob)
+}
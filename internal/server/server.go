@@ -1,34 +1,39 @@
 package server
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/codepnw/blog-api/internal/config"
 	"github.com/codepnw/blog-api/internal/database"
 	"github.com/codepnw/blog-api/internal/middleware"
+	"github.com/codepnw/blog-api/internal/notify"
+	analyticsrepo "github.com/codepnw/blog-api/internal/repositories/analytics"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
 	"github.com/codepnw/blog-api/internal/server/routes"
+	"github.com/codepnw/blog-api/internal/storage"
+	analyticsusecase "github.com/codepnw/blog-api/internal/usecases/analytics"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
 	jwttoken "github.com/codepnw/blog-api/internal/utils/jwt"
 	"github.com/codepnw/blog-api/internal/utils/logger"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
+// shutdownTimeout bounds how long requests in flight may take to finish once
+// the server is asked to stop.
+const shutdownTimeout = 10 * time.Second
+
 func Run(envPath string) error {
-	// Load Config
-	cfg, err := config.LoadConfig(envPath)
+	cfg, db, err := setup(envPath)
 	if err != nil {
 		return err
 	}
-
-	// Logger
-	logger.Init(cfg.APP.Mode)
-
-	// Connect Database
-	db, err := database.ConnectPostgres(cfg)
-	if err != nil {
-		logger.Error("server.Run: connect database", "error", err)
-		return fmt.Errorf("connect database failed")
-	}
 	defer db.Close()
 
 	// Init JWT Token
@@ -45,21 +50,65 @@ func Run(envPath string) error {
 		return err
 	}
 
-	app := fiber.New()
+	// Media Storage
+	store, err := storage.New(context.Background(), cfg.Media)
+	if err != nil {
+		logger.Error("server.Run: storage init", "error", err)
+		return err
+	}
+
+	// Notifications
+	notifier := notify.NewLogNotifier()
+
+	// Background Jobs
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+
+	// Post View Analytics
+	analyticsUc := analyticsusecase.NewAnalyticsUsecase(
+		analyticsrepo.NewAnalyticsRepository(db),
+		postusecase.NewPostUsecase(postrepo.NewPostRepository(db), notifier),
+		cfg.Analytics,
+	)
+	analyticsDone := make(chan struct{})
+	go func() {
+		defer close(analyticsDone)
+		analyticsUc.Run(jobCtx)
+	}()
+
+	// Leave room for the multipart framing around an upload.
+	app := fiber.New(fiber.Config{
+		BodyLimit: int(cfg.Media.MaxSize) + 1<<20,
+	})
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
+		AllowOrigins:  "*",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, If-Match, If-None-Match, If-Modified-Since",
+		ExposeHeaders: "ETag, Last-Modified",
+		AllowMethods:  "GET,POST,PUT,PATCH,DELETE,OPTIONS",
 	}))
 
 	// Register Routes
 	routesConfig := &routes.RouteConfig{
-		Mode:   cfg.APP.Mode,
-		Prefix: fmt.Sprintf("/api/v%d", cfg.APP.Version),
-		APP:    app,
-		DB:     db,
-		Token:  token,
-		Mid:    mid,
+		Mode:      cfg.APP.Mode,
+		Prefix:    fmt.Sprintf("/api/v%d", cfg.APP.Version),
+		APP:       app,
+		DB:        db,
+		Token:     token,
+		Mid:       mid,
+		Storage:   store,
+		Notifier:  notifier,
+		Analytics: analyticsUc,
+		Jobs:      jobCtx,
+
+		RequireIfMatch: cfg.APP.RequireIfMatch,
+		SiteURL:        cfg.APP.SiteURL,
+		Cache:          cfg.Cache,
+		Media:          cfg.Media,
+		Reaction:       cfg.Reaction,
+		Feed:           cfg.Feed,
+		Robots:         cfg.Robots,
+		Locale:         cfg.Locale,
+		Preview:        cfg.Preview,
 	}
 	r, err := routes.RegisterRoutes(routesConfig)
 	if err != nil {
@@ -69,17 +118,72 @@ func Run(envPath string) error {
 	// Init Routes
 	r.CategoryRoutes()
 	r.PostRoutes()
+	r.BookmarkRoutes()
+	r.SeriesRoutes()
+	r.PreviewRoutes()
 	r.UserRoutes()
 	r.CommentRoutes()
+	r.MediaRoutes()
+	r.AnalyticsRoutes()
+	r.FeedRoutes()
+	r.SitemapRoutes()
+	r.TransferRoutes()
+	r.WordPressRoutes()
+	// Last, since it catches every path the others don't.
+	r.RedirectRoutes()
+
+	startJobs(jobCtx, cfg, db, token, notifier)
 
 	port := fmt.Sprintf(":%d", cfg.APP.Port)
 	url := fmt.Sprintf("%s%s%s", cfg.APP.Host, port, routesConfig.Prefix)
 	logger.Info(fmt.Sprintf("server running at %s", url))
 	logger.Info(fmt.Sprintf("api docs at %s/docs", url))
 
-	if err := app.Listen(port); err != nil {
+	// Stop on SIGINT or SIGTERM: finish the requests in flight, then stop
+	// the jobs and write the views still buffered.
+	stop, cancelStop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancelStop()
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-stop.Done()
+		logger.Info("server shutting down")
+		if err := app.ShutdownWithTimeout(shutdownTimeout); err != nil {
+			logger.Error("server.Run: app shutdown", "error", err)
+		}
+	}()
+
+	// Listen returns as soon as the listener closes, before the requests
+	// in flight are done.
+	err = app.Listen(port)
+	if err == nil {
+		<-shutdownDone
+	}
+	cancelJobs()
+	<-analyticsDone
+	if err != nil {
 		logger.Error("server.Run: app listen", "error", err)
 		return err
 	}
 	return nil
 }
+
+// setup loads the config, starts the logger and connects to the database.
+func setup(envPath string) (*config.EnvConfig, *sql.DB, error) {
+	// Load Config
+	cfg, err := config.LoadConfig(envPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Logger
+	logger.Init(cfg.APP.Mode)
+
+	// Connect Database
+	db, err := database.ConnectPostgres(cfg)
+	if err != nil {
+		logger.Error("server.setup: connect database", "error", err)
+		return nil, nil, fmt.Errorf("connect database failed")
+	}
+	return cfg, db, nil
+}
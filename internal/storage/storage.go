@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/codepnw/blog-api/internal/config"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Storage keeps uploaded files. Keys are slash separated paths generated by
+// the server, never taken from the client.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL is where clients can download the object.
+	URL(key string) string
+}
+
+// New builds the storage backend selected in config.
+func New(ctx context.Context, cfg config.MediaConfig) (Storage, error) {
+	switch cfg.Storage {
+	case DriverLocal:
+		return NewLocalStorage(cfg.LocalDir, cfg.BaseURL)
+	case DriverS3:
+		return NewS3Storage(ctx, S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			UseSSL:    cfg.S3UseSSL,
+			BaseURL:   cfg.BaseURL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage)
+	}
+}
@@ -0,0 +1,207 @@
+package analyticsusecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/codepnw/blog-api/internal/config"
+	analyticsdomain "github.com/codepnw/blog-api/internal/domains/analytics"
+	analyticsrepo "github.com/codepnw/blog-api/internal/repositories/analytics"
+	"github.com/codepnw/blog-api/internal/usecases"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/logger"
+)
+
+const (
+	maxRangeDays  = 366
+	referrerLimit = 20
+)
+
+// botPattern matches crawlers, link previews and HTTP libraries. Requests
+// without a user agent are treated as bots too.
+var botPattern = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|preview|headless|lighthouse|` +
+	`facebookexternalhit|embedly|curl|wget|python-requests|go-http-client|httpclient|okhttp|axios|postman`)
+
+type Usecase interface {
+	// RecordView queues a view without waiting for the database. Views are
+	// dropped when the buffer is full. The arguments may share memory with a
+	// request buffer that is reused once the handler returns, so whatever is
+	// queued is copied.
+	RecordView(postID, ip, userAgent, referrer string)
+	// Run writes queued views in batches until ctx is done.
+	Run(ctx context.Context)
+	GetPostStats(ctx context.Context, postID, userID, role string, from, to time.Time) (*analyticsdomain.PostStats, error)
+}
+
+type usecase struct {
+	repo   analyticsrepo.Repository
+	postUc postusecase.Usecase
+	cfg    config.AnalyticsConfig
+	secret []byte
+	views  chan *analyticsdomain.View
+}
+
+func NewAnalyticsUsecase(repo analyticsrepo.Repository, postUc postusecase.Usecase, cfg config.AnalyticsConfig) Usecase {
+	return &usecase{
+		repo:   repo,
+		postUc: postUc,
+		cfg:    cfg,
+		secret: []byte(cfg.Secret),
+		views:  make(chan *analyticsdomain.View, cfg.BufferSize),
+	}
+}
+
+func (u *usecase) RecordView(postID, ip, userAgent, referrer string) {
+	if userAgent == "" || botPattern.MatchString(userAgent) {
+		return
+	}
+
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	view := &analyticsdomain.View{
+		PostID:      strings.Clone(postID),
+		Day:         day,
+		VisitorHash: u.visitorHash(day, ip, userAgent),
+		Referrer:    strings.Clone(referrerHost(referrer)),
+	}
+
+	select {
+	case u.views <- view:
+	default:
+		logger.Warn("usecase.RecordView: buffer full, view dropped", "post_id", postID)
+	}
+}
+
+func (u *usecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*analyticsdomain.View, 0, u.cfg.BatchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := u.repo.InsertViews(ctx, batch); err != nil {
+			logger.Error("usecase.RunAnalytics: insert views", "count", len(batch), "error", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case view := <-u.views:
+			batch = append(batch, view)
+			if len(batch) >= u.cfg.BatchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-ctx.Done():
+			// Write what is still queued before giving up.
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), usecases.ContextTimeout)
+			defer cancel()
+			for {
+				select {
+				case view := <-u.views:
+					batch = append(batch, view)
+					if len(batch) >= u.cfg.BatchSize {
+						flush(shutdownCtx)
+					}
+				default:
+					flush(shutdownCtx)
+					return
+				}
+			}
+		}
+	}
+}
+
+// GetPostStats returns the daily views between from and to, both inclusive,
+// with a zero for days without views, and the top referrers.
+func (u *usecase) GetPostStats(ctx context.Context, postID, userID, role string, from, to time.Time) (*analyticsdomain.PostStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	from = from.UTC().Truncate(24 * time.Hour)
+	to = to.UTC().Truncate(24 * time.Hour)
+	if to.Before(from) || to.Sub(from) > maxRangeDays*24*time.Hour {
+		return nil, errs.ErrInvalidDateRange
+	}
+
+	post, err := u.postUc.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if !postusecase.IsAuthor(post, userID) && role != string(userusecase.RoleAdmin) {
+		return nil, errs.ErrStatsForbidden
+	}
+
+	daily, err := u.repo.DailyViews(ctx, postID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	referrers, err := u.repo.Referrers(ctx, postID, from, to, referrerLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &analyticsdomain.PostStats{
+		PostID:    postID,
+		From:      from.Format(time.DateOnly),
+		To:        to.Format(time.DateOnly),
+		Daily:     make([]*analyticsdomain.DailyViews, 0, int(to.Sub(from).Hours()/24)+1),
+		Referrers: referrers,
+	}
+	if stats.Referrers == nil {
+		stats.Referrers = []*analyticsdomain.ReferrerViews{}
+	}
+
+	counts := make(map[string]int, len(daily))
+	for _, d := range daily {
+		counts[d.Date] = d.Views
+		stats.TotalViews += d.Views
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		stats.Daily = append(stats.Daily, &analyticsdomain.DailyViews{Date: date, Views: counts[date]})
+	}
+	return stats, nil
+}
+
+// visitorHash identifies a visitor for one day only. The key changes with
+// the day, so the same visitor can't be followed from one day to the next.
+func (u *usecase) visitorHash(day time.Time, ip, userAgent string) string {
+	dayKey := hmac.New(sha256.New, u.secret)
+	dayKey.Write([]byte(day.Format(time.DateOnly)))
+
+	mac := hmac.New(sha256.New, dayKey.Sum(nil))
+	mac.Write([]byte(ip))
+	mac.Write([]byte{0})
+	mac.Write([]byte(userAgent))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// referrerHost keeps only the host of the referring page, so no paths or
+// query strings with personal data are stored.
+func referrerHost(referrer string) string {
+	if referrer == "" {
+		return ""
+	}
+	u, err := url.Parse(referrer)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if len(host) > 255 {
+		host = host[:255]
+	}
+	return host
+}
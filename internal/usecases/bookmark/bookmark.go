@@ -0,0 +1,245 @@
+package bookmarkusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	bookmarkdomain "github.com/codepnw/blog-api/internal/domains/bookmark"
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	bookmarkrepo "github.com/codepnw/blog-api/internal/repositories/bookmark"
+	"github.com/codepnw/blog-api/internal/usecases"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+type Usecase interface {
+	// Bookmarks
+	AddBookmark(ctx context.Context, userID, postID string) error
+	RemoveBookmark(ctx context.Context, userID, postID string) error
+	GetBookmarks(ctx context.Context, userID string) ([]*postdomain.Summary, error)
+	// GetBookmarked reports which of the given posts the user bookmarked.
+	GetBookmarked(ctx context.Context, userID string, postIDs []string) (map[string]bool, error)
+
+	// Reading Lists
+	CreateList(ctx context.Context, input *bookmarkdomain.ReadingList) (*bookmarkdomain.ReadingList, error)
+	GetMyLists(ctx context.Context, ownerID string) ([]*bookmarkdomain.ReadingList, error)
+	GetList(ctx context.Context, id, userID string) (*bookmarkdomain.ReadingList, error)
+	GetSharedList(ctx context.Context, token string) (*bookmarkdomain.ReadingList, error)
+	UpdateList(ctx context.Context, id, userID string, input *bookmarkdomain.ReadingListUpdate) (*bookmarkdomain.ReadingList, error)
+	DeleteList(ctx context.Context, id, userID string) error
+	AddToList(ctx context.Context, id, userID, postID string) error
+	RemoveFromList(ctx context.Context, id, userID, postID string) error
+}
+
+type usecase struct {
+	repo   bookmarkrepo.Repository
+	postUc postusecase.Usecase
+}
+
+func NewBookmarkUsecase(repo bookmarkrepo.Repository, postUc postusecase.Usecase) Usecase {
+	return &usecase{repo: repo, postUc: postUc}
+}
+
+func (u *usecase) AddBookmark(ctx context.Context, userID, postID string) error {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if err := u.checkPost(ctx, postID, userID); err != nil {
+		return err
+	}
+	return u.repo.AddBookmark(ctx, userID, postID)
+}
+
+func (u *usecase) RemoveBookmark(ctx context.Context, userID, postID string) error {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return u.repo.RemoveBookmark(ctx, userID, postID)
+}
+
+// GetBookmarks leaves out bookmarked posts that were unpublished or deleted
+// since, without removing the bookmarks.
+func (u *usecase) GetBookmarks(ctx context.Context, userID string) ([]*postdomain.Summary, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	ids, err := u.repo.ListBookmarks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := u.posts(ctx, ids, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range posts {
+		p.Bookmarked = true
+	}
+	return posts, nil
+}
+
+func (u *usecase) GetBookmarked(ctx context.Context, userID string, postIDs []string) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return u.repo.FindBookmarked(ctx, userID, postIDs)
+}
+
+func (u *usecase) CreateList(ctx context.Context, input *bookmarkdomain.ReadingList) (*bookmarkdomain.ReadingList, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	input.ShareToken = token
+
+	return u.repo.InsertList(ctx, input)
+}
+
+func (u *usecase) GetMyLists(ctx context.Context, ownerID string) ([]*bookmarkdomain.ReadingList, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return u.repo.ListByOwner(ctx, ownerID)
+}
+
+func (u *usecase) GetList(ctx context.Context, id, userID string) (*bookmarkdomain.ReadingList, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	list, err := u.ownList(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if list.Posts, err = u.listPosts(ctx, list, userID); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetSharedList returns a public list with its published posts. Private
+// lists are reported as not found.
+func (u *usecase) GetSharedList(ctx context.Context, token string) (*bookmarkdomain.ReadingList, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	list, err := u.repo.FindListByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !list.Public {
+		return nil, errs.ErrReadingListNotFound
+	}
+	if list.Posts, err = u.listPosts(ctx, list, ""); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (u *usecase) UpdateList(ctx context.Context, id, userID string, input *bookmarkdomain.ReadingListUpdate) (*bookmarkdomain.ReadingList, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	list, err := u.ownList(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		list.Name = *input.Name
+	}
+	if input.Description != nil {
+		list.Description = *input.Description
+	}
+	if input.Public != nil {
+		list.Public = *input.Public
+	}
+	return u.repo.UpdateList(ctx, list)
+}
+
+func (u *usecase) DeleteList(ctx context.Context, id, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if _, err := u.ownList(ctx, id, userID); err != nil {
+		return err
+	}
+	return u.repo.DeleteList(ctx, id)
+}
+
+func (u *usecase) AddToList(ctx context.Context, id, userID, postID string) error {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if _, err := u.ownList(ctx, id, userID); err != nil {
+		return err
+	}
+	if err := u.checkPost(ctx, postID, userID); err != nil {
+		return err
+	}
+	return u.repo.AddListItem(ctx, id, postID)
+}
+
+func (u *usecase) RemoveFromList(ctx context.Context, id, userID, postID string) error {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if _, err := u.ownList(ctx, id, userID); err != nil {
+		return err
+	}
+	return u.repo.RemoveListItem(ctx, id, postID)
+}
+
+// ownList loads a list of userID. Other users' lists are reported as not
+// found, so their ids can't be probed.
+func (u *usecase) ownList(ctx context.Context, id, userID string) (*bookmarkdomain.ReadingList, error) {
+	list, err := u.repo.FindListByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if list.OwnerID != userID {
+		return nil, errs.ErrReadingListNotFound
+	}
+	return list, nil
+}
+
+func (u *usecase) listPosts(ctx context.Context, list *bookmarkdomain.ReadingList, viewerID string) ([]*postdomain.Summary, error) {
+	ids, err := u.repo.ListItems(ctx, list.ID)
+	if err != nil {
+		return nil, err
+	}
+	return u.posts(ctx, ids, viewerID)
+}
+
+func (u *usecase) posts(ctx context.Context, ids []string, viewerID string) ([]*postdomain.Summary, error) {
+	posts, err := u.postUc.GetByIDs(ctx, ids, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if posts == nil {
+		posts = []*postdomain.Summary{}
+	}
+	return posts, nil
+}
+
+// checkPost only lets users save posts they can read.
+func (u *usecase) checkPost(ctx context.Context, postID, userID string) error {
+	post, err := u.postUc.GetByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if !postusecase.CanRead(post, userID) {
+		return errs.ErrPostNotFound
+	}
+	return nil
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -12,8 +12,8 @@ type Usecase interface {
 	Create(ctx context.Context, input *categorydomain.Category) error
 	GetByID(ctx context.Context, id string) (*categorydomain.Category, error)
 	GetAll(ctx context.Context) ([]*categorydomain.Category, error)
-	Update(ctx context.Context, input *categorydomain.Category) error
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, input *categorydomain.Category) (*categorydomain.Category, error)
+	Delete(ctx context.Context, id string, version int) error
 }
 
 type usecase struct {
@@ -45,16 +45,16 @@ func (u *usecase) GetAll(ctx context.Context) ([]*categorydomain.Category, error
 	return u.repo.List(ctx)
 }
 
-func (u *usecase) Update(ctx context.Context, input *categorydomain.Category) error {
+func (u *usecase) Update(ctx context.Context, input *categorydomain.Category) (*categorydomain.Category, error) {
 	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
 	defer cancel()
 
 	return u.repo.Update(ctx, input)
 }
 
-func (u *usecase) Delete(ctx context.Context, id string) error {
+func (u *usecase) Delete(ctx context.Context, id string, version int) error {
 	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
 	defer cancel()
 
-	return u.repo.Delete(ctx, id)
+	return u.repo.Delete(ctx, id, version)
 }
@@ -3,6 +3,7 @@ package commentusecase
 import (
 	"context"
 	"strings"
+	"time"
 
 	commentdomain "github.com/codepnw/blog-api/internal/domains/comment"
 	commentrepo "github.com/codepnw/blog-api/internal/repositories/comment"
@@ -17,7 +18,12 @@ type Usecase interface {
 	CreateComment(ctx context.Context, input *commentdomain.Comment) (*commentdomain.Comment, error)
 	GetCommentByPost(ctx context.Context, postID string) ([]*commentdomain.Comment, error)
 	EditComment(ctx context.Context, input *commentdomain.Comment, role string) (*commentdomain.Comment, error)
-	DeleteComment(ctx context.Context, commentID int64, userID, role string) error
+	DeleteComment(ctx context.Context, commentID int64, userID, role string, version int) error
+
+	// Trash
+	GetTrash(ctx context.Context, userID string) ([]*commentdomain.Comment, error)
+	RestoreComment(ctx context.Context, commentID int64, userID, role string) (*commentdomain.Comment, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) error
 }
 
 type usecase struct {
@@ -66,7 +72,7 @@ func (u *usecase) EditComment(ctx context.Context, input *commentdomain.Comment,
 	return u.repo.Update(ctx, input)
 }
 
-func (u *usecase) DeleteComment(ctx context.Context, commentID int64, userID, role string) error {
+func (u *usecase) DeleteComment(ctx context.Context, commentID int64, userID, role string, version int) error {
 	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
 	defer cancel()
 
@@ -74,7 +80,47 @@ func (u *usecase) DeleteComment(ctx context.Context, commentID int64, userID, ro
 		logger.Error("usecase.DeleteComment: validate owner", "id", commentID, "user_id", userID, "role", role, "error", err)
 		return err
 	}
-	return u.repo.Delete(ctx, commentID)
+	return u.repo.Delete(ctx, commentID, version)
+}
+
+// GetTrash lists the trashed comments of userID, or every trashed comment
+// when userID is empty.
+func (u *usecase) GetTrash(ctx context.Context, userID string) ([]*commentdomain.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return u.repo.ListDeleted(ctx, userID)
+}
+
+func (u *usecase) RestoreComment(ctx context.Context, commentID int64, userID, role string) (*commentdomain.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	comment, err := u.repo.FindDeletedByID(ctx, commentID)
+	if err != nil {
+		logger.Error("usecase.RestoreComment: find comment", "id", commentID, "error", err)
+		return nil, err
+	}
+	if comment.UserID != userID && role != string(userusecase.RoleAdmin) {
+		return nil, errs.ErrCommentNotOwner
+	}
+	return u.repo.Restore(ctx, commentID)
+}
+
+// PurgeTrash hard-deletes comments that have been in the trash longer than
+// retention. It is run periodically by the scheduler.
+func (u *usecase) PurgeTrash(ctx context.Context, retention time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	n, err := u.repo.Purge(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		logger.Info("usecase.PurgeTrash: comments purged", "count", n)
+	}
+	return nil
 }
 
 func (u *usecase) validateComment(ctx context.Context, input *commentdomain.Comment) error {
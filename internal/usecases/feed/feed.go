@@ -0,0 +1,83 @@
+package feedusecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/codepnw/blog-api/internal/config"
+	feeddomain "github.com/codepnw/blog-api/internal/domains/feed"
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	categoryusecase "github.com/codepnw/blog-api/internal/usecases/category"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+)
+
+type Usecase interface {
+	// GetFeed fails with errs.ErrCategoryNotFound, errs.ErrUserNotFound or
+	// errs.ErrTagNotFound when the filter names something that doesn't exist.
+	GetFeed(ctx context.Context, filter postdomain.FeedFilter) (*feeddomain.Feed, error)
+}
+
+type usecase struct {
+	postUc     postusecase.Usecase
+	categoryUc categoryusecase.Usecase
+	userUc     userusecase.Usecase
+	cfg        config.FeedConfig
+}
+
+func NewFeedUsecase(postUc postusecase.Usecase, categoryUc categoryusecase.Usecase, userUc userusecase.Usecase, cfg config.FeedConfig) Usecase {
+	return &usecase{
+		postUc:     postUc,
+		categoryUc: categoryUc,
+		userUc:     userUc,
+		cfg:        cfg,
+	}
+}
+
+func (u *usecase) GetFeed(ctx context.Context, filter postdomain.FeedFilter) (*feeddomain.Feed, error) {
+	feed := &feeddomain.Feed{
+		Title:       u.cfg.Title,
+		Description: u.cfg.Description,
+	}
+
+	// Every filter narrows the title, e.g. "Blog: Go" for a category.
+	var scopes []string
+	if filter.CategoryID != "" {
+		category, err := u.categoryUc.GetByID(ctx, filter.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, category.Name)
+	}
+	if filter.AuthorID != "" {
+		user, err := u.userUc.GetUser(ctx, filter.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, strings.TrimSpace(user.FirstName+" "+user.LastName))
+	}
+	if filter.Tag != "" {
+		tag, err := u.postUc.GetTag(ctx, filter.Tag)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tag = tag
+		scopes = append(scopes, "#"+tag)
+	}
+	if len(scopes) > 0 {
+		feed.Title = fmt.Sprintf("%s: %s", feed.Title, strings.Join(scopes, ", "))
+	}
+
+	posts, err := u.postUc.GetPublished(ctx, filter, u.cfg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	feed.Posts = posts
+	for _, post := range posts {
+		if post.UpdatedAt.After(feed.Updated) {
+			feed.Updated = post.UpdatedAt
+		}
+	}
+	return feed, nil
+}
@@ -0,0 +1,247 @@
+package mediausecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/codepnw/blog-api/internal/config"
+	mediadomain "github.com/codepnw/blog-api/internal/domains/media"
+	mediarepo "github.com/codepnw/blog-api/internal/repositories/media"
+	"github.com/codepnw/blog-api/internal/storage"
+	"github.com/codepnw/blog-api/internal/usecases"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/images"
+	"github.com/codepnw/blog-api/internal/utils/logger"
+	"github.com/google/uuid"
+)
+
+// Resizing and storing every variant takes longer than a plain query.
+const uploadTimeout = time.Second * 30
+
+type Usecase interface {
+	Upload(ctx context.Context, uploaderID, filename string, data []byte) (*mediadomain.Media, error)
+	GetByID(ctx context.Context, id string) (*mediadomain.Media, error)
+	GetByUploader(ctx context.Context, uploaderID string) ([]*mediadomain.Media, error)
+	Delete(ctx context.Context, id, userID, role string) error
+}
+
+type usecase struct {
+	repo  mediarepo.Repository
+	store storage.Storage
+	webp  *images.WebPEncoder
+	cfg   config.MediaConfig
+}
+
+func NewMediaUsecase(repo mediarepo.Repository, store storage.Storage, cfg config.MediaConfig) Usecase {
+	webp := images.NewWebPEncoder(cfg.CWebP)
+	if webp == nil {
+		logger.Warn("mediausecase: cwebp not found, WebP variants are disabled", "cwebp", cfg.CWebP)
+	}
+	return &usecase{repo: repo, store: store, webp: webp, cfg: cfg}
+}
+
+// Upload validates the image by its content, stores it with its variants and
+// records it for uploaderID.
+func (u *usecase) Upload(ctx context.Context, uploaderID, filename string, data []byte) (*mediadomain.Media, error) {
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	if int64(len(data)) > u.cfg.MaxSize {
+		return nil, errs.ErrMediaTooLarge
+	}
+
+	mime, ok := images.Sniff(data)
+	if !ok {
+		return nil, errs.ErrMediaUnsupportedType
+	}
+
+	// Check the dimensions before decoding, a small file can still expand
+	// into gigabytes of pixels.
+	imgCfg, err := images.DecodeConfig(data)
+	if err != nil {
+		return nil, errs.ErrMediaUnsupportedType
+	}
+	if imgCfg.Width*imgCfg.Height > u.cfg.MaxPixels {
+		return nil, errs.ErrMediaTooLarge
+	}
+
+	id := uuid.NewString()
+	prefix := fmt.Sprintf("media/%s/%s", time.Now().UTC().Format("2006/01"), id)
+	media := &mediadomain.Media{
+		ID:         id,
+		UploaderID: uploaderID,
+		StorageKey: fmt.Sprintf("%s/original.%s", prefix, images.Extension(mime)),
+		Filename:   filepath.Base(filename),
+		MimeType:   mime,
+		Size:       int64(len(data)),
+		Width:      imgCfg.Width,
+		Height:     imgCfg.Height,
+	}
+
+	var stored []string
+	cleanup := func() {
+		for _, key := range stored {
+			if err := u.store.Delete(context.Background(), key); err != nil {
+				logger.Error("usecase.Upload: cleanup", "key", key, "error", err)
+			}
+		}
+	}
+
+	if err := u.store.Put(ctx, media.StorageKey, bytes.NewReader(data), media.Size, mime); err != nil {
+		return nil, err
+	}
+	stored = append(stored, media.StorageKey)
+
+	variants, err := u.makeVariants(ctx, prefix, data, mime)
+	for _, v := range variants {
+		stored = append(stored, v.StorageKey)
+	}
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	media.Variants = variants
+
+	result, err := u.repo.Insert(ctx, media)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	return u.withURLs(result), nil
+}
+
+func (u *usecase) GetByID(ctx context.Context, id string) (*mediadomain.Media, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	media, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.withURLs(media), nil
+}
+
+func (u *usecase) GetByUploader(ctx context.Context, uploaderID string) ([]*mediadomain.Media, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	media, err := u.repo.ListByUploader(ctx, uploaderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range media {
+		u.withURLs(m)
+	}
+	return media, nil
+}
+
+// Delete removes the record and then its files. Files that fail to delete
+// are logged and left behind rather than failing the request.
+func (u *usecase) Delete(ctx context.Context, id, userID, role string) error {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	media, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if media.UploaderID != userID && role != string(userusecase.RoleAdmin) {
+		return errs.ErrMediaNotOwner
+	}
+
+	if err := u.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	keys := []string{media.StorageKey}
+	for _, v := range media.Variants {
+		keys = append(keys, v.StorageKey)
+	}
+	for _, key := range keys {
+		if err := u.store.Delete(ctx, key); err != nil {
+			logger.Error("usecase.DeleteMedia: delete file", "key", key, "error", err)
+		}
+	}
+	return nil
+}
+
+// makeVariants stores a resized copy for every configured width smaller than
+// the original, plus WebP versions of those and of the original when cwebp
+// is available. It returns the variants stored so far even on error.
+func (u *usecase) makeVariants(ctx context.Context, prefix string, data []byte, mime string) ([]mediadomain.Variant, error) {
+	img, err := images.Decode(data)
+	if err != nil {
+		return nil, errs.ErrMediaUnsupportedType
+	}
+
+	var variants []mediadomain.Variant
+	put := func(name, contentType string, body []byte, width, height int) error {
+		key := fmt.Sprintf("%s/%s.%s", prefix, name, images.Extension(contentType))
+		if err := u.store.Put(ctx, key, bytes.NewReader(body), int64(len(body)), contentType); err != nil {
+			return err
+		}
+		variants = append(variants, mediadomain.Variant{
+			Name:       name,
+			StorageKey: key,
+			MimeType:   contentType,
+			Size:       int64(len(body)),
+			Width:      width,
+			Height:     height,
+		})
+		return nil
+	}
+
+	if u.webp != nil && mime != images.MimeWebP {
+		out, err := u.webp.Encode(ctx, img)
+		if err != nil {
+			return variants, err
+		}
+		b := img.Bounds()
+		if err := put("original", images.MimeWebP, out, b.Dx(), b.Dy()); err != nil {
+			return variants, err
+		}
+	}
+
+	variantType := images.VariantType(mime)
+	for _, width := range u.cfg.VariantWidths {
+		if width <= 0 || width >= img.Bounds().Dx() {
+			continue
+		}
+
+		resized := images.Resize(img, width)
+		b := resized.Bounds()
+		name := fmt.Sprintf("w%d", width)
+
+		var buf bytes.Buffer
+		if err := images.Encode(&buf, resized, variantType); err != nil {
+			return variants, err
+		}
+		if err := put(name, variantType, buf.Bytes(), b.Dx(), b.Dy()); err != nil {
+			return variants, err
+		}
+
+		if u.webp == nil {
+			continue
+		}
+		out, err := u.webp.Encode(ctx, resized)
+		if err != nil {
+			return variants, err
+		}
+		if err := put(name, images.MimeWebP, out, b.Dx(), b.Dy()); err != nil {
+			return variants, err
+		}
+	}
+	return variants, nil
+}
+
+func (u *usecase) withURLs(media *mediadomain.Media) *mediadomain.Media {
+	media.URL = u.store.URL(media.StorageKey)
+	for i := range media.Variants {
+		media.Variants[i].URL = u.store.URL(media.Variants[i].StorageKey)
+	}
+	return media
+}
@@ -0,0 +1,56 @@
+package postusecase
+
+import (
+	"context"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+type bylineRole string
+
+const (
+	BylineAuthor      bylineRole = "author"
+	BylineContributor bylineRole = "contributor"
+	BylineEditor      bylineRole = "editor"
+)
+
+// IsAuthor reports whether userID owns or co-authors the post. Every
+// co-author may edit it.
+func IsAuthor(post *postdomain.Post, userID string) bool {
+	if userID == "" {
+		return false
+	}
+	if post.AuthorID == userID {
+		return true
+	}
+	for _, byline := range post.Authors {
+		if byline.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAuthors replaces the bylines of a post. The owner must stay among them.
+func (u *usecase) SetAuthors(ctx context.Context, id string, authors []postdomain.Byline, version int, editorID string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	post, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkLock(ctx, post.ID, editorID); err != nil {
+		return nil, err
+	}
+
+	hasOwner := false
+	for _, byline := range authors {
+		hasOwner = hasOwner || byline.UserID == post.AuthorID
+	}
+	if !hasOwner {
+		return nil, errs.ErrPostOwnerRequired
+	}
+	return u.repo.SetAuthors(ctx, id, authors, version)
+}
@@ -0,0 +1,111 @@
+package postusecase
+
+import (
+	"context"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/logger"
+)
+
+// autosaveNote describes applied autosaves in the revision history when
+// the editor gives no note.
+const autosaveNote = "Applied autosave"
+
+// SaveAutosave stores the unsaved edits of a user without touching the
+// post. A non-zero BaseVersion, from If-Match, sets the version the edits
+// started from. Otherwise a new autosave starts from the current version
+// and an existing one keeps its own.
+func (u *usecase) SaveAutosave(ctx context.Context, input *postdomain.Autosave) (*postdomain.Autosave, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	post, err := u.repo.FindByID(ctx, input.PostID)
+	if err != nil {
+		return nil, err
+	}
+	rebase := input.BaseVersion != 0
+	if !rebase {
+		input.BaseVersion = post.Version
+	}
+
+	result, err := u.repo.SaveAutosave(ctx, input, rebase)
+	if err != nil {
+		logger.Error("usecase.SaveAutosave", "post_id", input.PostID, "error", err)
+		return nil, err
+	}
+	result.Outdated = result.BaseVersion != post.Version
+	return result, nil
+}
+
+func (u *usecase) GetAutosave(ctx context.Context, postID, userID string) (*postdomain.Autosave, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	post, err := u.repo.FindByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	autosave, err := u.repo.FindAutosave(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	autosave.Outdated = autosave.BaseVersion != post.Version
+	return autosave, nil
+}
+
+func (u *usecase) DiscardAutosave(ctx context.Context, postID, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.DeleteAutosave(ctx, postID, userID)
+}
+
+// ApplyAutosave saves the autosave of a user into the post as a regular
+// edit, then drops it. It fails with errs.ErrVersionConflict when the post
+// changed since the autosave started, leaving the autosave in place.
+func (u *usecase) ApplyAutosave(ctx context.Context, postID, userID, role, note string) (*postdomain.Post, error) {
+	autosave, err := u.GetAutosave(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &postdomain.Post{ID: postID, Version: autosave.BaseVersion}
+	if autosave.Title != nil {
+		input.Title = *autosave.Title
+	}
+	if autosave.Content != nil {
+		input.Content = *autosave.Content
+	}
+	if autosave.ContentFormat != nil {
+		input.ContentFormat = *autosave.ContentFormat
+	}
+	if note == "" {
+		note = autosaveNote
+	}
+
+	post, err := u.Update(ctx, input, userID, role, note)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.DiscardAutosave(ctx, postID, userID); err != nil {
+		// The edit is in; a leftover autosave only shows up as outdated.
+		logger.Error("usecase.ApplyAutosave: discard", "post_id", postID, "error", err)
+	}
+	return post, nil
+}
+
+// PurgeAutosaves drops the autosaves not saved again within retention.
+func (u *usecase) PurgeAutosaves(ctx context.Context, retention time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	n, err := u.repo.PurgeAutosaves(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		logger.Info("usecase.PurgeAutosaves: autosaves purged", "count", n)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package postusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestAutosaveKeepsBaseVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo(newPost(StatusDraft))
+	uc := newTestUsecase(repo)
+
+	first, err := uc.SaveAutosave(ctx, &postdomain.Autosave{PostID: postID, UserID: authorID, Content: ptr("First try.")})
+	if err != nil {
+		t.Fatalf("first autosave: %v", err)
+	}
+
+	// Someone else saves the post meanwhile.
+	if _, err := uc.Update(ctx, &postdomain.Post{ID: postID, Title: "Their title"}, adminID, roleAdmin, ""); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	second, err := uc.SaveAutosave(ctx, &postdomain.Autosave{PostID: postID, UserID: authorID, Content: ptr("Second try.")})
+	if err != nil {
+		t.Fatalf("second autosave: %v", err)
+	}
+	if second.BaseVersion != first.BaseVersion {
+		t.Errorf("base version = %d, want %d", second.BaseVersion, first.BaseVersion)
+	}
+	if !second.Outdated {
+		t.Error("autosave not reported outdated")
+	}
+
+	_, err = uc.ApplyAutosave(ctx, postID, authorID, roleUser, "")
+	if !errors.Is(err, errs.ErrVersionConflict) {
+		t.Fatalf("apply err = %v, want %v", err, errs.ErrVersionConflict)
+	}
+	if post, _ := repo.FindByID(ctx, postID); post.Title != "Their title" || post.Content != "Short draft." {
+		t.Errorf("post was overwritten: %q %q", post.Title, post.Content)
+	}
+	if _, err := repo.FindAutosave(ctx, postID, authorID); err != nil {
+		t.Errorf("autosave dropped after conflict: %v", err)
+	}
+}
+
+func TestAutosaveRebaseAndApply(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo(newPost(StatusDraft))
+	uc := newTestUsecase(repo)
+
+	if _, err := uc.SaveAutosave(ctx, &postdomain.Autosave{PostID: postID, UserID: authorID, Content: ptr("First try.")}); err != nil {
+		t.Fatalf("autosave: %v", err)
+	}
+	updated, err := uc.Update(ctx, &postdomain.Post{ID: postID, Title: "Their title"}, adminID, roleAdmin, "")
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// The editor merged their change and says so with If-Match.
+	input := &postdomain.Autosave{PostID: postID, UserID: authorID, Content: ptr("Merged."), BaseVersion: updated.Version}
+	if _, err := uc.SaveAutosave(ctx, input); err != nil {
+		t.Fatalf("rebased autosave: %v", err)
+	}
+
+	post, err := uc.ApplyAutosave(ctx, postID, authorID, roleUser, "")
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if post.Title != "Their title" || post.Content != "Merged." {
+		t.Errorf("post = %q %q", post.Title, post.Content)
+	}
+	if _, err := repo.FindAutosave(ctx, postID, authorID); !errors.Is(err, errs.ErrAutosaveNotFound) {
+		t.Errorf("autosave kept after apply: %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+package postusecase
+
+import (
+	"context"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+)
+
+func (u *usecase) GetPublished(ctx context.Context, filter postdomain.FeedFilter, limit int) ([]*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.ListPublished(ctx, filter, limit)
+}
+
+func (u *usecase) GetTag(ctx context.Context, name string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.FindTag(ctx, name)
+}
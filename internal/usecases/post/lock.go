@@ -0,0 +1,78 @@
+package postusecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/logger"
+)
+
+const (
+	// DefaultLockTTL is the lease of an editor that doesn't ask for one.
+	// Editors renew it while they keep the post open.
+	DefaultLockTTL = 2 * time.Minute
+	MaxLockTTL     = 15 * time.Minute
+)
+
+// Lock takes the edit lease of a post for ttl, or renews it when userID
+// already holds it.
+func (u *usecase) Lock(ctx context.Context, postID, userID string, ttl time.Duration) (*postdomain.Lock, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if _, err := u.repo.FindByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+	return u.repo.AcquireLock(ctx, postID, userID, min(ttl, MaxLockTTL))
+}
+
+// GetLock returns the lease of a post, or errs.ErrLockNotFound when no one
+// holds it.
+func (u *usecase) GetLock(ctx context.Context, postID string) (*postdomain.Lock, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.FindLock(ctx, postID)
+}
+
+// Unlock releases the lease of a post. Admins can break the lease of
+// someone else.
+func (u *usecase) Unlock(ctx context.Context, postID, userID, role string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	lock, err := u.repo.FindLock(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if lock.UserID != userID {
+		if role != string(userusecase.RoleAdmin) {
+			return errs.ErrLockNotHeld
+		}
+		logger.Info("usecase.Unlock: lock broken", "post_id", postID, "holder", lock.UserID, "admin", userID)
+	}
+	return u.repo.DeleteLock(ctx, postID)
+}
+
+// checkLock fails with errs.ErrPostLocked while someone other than userID
+// holds the lease of a post.
+func (u *usecase) checkLock(ctx context.Context, postID, userID string) error {
+	lock, err := u.repo.FindLock(ctx, postID)
+	if err != nil {
+		if errors.Is(err, errs.ErrLockNotFound) {
+			return nil
+		}
+		return err
+	}
+	if lock.UserID != userID {
+		return errs.ErrPostLocked
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package postusecase
+
+import (
+	"context"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+// Pin features a post site-wide or in a category. Pinning a post again
+// moves it and replaces its expiry.
+func (u *usecase) Pin(ctx context.Context, input *postdomain.Pin) (*postdomain.Pin, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, errs.ErrPinExpiryInPast
+	}
+	if _, err := u.repo.FindByID(ctx, input.PostID); err != nil {
+		return nil, err
+	}
+	return u.repo.UpsertPin(ctx, input)
+}
+
+func (u *usecase) Unpin(ctx context.Context, postID, categoryID string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.DeletePin(ctx, postID, categoryID)
+}
+
+// GetFeatured returns the pinned posts of a category, or of the homepage when
+// categoryID is empty, in pin order. Pinned posts that aren't published, or
+// are unlisted or private, are left out until they are listed.
+func (u *usecase) GetFeatured(ctx context.Context, categoryID string) ([]*postdomain.Summary, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	ids, err := u.pinnedIDs(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := u.repo.FindByIDs(ctx, ids, "")
+	if err != nil {
+		return nil, err
+	}
+	posts = listed(posts)
+	for _, p := range posts {
+		p.Pinned = true
+	}
+	return posts, nil
+}
+
+// pinFirst moves the site-wide pinned posts of a listing to the top, in pin
+// order, keeping the order of the rest.
+func (u *usecase) pinFirst(ctx context.Context, posts []*postdomain.Summary) ([]*postdomain.Summary, error) {
+	ids, err := u.pinnedIDs(ctx, "")
+	if err != nil || len(ids) == 0 {
+		return posts, err
+	}
+
+	rank := make(map[string]int, len(ids))
+	for i, id := range ids {
+		rank[id] = i
+	}
+	pinned := make([]*postdomain.Summary, len(ids))
+	rest := make([]*postdomain.Summary, 0, len(posts))
+	for _, p := range posts {
+		if i, ok := rank[p.ID]; ok {
+			p.Pinned = true
+			pinned[i] = p
+		} else {
+			rest = append(rest, p)
+		}
+	}
+
+	result := make([]*postdomain.Summary, 0, len(posts))
+	for _, p := range pinned {
+		if p != nil {
+			result = append(result, p)
+		}
+	}
+	return append(result, rest...), nil
+}
+
+func (u *usecase) pinnedIDs(ctx context.Context, categoryID string) ([]string, error) {
+	pins, err := u.repo.ListPins(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(pins))
+	for _, pin := range pins {
+		ids = append(ids, pin.PostID)
+	}
+	return ids, nil
+}
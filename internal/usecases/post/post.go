@@ -2,36 +2,168 @@ package postusecase
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/notify"
 	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
 	"github.com/codepnw/blog-api/internal/utils/logger"
+	"github.com/codepnw/blog-api/internal/utils/render"
+	"github.com/codepnw/blog-api/internal/utils/textstat"
 )
 
-const contextTimeout = time.Second * 5
+const (
+	contextTimeout  = time.Second * 5
+	renderBatchSize = 100
+)
+
+type postStatus string
+
+const (
+	StatusDraft     postStatus = "draft"
+	StatusScheduled postStatus = "scheduled"
+	StatusPublished postStatus = "published"
+	StatusArchived  postStatus = "archived"
+
+	// Editorial review, see reviewTransitions.
+	StatusInReview         postStatus = "in_review"
+	StatusApproved         postStatus = "approved"
+	StatusChangesRequested postStatus = "changes_requested"
+)
 
 type Usecase interface {
-	Create(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error)
+	// Create saves a new post by a user with role. Only reviewers can
+	// create it published or scheduled, and only admins can give it a cover
+	// image uploaded by someone else.
+	Create(ctx context.Context, input *postdomain.Post, role string) (*postdomain.Post, error)
 	GetByID(ctx context.Context, id string) (*postdomain.Post, error)
-	GetByAuthorID(ctx context.Context, authorID string) ([]*postdomain.Post, error)
-	GetAll(ctx context.Context) ([]*postdomain.Post, error)
-	Update(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error)
-	Delete(ctx context.Context, id string) error
+	GetByAuthorID(ctx context.Context, authorID string, publishedOnly bool, locale string) ([]*postdomain.Summary, error)
+	// GetAll lists the posts the filter matches, newest first, or with the
+	// site-wide pinned posts on top when pinnedFirst is set.
+	GetAll(ctx context.Context, filter postdomain.ListFilter, pinnedFirst bool) ([]*postdomain.Summary, error)
+	GetByIDs(ctx context.Context, ids []string, viewerID string) ([]*postdomain.Summary, error)
+	// Update edits the post. Title or content changes by anyone but a
+	// reviewer take a submitted, approved, scheduled or published post back
+	// to draft, to be reviewed again.
+	Update(ctx context.Context, input *postdomain.Post, editorID, role, note string) (*postdomain.Post, error)
+	Delete(ctx context.Context, id string, version int) error
+
+	// Lifecycle, see lifecycleTransitions. A non-zero version must match the
+	// current one.
+	// Publish publishes the post on behalf of a user with role, who must be
+	// a reviewer unless the post was approved.
+	Publish(ctx context.Context, id string, at *time.Time, version int, userID, role string) (*postdomain.Post, error)
+	Unpublish(ctx context.Context, id string, version int, userID string) (*postdomain.Post, error)
+	Archive(ctx context.Context, id string, version int, userID string) (*postdomain.Post, error)
+	PublishScheduled(ctx context.Context) error
+	RenderPending(ctx context.Context) error
+
+	// Import and export
+	Import(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error)
+	GetIDBySlug(ctx context.Context, slug string) (string, error)
+	GetAllPosts(ctx context.Context) ([]*postdomain.Post, error)
+
+	// Feeds
+	GetPublished(ctx context.Context, filter postdomain.FeedFilter, limit int) ([]*postdomain.Post, error)
+	GetTag(ctx context.Context, name string) (string, error)
+
+	// Review
+	// Submit sends a draft, or a post that changes were requested on, to
+	// the reviewers.
+	Submit(ctx context.Context, id, userID, comment string) (*postdomain.Post, error)
+	Approve(ctx context.Context, id, userID, role, comment string) (*postdomain.Post, error)
+	RequestChanges(ctx context.Context, id, userID, role, comment string) (*postdomain.Post, error)
+	GetReviews(ctx context.Context, postID string) ([]*postdomain.Review, error)
+
+	// Locks
+	Lock(ctx context.Context, postID, userID string, ttl time.Duration) (*postdomain.Lock, error)
+	GetLock(ctx context.Context, postID string) (*postdomain.Lock, error)
+	Unlock(ctx context.Context, postID, userID, role string) error
+
+	// Autosaves
+	SaveAutosave(ctx context.Context, input *postdomain.Autosave) (*postdomain.Autosave, error)
+	GetAutosave(ctx context.Context, postID, userID string) (*postdomain.Autosave, error)
+	DiscardAutosave(ctx context.Context, postID, userID string) error
+	ApplyAutosave(ctx context.Context, postID, userID, role, note string) (*postdomain.Post, error)
+	PurgeAutosaves(ctx context.Context, retention time.Duration) error
+
+	// Pins
+	Pin(ctx context.Context, input *postdomain.Pin) (*postdomain.Pin, error)
+	Unpin(ctx context.Context, postID, categoryID string) error
+	GetFeatured(ctx context.Context, categoryID string) ([]*postdomain.Summary, error)
+
+	// Translations
+	// SetTranslation and DeleteTranslation change what readers see, so by
+	// anyone but a reviewer they take the post back to draft as Update does.
+	SetTranslation(ctx context.Context, input *postdomain.Translation, version int, editorID, role string) (*postdomain.Post, error)
+	DeleteTranslation(ctx context.Context, postID, locale string, version int, editorID, role string) (*postdomain.Post, error)
+	GetTranslations(ctx context.Context, postID string) ([]*postdomain.Translation, error)
+	// Localize serves the post in the first locale of chain it is written
+	// in or translated into, leaving it as is when there is none.
+	Localize(ctx context.Context, post *postdomain.Post, chain []string) error
+	// ResolveSlug returns the post a slug belongs to, either its own or
+	// that of a translation, and the locale the slug is in. chain picks
+	// between translations sharing the slug.
+	ResolveSlug(ctx context.Context, slug string, chain []string) (id, locale string, err error)
+	LocalizeSummaries(ctx context.Context, posts []*postdomain.Summary, chain []string) error
+
+	// Related
+	GetRelated(ctx context.Context, id string, limit int) ([]*postdomain.Summary, error)
+	RefreshRelated(ctx context.Context) error
+
+	// Trash
+	GetDeletedByID(ctx context.Context, id string) (*postdomain.Post, error)
+	GetTrash(ctx context.Context, authorID string) ([]*postdomain.Post, error)
+	Restore(ctx context.Context, id string) (*postdomain.Post, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) error
+
+	// Authors
+	SetAuthors(ctx context.Context, id string, authors []postdomain.Byline, version int, editorID string) (*postdomain.Post, error)
+
+	// Revisions
+	GetRevisions(ctx context.Context, postID string) ([]*postdomain.Revision, error)
+	GetRevision(ctx context.Context, postID string, revision int) (*postdomain.Revision, error)
+	DiffRevisions(ctx context.Context, postID string, from, to int, mode string) (*RevisionDiff, error)
+	RestoreRevision(ctx context.Context, postID string, revision, version int, editorID, role string) (*postdomain.Post, error)
 }
 
 type usecase struct {
-	repo postrepo.Repository
+	repo     postrepo.Repository
+	notifier notify.Notifier
 }
 
-func NewPostUsecase(repo postrepo.Repository) Usecase {
-	return &usecase{repo: repo}
+func NewPostUsecase(repo postrepo.Repository, notifier notify.Notifier) Usecase {
+	return &usecase{repo: repo, notifier: notifier}
 }
 
-func (u *usecase) Create(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error) {
+func (u *usecase) Create(ctx context.Context, input *postdomain.Post, role string) (*postdomain.Post, error) {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
+	if err := u.prepareStatus(input); err != nil {
+		return nil, err
+	}
+	if input.Status != string(StatusDraft) && !IsReviewer(role) {
+		return nil, errs.ErrReviewRequired
+	}
+	if input.ContentFormat == "" {
+		input.ContentFormat = render.FormatMarkdown
+	}
+	if input.Locale == "" {
+		input.Locale = DefaultLocale
+	}
+	if input.Visibility == "" {
+		input.Visibility = string(VisibilityPublic)
+	}
+	if err := u.checkCoverMedia(ctx, input, input.CoverMediaID, role); err != nil {
+		return nil, err
+	}
+	if err := u.renderContent(input); err != nil {
+		return nil, err
+	}
 	return u.repo.Insert(ctx, input)
 }
 
@@ -42,35 +174,233 @@ func (u *usecase) GetByID(ctx context.Context, id string) (*postdomain.Post, err
 	return u.repo.FindByID(ctx, id)
 }
 
-func (u *usecase) GetByAuthorID(ctx context.Context, authorID string) ([]*postdomain.Post, error) {
+func (u *usecase) GetByAuthorID(ctx context.Context, authorID string, publishedOnly bool, locale string) ([]*postdomain.Summary, error) {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
-	return u.repo.FindByAuthorID(ctx, authorID)
+	return u.repo.FindByAuthorID(ctx, authorID, publishedOnly, locale)
 }
 
-func (u *usecase) GetAll(ctx context.Context) ([]*postdomain.Post, error) {
+func (u *usecase) GetAll(ctx context.Context, filter postdomain.ListFilter, pinnedFirst bool) ([]*postdomain.Summary, error) {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
-	return u.repo.List(ctx)
+	posts, err := u.repo.List(ctx, filter)
+	if err != nil || !pinnedFirst {
+		return posts, err
+	}
+	return u.pinFirst(ctx, posts)
 }
 
-func (u *usecase) Update(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error) {
+func (u *usecase) GetByIDs(ctx context.Context, ids []string, viewerID string) ([]*postdomain.Summary, error) {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
-	_, err := u.repo.FindByID(ctx, input.ID)
+	return u.repo.FindByIDs(ctx, ids, viewerID)
+}
+
+func (u *usecase) Update(ctx context.Context, input *postdomain.Post, editorID, role, note string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	post, err := u.repo.FindByID(ctx, input.ID)
 	if err != nil {
 		logger.Error("usecase.UpdatePost: find post", "id", input.ID, "error", err)
 		return nil, err
 	}
-	return u.repo.Update(ctx, input)
+	if err := u.checkLock(ctx, post.ID, editorID); err != nil {
+		return nil, err
+	}
+	if err := u.checkCoverMedia(ctx, post, input.CoverMediaID, role); err != nil {
+		return nil, err
+	}
+
+	// Re-render when either the source or its format changes.
+	if input.Content != "" || input.ContentFormat != "" {
+		if input.Content == "" {
+			input.Content = post.Content
+		}
+		if input.ContentFormat == "" {
+			input.ContentFormat = post.ContentFormat
+		}
+		if err := u.renderContent(input); err != nil {
+			return nil, err
+		}
+	}
+	return u.repo.Update(ctx, input, editorID, note, withdrawsReview(input, role))
+}
+
+// checkCoverMedia lets a post take as its cover an image uploaded by one of
+// its authors. Admins can use any image.
+func (u *usecase) checkCoverMedia(ctx context.Context, post *postdomain.Post, mediaID *string, role string) error {
+	if mediaID == nil || *mediaID == "" || role == string(userusecase.RoleAdmin) {
+		return nil
+	}
+	uploaderID, err := u.repo.FindMediaUploaderID(ctx, *mediaID)
+	if err != nil {
+		return err
+	}
+	if !IsAuthor(post, uploaderID) {
+		return errs.ErrMediaNotOwner
+	}
+	return nil
 }
 
-func (u *usecase) Delete(ctx context.Context, id string) error {
+// Delete trashes the post. A non-zero version must match the current one.
+func (u *usecase) Delete(ctx context.Context, id string, version int) error {
 	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
 	defer cancel()
 
-	return u.repo.Delete(ctx, id)
+	return u.repo.Delete(ctx, id, version)
+}
+
+// ------- Lifecycle -----------
+
+// lifecycleTransitions are the steps of Publish, Unpublish and Archive.
+// Going back to draft is also how an archived post comes back.
+var lifecycleTransitions = map[reviewAction]transition{
+	ReviewPublished: {
+		from: []postStatus{StatusDraft, StatusInReview, StatusApproved, StatusChangesRequested, StatusScheduled, StatusArchived},
+		to:   StatusPublished,
+	},
+	ReviewScheduled: {
+		from: []postStatus{StatusDraft, StatusInReview, StatusApproved, StatusChangesRequested, StatusScheduled, StatusPublished},
+		to:   StatusScheduled,
+	},
+	ReviewUnpublished: {from: []postStatus{StatusScheduled, StatusPublished, StatusArchived}, to: StatusDraft},
+	ReviewArchived:    {from: []postStatus{StatusPublished}, to: StatusArchived},
+}
+
+// Publish makes the post public now, or schedules it when at is in the future.
+func (u *usecase) Publish(ctx context.Context, id string, at *time.Time, version int, userID, role string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	post, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		logger.Error("usecase.Publish: find post", "id", id, "error", err)
+		return nil, err
+	}
+	if post.Status == string(StatusPublished) && at == nil && (version == 0 || version == post.Version) {
+		return post, nil
+	}
+	if !canPublish(post.Status, role) {
+		return nil, errs.ErrReviewRequired
+	}
+
+	input := &postdomain.Post{Status: string(StatusPublished), PublishedAt: at}
+	action := ReviewPublished
+	if at != nil {
+		input.Status = string(StatusScheduled)
+		action = ReviewScheduled
+	}
+	if err := u.prepareStatus(input); err != nil {
+		return nil, err
+	}
+
+	// The status checked above may be gone by now; contributors can only
+	// take the step from what canPublish allows.
+	t := lifecycleTransitions[action]
+	t.from = slices.DeleteFunc(slices.Clone(t.from), func(s postStatus) bool {
+		return !canPublish(string(s), role)
+	})
+	review := &postdomain.Review{PostID: id, ActorID: &userID, Action: string(action)}
+	return u.transition(ctx, review, t, input.PublishedAt, version)
+}
+
+// Unpublish takes the post off the site, or off the schedule, back to draft.
+func (u *usecase) Unpublish(ctx context.Context, id string, version int, userID string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	review := &postdomain.Review{PostID: id, ActorID: &userID, Action: string(ReviewUnpublished)}
+	return u.transition(ctx, review, lifecycleTransitions[ReviewUnpublished], nil, version)
+}
+
+// Archive retires a published post, keeping its publication date.
+func (u *usecase) Archive(ctx context.Context, id string, version int, userID string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	review := &postdomain.Review{PostID: id, ActorID: &userID, Action: string(ReviewArchived)}
+	return u.transition(ctx, review, lifecycleTransitions[ReviewArchived], nil, version)
+}
+
+// PublishScheduled is run periodically by the scheduler.
+func (u *usecase) PublishScheduled(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	ids, err := u.repo.PublishScheduled(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		logger.Info("usecase.PublishScheduled: post published", "id", id)
+	}
+	return nil
+}
+
+// RenderPending fills the HTML cache of posts that don't have one yet. It is
+// run periodically by the scheduler; rendering is deterministic, so two
+// instances picking the same post only do redundant work.
+func (u *usecase) RenderPending(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	posts, err := u.repo.ListUnrendered(ctx, renderBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		if err := u.renderContent(post); err != nil {
+			logger.Error("usecase.RenderPending: render", "id", post.ID, "error", err)
+			continue
+		}
+		if err := u.repo.UpdateRendered(ctx, post); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderContent caches the sanitized HTML, table of contents, excerpt and
+// reading stats on input.
+func (u *usecase) renderContent(input *postdomain.Post) error {
+	result, err := render.Render(input.ContentFormat, input.Content)
+	if err != nil {
+		return errs.ErrPostInvalidFormat
+	}
+
+	stats := textstat.FromHTML(result.HTML)
+	input.Excerpt = stats.Excerpt
+	input.WordCount = stats.WordCount
+	input.ReadingTime = stats.ReadingMinutes
+
+	input.ContentHTML = result.HTML
+	input.TOC = make([]postdomain.Heading, 0, len(result.TOC))
+	for _, h := range result.TOC {
+		input.TOC = append(input.TOC, postdomain.Heading{Level: h.Level, ID: h.ID, Text: h.Text})
+	}
+	return nil
+}
+
+// prepareStatus defaults the status to draft and fills published_at so that
+// it is consistent with the status.
+func (u *usecase) prepareStatus(input *postdomain.Post) error {
+	switch postStatus(input.Status) {
+	case "", StatusDraft:
+		input.Status = string(StatusDraft)
+		input.PublishedAt = nil
+	case StatusPublished:
+		now := time.Now()
+		input.PublishedAt = &now
+	case StatusScheduled:
+		if input.PublishedAt == nil || !input.PublishedAt.After(time.Now()) {
+			return errs.ErrPostScheduleInPast
+		}
+	default:
+		return errs.ErrPostInvalidStatus
+	}
+	return nil
 }
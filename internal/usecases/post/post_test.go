@@ -0,0 +1,143 @@
+package postusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+func TestCoverMediaOwnership(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		ownCover    = "media-author"
+		coAuthCover = "media-coauthor"
+		otherCover  = "media-other"
+		coAuthorID  = "coauthor-1"
+	)
+	newRepo := func() *fakeRepo {
+		post := newPost(StatusDraft)
+		post.Authors = []postdomain.Byline{{UserID: authorID}, {UserID: coAuthorID}}
+		repo := newFakeRepo(post)
+		repo.uploaders[ownCover] = authorID
+		repo.uploaders[coAuthCover] = coAuthorID
+		repo.uploaders[otherCover] = "someone-else"
+		return repo
+	}
+
+	tests := []struct {
+		name    string
+		mediaID string
+		role    string
+		wantErr error
+	}{
+		{"own upload", ownCover, roleUser, nil},
+		{"upload of a co-author", coAuthCover, roleUser, nil},
+		{"upload of someone else", otherCover, roleUser, errs.ErrMediaNotOwner},
+		{"editor uses someone else's upload", otherCover, roleEditor, errs.ErrMediaNotOwner},
+		{"admin uses someone else's upload", otherCover, roleAdmin, nil},
+		{"missing media", "media-missing", roleUser, errs.ErrMediaNotFound},
+		{"no cover", "", roleUser, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mediaID := tt.mediaID
+
+			t.Run("update", func(t *testing.T) {
+				repo := newRepo()
+				input := &postdomain.Post{ID: postID, CoverMediaID: &mediaID}
+				_, err := newTestUsecase(repo).Update(ctx, input, authorID, tt.role, "")
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				post, _ := repo.FindByID(ctx, postID)
+				if saved := post.CoverMediaID != nil && *post.CoverMediaID == mediaID; saved != (tt.wantErr == nil) {
+					t.Errorf("cover = %v, saved = %v", post.CoverMediaID, saved)
+				}
+			})
+
+			t.Run("create", func(t *testing.T) {
+				// On create, only the owner's own uploads are theirs.
+				wantErr := tt.wantErr
+				if tt.mediaID == coAuthCover {
+					wantErr = errs.ErrMediaNotOwner
+				}
+				input := &postdomain.Post{AuthorID: authorID, Title: "New", CoverMediaID: &mediaID}
+				_, err := newTestUsecase(newRepo()).Create(ctx, input, tt.role)
+				if !errors.Is(err, wantErr) {
+					t.Fatalf("err = %v, want %v", err, wantErr)
+				}
+			})
+		})
+	}
+}
+
+func TestLifecycleTransitions(t *testing.T) {
+	ctx := context.Background()
+	publishedAt := time.Now().Add(-time.Hour)
+
+	publish := func(role string) func(*usecase, int) (*postdomain.Post, error) {
+		return func(uc *usecase, version int) (*postdomain.Post, error) {
+			return uc.Publish(ctx, postID, nil, version, authorID, role)
+		}
+	}
+	unpublish := func(uc *usecase, version int) (*postdomain.Post, error) {
+		return uc.Unpublish(ctx, postID, version, authorID)
+	}
+	archive := func(uc *usecase, version int) (*postdomain.Post, error) {
+		return uc.Archive(ctx, postID, version, authorID)
+	}
+
+	tests := []struct {
+		name      string
+		from      postStatus
+		step      func(*usecase, int) (*postdomain.Post, error)
+		version   int
+		want      postStatus
+		wantErr   error
+		published bool
+	}{
+		{"unpublish published", StatusPublished, unpublish, 0, StatusDraft, nil, false},
+		{"unpublish scheduled", StatusScheduled, unpublish, 0, StatusDraft, nil, false},
+		{"unpublish archived", StatusArchived, unpublish, 0, StatusDraft, nil, false},
+		{"unpublish draft", StatusDraft, unpublish, 0, StatusDraft, errs.ErrPostInvalidTransition, false},
+		{"unpublish post in review", StatusInReview, unpublish, 0, StatusInReview, errs.ErrPostInvalidTransition, false},
+		{"archive published", StatusPublished, archive, 0, StatusArchived, nil, true},
+		{"archive draft", StatusDraft, archive, 0, StatusDraft, errs.ErrPostInvalidTransition, false},
+		{"archive scheduled", StatusScheduled, archive, 0, StatusScheduled, errs.ErrPostInvalidTransition, true},
+		{"archive current version", StatusPublished, archive, 1, StatusArchived, nil, true},
+		{"archive stale version", StatusPublished, archive, 2, StatusPublished, errs.ErrVersionConflict, true},
+		{"unpublish stale version", StatusPublished, unpublish, 2, StatusPublished, errs.ErrVersionConflict, true},
+		{"editor republishes archived", StatusArchived, publish(roleEditor), 0, StatusPublished, nil, true},
+		{"author republishes archived", StatusArchived, publish(roleUser), 0, StatusArchived, errs.ErrReviewRequired, false},
+		{"publish stale version", StatusApproved, publish(roleUser), 2, StatusApproved, errs.ErrVersionConflict, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := newPost(tt.from)
+			if tt.from == StatusPublished || tt.from == StatusScheduled {
+				post.PublishedAt = &publishedAt
+			}
+			repo := newFakeRepo(post)
+
+			_, err := tt.step(newTestUsecase(repo), tt.version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			got, _ := repo.FindByID(ctx, postID)
+			if got.Status != string(tt.want) {
+				t.Errorf("status = %s, want %s", got.Status, tt.want)
+			}
+			if (got.PublishedAt != nil) != tt.published {
+				t.Errorf("published at = %v, want set = %v", got.PublishedAt, tt.published)
+			}
+			if tt.wantErr == nil && len(repo.actions()) != 1 {
+				t.Errorf("recorded %v, want the step", repo.actions())
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package postusecase
+
+import (
+	"context"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+const (
+	// MaxRelated is how many related posts are stored, and so the most a
+	// client can ask for.
+	MaxRelated       = 10
+	relatedBatchSize = 50
+)
+
+// GetRelated returns up to limit of the precomputed related posts of a
+// published post that isn't private. Posts unpublished or unlisted since the
+// last run are left out.
+func (u *usecase) GetRelated(ctx context.Context, id string, limit int) ([]*postdomain.Summary, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	post, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !CanRead(post, "") {
+		return nil, errs.ErrPostNotFound
+	}
+
+	ids, err := u.repo.FindRelated(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := u.repo.FindByIDs(ctx, ids, "")
+	if err != nil {
+		return nil, err
+	}
+	return listed(posts), nil
+}
+
+// RefreshRelated recomputes the related posts of posts that changed, or
+// whose neighbours did, since the last run. It is run periodically by the
+// scheduler.
+func (u *usecase) RefreshRelated(ctx context.Context) error {
+	listCtx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := u.repo.InvalidateRelated(listCtx); err != nil {
+		return err
+	}
+	ids, err := u.repo.ListStaleRelated(listCtx, relatedBatchSize)
+	if err != nil {
+		return err
+	}
+
+	// Scoring scans every published post, so each one gets its own timeout.
+	for _, id := range ids {
+		if err := u.computeRelated(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *usecase) computeRelated(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.ComputeRelated(ctx, id, MaxRelated)
+}
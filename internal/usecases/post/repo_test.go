@@ -0,0 +1,370 @@
+package postusecase
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/notify"
+	postrepo "github.com/codepnw/blog-api/internal/repositories/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init("test")
+	os.Exit(m.Run())
+}
+
+// fakeRepo keeps posts in memory and mirrors the conditions the SQL of
+// the real repository enforces. Methods it doesn't implement panic through
+// the nil embedded interface.
+type fakeRepo struct {
+	postrepo.Repository
+
+	mu        sync.Mutex
+	posts     map[string]*postdomain.Post
+	revisions map[string][]*postdomain.Revision
+	locks     map[string]*postdomain.Lock
+	autosaves map[string]*postdomain.Autosave
+	// translations are keyed by post, then locale.
+	translations map[string]map[string]*postdomain.Translation
+	// uploaders maps media to the user who uploaded it.
+	uploaders map[string]string
+	reviews   []*postdomain.Review
+}
+
+func newFakeRepo(posts ...*postdomain.Post) *fakeRepo {
+	r := &fakeRepo{
+		posts:     make(map[string]*postdomain.Post),
+		revisions: make(map[string][]*postdomain.Revision),
+		locks:     make(map[string]*postdomain.Lock),
+		autosaves: make(map[string]*postdomain.Autosave),
+
+		translations: make(map[string]map[string]*postdomain.Translation),
+		uploaders:    make(map[string]string),
+	}
+	for _, p := range posts {
+		if p.Version == 0 {
+			p.Version = 1
+		}
+		if p.ContentFormat == "" {
+			p.ContentFormat = "markdown"
+		}
+		r.posts[p.ID] = p
+		r.revisions[p.ID] = []*postdomain.Revision{
+			{PostID: p.ID, Revision: 1, Title: p.Title, Content: p.Content, Format: p.ContentFormat},
+		}
+	}
+	return r
+}
+
+func newTestUsecase(repo *fakeRepo) *usecase {
+	return &usecase{repo: repo, notifier: notify.NewLogNotifier()}
+}
+
+func (r *fakeRepo) status(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.posts[id].Status
+}
+
+func (r *fakeRepo) Insert(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p := *input
+	if p.ID == "" {
+		p.ID = fmt.Sprintf("post-%d", len(r.posts)+1)
+	}
+	p.Version = 1
+	r.posts[p.ID] = &p
+	clone := p
+	return &clone, nil
+}
+
+func (r *fakeRepo) FindByID(ctx context.Context, id string) (*postdomain.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.posts[id]
+	if !ok {
+		return nil, errs.ErrPostNotFound
+	}
+	clone := *p
+	// As localesColumn: the post's own locale, then its translations.
+	clone.Locales = []string{p.Locale}
+	for _, l := range slices.Sorted(maps.Keys(r.translations[id])) {
+		clone.Locales = append(clone.Locales, l)
+	}
+	return &clone, nil
+}
+
+func (r *fakeRepo) Update(ctx context.Context, input *postdomain.Post, editorID, note string, withdrawReview bool) (*postdomain.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.posts[input.ID]
+	if !ok {
+		return nil, errs.ErrPostNotFound
+	}
+	if input.Version != 0 && input.Version != p.Version {
+		return nil, errs.ErrVersionConflict
+	}
+	if input.Title != "" {
+		p.Title = input.Title
+	}
+	if input.Content != "" {
+		p.Content = input.Content
+	}
+	if input.ContentFormat != "" {
+		p.ContentFormat = input.ContentFormat
+	}
+	if input.CoverMediaID != nil {
+		p.CoverMediaID = input.CoverMediaID
+	}
+	if withdrawReview {
+		withdraw(p)
+	}
+	p.Version++
+
+	revs := r.revisions[p.ID]
+	r.revisions[p.ID] = append(revs, &postdomain.Revision{
+		PostID:   p.ID,
+		Revision: len(revs) + 1,
+		Title:    p.Title,
+		Content:  p.Content,
+		Format:   p.ContentFormat,
+		EditorID: editorID,
+		Note:     note,
+	})
+	clone := *p
+	return &clone, nil
+}
+
+// withdraw mirrors withdrawReviewSet.
+func withdraw(p *postdomain.Post) {
+	switch postStatus(p.Status) {
+	case StatusScheduled, StatusPublished:
+		p.PublishedAt = nil
+		fallthrough
+	case StatusInReview, StatusApproved:
+		p.Status = string(StatusDraft)
+	}
+}
+
+func (r *fakeRepo) UpsertTranslation(ctx context.Context, input *postdomain.Translation, version int, withdrawReview bool) (*postdomain.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, err := r.bumpVersion(input.PostID, version, withdrawReview)
+	if err != nil {
+		return nil, err
+	}
+	if r.translations[post.ID] == nil {
+		r.translations[post.ID] = make(map[string]*postdomain.Translation)
+	}
+	saved := *input
+	r.translations[post.ID][input.Locale] = &saved
+	return post, nil
+}
+
+func (r *fakeRepo) DeleteTranslation(ctx context.Context, postID, locale string, version int, withdrawReview bool) (*postdomain.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.translations[postID][locale]; !ok {
+		return nil, errs.ErrTranslationNotFound
+	}
+	post, err := r.bumpVersion(postID, version, withdrawReview)
+	if err != nil {
+		return nil, err
+	}
+	delete(r.translations[postID], locale)
+	return post, nil
+}
+
+func (r *fakeRepo) FindTranslation(ctx context.Context, postID, locale string) (*postdomain.Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tr, ok := r.translations[postID][locale]
+	if !ok {
+		return nil, errs.ErrTranslationNotFound
+	}
+	clone := *tr
+	return &clone, nil
+}
+
+func (r *fakeRepo) FindBySlug(ctx context.Context, slug string, locales []string) (string, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.posts {
+		if p.Slug != nil && *p.Slug == slug {
+			return p.ID, p.Locale, nil
+		}
+	}
+	var id, locale string
+	for postID, byLocale := range r.translations {
+		for l, tr := range byLocale {
+			if tr.Slug == nil || *tr.Slug != slug {
+				continue
+			}
+			if id == "" || rank(locales, l) < rank(locales, locale) {
+				id, locale = postID, l
+			}
+		}
+	}
+	if id == "" {
+		return "", "", errs.ErrPostNotFound
+	}
+	return id, locale, nil
+}
+
+// rank orders locales the way array_position does, unlisted ones last.
+func rank(locales []string, locale string) int {
+	if i := slices.Index(locales, locale); i >= 0 {
+		return i
+	}
+	return len(locales)
+}
+
+// bumpVersion mirrors the repository's for a change stored outside the
+// post. The caller holds r.mu.
+func (r *fakeRepo) bumpVersion(id string, version int, withdrawReview bool) (*postdomain.Post, error) {
+	p, ok := r.posts[id]
+	if !ok {
+		return nil, errs.ErrPostNotFound
+	}
+	if version != 0 && version != p.Version {
+		return nil, errs.ErrVersionConflict
+	}
+	if withdrawReview {
+		withdraw(p)
+	}
+	p.Version++
+	clone := *p
+	return &clone, nil
+}
+
+func (r *fakeRepo) Transition(ctx context.Context, review *postdomain.Review, from []string, to string, publishedAt *time.Time, version int) (*postdomain.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.posts[review.PostID]
+	if !ok {
+		return nil, errs.ErrPostNotFound
+	}
+	if version != 0 && version != p.Version {
+		return nil, errs.ErrVersionConflict
+	}
+	if !slices.Contains(from, p.Status) {
+		return nil, errs.ErrPostInvalidTransition
+	}
+	p.Status = to
+	switch postStatus(to) {
+	case StatusPublished, StatusScheduled:
+		p.PublishedAt = publishedAt
+	case StatusDraft:
+		p.PublishedAt = nil
+	}
+	p.Version++
+	r.reviews = append(r.reviews, review)
+	clone := *p
+	return &clone, nil
+}
+
+// actions lists the steps recorded by Transition, oldest first.
+func (r *fakeRepo) actions() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var actions []string
+	for _, rv := range r.reviews {
+		actions = append(actions, rv.Action)
+	}
+	return actions
+}
+
+func (r *fakeRepo) FindMediaUploaderID(ctx context.Context, mediaID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uploaderID, ok := r.uploaders[mediaID]
+	if !ok {
+		return "", errs.ErrMediaNotFound
+	}
+	return uploaderID, nil
+}
+
+func (r *fakeRepo) ListReviewerIDs(ctx context.Context) ([]string, error) {
+	return []string{editorID}, nil
+}
+
+func (r *fakeRepo) FindRevision(ctx context.Context, postID string, revision int) (*postdomain.Revision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revs := r.revisions[postID]
+	if revision < 1 || revision > len(revs) {
+		return nil, errs.ErrRevisionNotFound
+	}
+	return revs[revision-1], nil
+}
+
+func (r *fakeRepo) FindLock(ctx context.Context, postID string) (*postdomain.Lock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lock, ok := r.locks[postID]
+	if !ok || !lock.ExpiresAt.After(time.Now()) {
+		return nil, errs.ErrLockNotFound
+	}
+	return lock, nil
+}
+
+func (r *fakeRepo) SaveAutosave(ctx context.Context, input *postdomain.Autosave, rebase bool) (*postdomain.Autosave, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := input.PostID + "/" + input.UserID
+	saved := *input
+	if existing, ok := r.autosaves[key]; ok && !rebase {
+		saved.BaseVersion = existing.BaseVersion
+	}
+	saved.SavedAt = time.Now()
+	r.autosaves[key] = &saved
+	clone := saved
+	return &clone, nil
+}
+
+func (r *fakeRepo) FindAutosave(ctx context.Context, postID, userID string) (*postdomain.Autosave, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.autosaves[postID+"/"+userID]
+	if !ok {
+		return nil, errs.ErrAutosaveNotFound
+	}
+	clone := *a
+	return &clone, nil
+}
+
+func (r *fakeRepo) DeleteAutosave(ctx context.Context, postID, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := postID + "/" + userID
+	if _, ok := r.autosaves[key]; !ok {
+		return errs.ErrAutosaveNotFound
+	}
+	delete(r.autosaves, key)
+	return nil
+}
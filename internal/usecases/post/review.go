@@ -0,0 +1,205 @@
+package postusecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/notify"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/logger"
+)
+
+type reviewAction string
+
+const (
+	ReviewSubmitted        reviewAction = "submitted"
+	ReviewApproved         reviewAction = "approved"
+	ReviewChangesRequested reviewAction = "changes_requested"
+
+	// Lifecycle steps, recorded in the same history.
+	ReviewPublished   reviewAction = "published"
+	ReviewScheduled   reviewAction = "scheduled"
+	ReviewUnpublished reviewAction = "unpublished"
+	ReviewArchived    reviewAction = "archived"
+)
+
+type transition struct {
+	from []postStatus
+	to   postStatus
+}
+
+// reviewTransitions is the editorial workflow: draft → in_review →
+// approved or changes_requested, and back to in_review once changed.
+// Publishing an approved post is left to Publish.
+var reviewTransitions = map[reviewAction]transition{
+	ReviewSubmitted:        {from: []postStatus{StatusDraft, StatusChangesRequested}, to: StatusInReview},
+	ReviewApproved:         {from: []postStatus{StatusInReview}, to: StatusApproved},
+	ReviewChangesRequested: {from: []postStatus{StatusInReview, StatusApproved}, to: StatusChangesRequested},
+}
+
+// IsReviewer reports whether a user role may approve posts and publish
+// without review.
+func IsReviewer(role string) bool {
+	return role == string(userusecase.RoleEditor) || role == string(userusecase.RoleAdmin)
+}
+
+// InReview reports whether a post in status is going through review:
+// submitted, approved or sent back for changes.
+func InReview(status string) bool {
+	switch postStatus(status) {
+	case StatusInReview, StatusApproved, StatusChangesRequested:
+		return true
+	default:
+		return false
+	}
+}
+
+// canPublish reports whether a user with role may publish a post in status.
+// Contributors need the approval of a reviewer first.
+func canPublish(status, role string) bool {
+	if IsReviewer(role) {
+		return true
+	}
+	switch postStatus(status) {
+	case StatusApproved, StatusScheduled, StatusPublished:
+		return true
+	default:
+		return false
+	}
+}
+
+// withdrawsReview reports whether an edit by a user with role undoes the
+// review of a post: what was approved is no longer what is, or would be,
+// published. Contributors can't change live content without a reviewer.
+func withdrawsReview(input *postdomain.Post, role string) bool {
+	if IsReviewer(role) {
+		return false
+	}
+	return input.Title != "" || input.Content != "" || input.ContentFormat != ""
+}
+
+func (u *usecase) Submit(ctx context.Context, id, userID, comment string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.review(ctx, id, userID, ReviewSubmitted, comment)
+}
+
+func (u *usecase) Approve(ctx context.Context, id, userID, role, comment string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := u.checkReviewer(ctx, id, userID, role); err != nil {
+		return nil, err
+	}
+	return u.review(ctx, id, userID, ReviewApproved, comment)
+}
+
+func (u *usecase) RequestChanges(ctx context.Context, id, userID, role, comment string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := u.checkReviewer(ctx, id, userID, role); err != nil {
+		return nil, err
+	}
+	return u.review(ctx, id, userID, ReviewChangesRequested, comment)
+}
+
+func (u *usecase) GetReviews(ctx context.Context, postID string) ([]*postdomain.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if _, err := u.repo.FindByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	return u.repo.ListReviews(ctx, postID)
+}
+
+// checkReviewer lets editors and admins review, but not editors their own
+// posts.
+func (u *usecase) checkReviewer(ctx context.Context, id, userID, role string) error {
+	if !IsReviewer(role) {
+		return errs.ErrReviewForbidden
+	}
+	post, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if IsAuthor(post, userID) && role != string(userusecase.RoleAdmin) {
+		return errs.ErrReviewOwnPost
+	}
+	return nil
+}
+
+// review applies a step of the workflow, records it and lets the people on
+// the other side know.
+func (u *usecase) review(ctx context.Context, id, userID string, action reviewAction, comment string) (*postdomain.Post, error) {
+	input := &postdomain.Review{PostID: id, ActorID: &userID, Action: string(action), Comment: comment}
+	post, err := u.transition(ctx, input, reviewTransitions[action], nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.notifyReview(ctx, post, userID, action, comment); err != nil {
+		logger.Error("usecase.review: notify", "id", id, "action", action, "error", err)
+	}
+	return post, nil
+}
+
+// transition moves the post along t and records the step in input.
+func (u *usecase) transition(ctx context.Context, input *postdomain.Review, t transition, publishedAt *time.Time, version int) (*postdomain.Post, error) {
+	from := make([]string, 0, len(t.from))
+	for _, s := range t.from {
+		from = append(from, string(s))
+	}
+	return u.repo.Transition(ctx, input, from, string(t.to), publishedAt, version)
+}
+
+// notifyReview tells the reviewers about a submission, and the authors
+// about the decision on it.
+func (u *usecase) notifyReview(ctx context.Context, post *postdomain.Post, actorID string, action reviewAction, comment string) error {
+	n := &notify.Notification{
+		Event:   "post." + string(action),
+		PostID:  post.ID,
+		Message: comment,
+	}
+
+	var recipients []string
+	switch action {
+	case ReviewSubmitted:
+		n.Subject = fmt.Sprintf("%q is waiting for review", post.Title)
+		ids, err := u.repo.ListReviewerIDs(ctx)
+		if err != nil {
+			return err
+		}
+		recipients = ids
+	case ReviewApproved:
+		n.Subject = fmt.Sprintf("%q was approved", post.Title)
+		recipients = authorIDs(post)
+	case ReviewChangesRequested:
+		n.Subject = fmt.Sprintf("Changes were requested on %q", post.Title)
+		recipients = authorIDs(post)
+	}
+
+	for _, id := range recipients {
+		if id != actorID && !slices.Contains(n.Recipients, id) {
+			n.Recipients = append(n.Recipients, id)
+		}
+	}
+	if len(n.Recipients) == 0 {
+		return nil
+	}
+	return u.notifier.Notify(ctx, n)
+}
+
+func authorIDs(post *postdomain.Post) []string {
+	ids := []string{post.AuthorID}
+	for _, byline := range post.Authors {
+		ids = append(ids, byline.UserID)
+	}
+	return ids
+}
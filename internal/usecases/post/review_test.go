@@ -0,0 +1,340 @@
+package postusecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+const (
+	postID   = "post-1"
+	authorID = "author-1"
+	editorID = "editor-1"
+	adminID  = "admin-1"
+)
+
+var (
+	roleUser   = string(userusecase.RoleUser)
+	roleEditor = string(userusecase.RoleEditor)
+	roleAdmin  = string(userusecase.RoleAdmin)
+)
+
+func newPost(status postStatus) *postdomain.Post {
+	return &postdomain.Post{
+		ID:       postID,
+		AuthorID: authorID,
+		Title:    "Draft",
+		Content:  "Short draft.",
+		Status:   string(status),
+	}
+}
+
+func TestReviewTransitions(t *testing.T) {
+	submit := func(uc *usecase) error {
+		_, err := uc.Submit(context.Background(), postID, authorID, "")
+		return err
+	}
+	approve := func(userID, role string) func(*usecase) error {
+		return func(uc *usecase) error {
+			_, err := uc.Approve(context.Background(), postID, userID, role, "")
+			return err
+		}
+	}
+	requestChanges := func(uc *usecase) error {
+		_, err := uc.RequestChanges(context.Background(), postID, editorID, roleEditor, "needs work")
+		return err
+	}
+
+	tests := []struct {
+		name    string
+		from    postStatus
+		action  func(*usecase) error
+		want    postStatus
+		wantErr error
+	}{
+		{"submit draft", StatusDraft, submit, StatusInReview, nil},
+		{"resubmit after changes", StatusChangesRequested, submit, StatusInReview, nil},
+		{"submit twice", StatusInReview, submit, StatusInReview, errs.ErrPostInvalidTransition},
+		{"submit approved", StatusApproved, submit, StatusApproved, errs.ErrPostInvalidTransition},
+		{"approve", StatusInReview, approve(editorID, roleEditor), StatusApproved, nil},
+		{"approve draft", StatusDraft, approve(editorID, roleEditor), StatusDraft, errs.ErrPostInvalidTransition},
+		{"approve as user", StatusInReview, approve(editorID, roleUser), StatusInReview, errs.ErrReviewForbidden},
+		{"approve own post", StatusInReview, approve(authorID, roleEditor), StatusInReview, errs.ErrReviewOwnPost},
+		{"admin approves own post", StatusInReview, approve(authorID, roleAdmin), StatusApproved, nil},
+		{"request changes", StatusInReview, requestChanges, StatusChangesRequested, nil},
+		{"request changes after approval", StatusApproved, requestChanges, StatusChangesRequested, nil},
+		{"request changes on draft", StatusDraft, requestChanges, StatusDraft, errs.ErrPostInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo(newPost(tt.from))
+			err := tt.action(newTestUsecase(repo))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got := repo.status(postID); got != string(tt.want) {
+				t.Errorf("status = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublishRequiresApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    postStatus
+		role    string
+		wantErr error
+	}{
+		{"author publishes draft", StatusDraft, roleUser, errs.ErrReviewRequired},
+		{"author publishes post in review", StatusInReview, roleUser, errs.ErrReviewRequired},
+		{"author publishes post sent back", StatusChangesRequested, roleUser, errs.ErrReviewRequired},
+		{"author publishes approved post", StatusApproved, roleUser, nil},
+		{"editor publishes draft", StatusDraft, roleEditor, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo(newPost(tt.from))
+			_, err := newTestUsecase(repo).Publish(context.Background(), postID, nil, 0, authorID, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEditWithdrawsReview(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		from postStatus
+		role string
+		edit func(*usecase) error
+		want postStatus
+	}{
+		{
+			name: "author edits approved post",
+			from: StatusApproved,
+			role: roleUser,
+			edit: func(uc *usecase) error {
+				_, err := uc.Update(ctx, &postdomain.Post{ID: postID, Content: "Rewritten."}, authorID, roleUser, "")
+				return err
+			},
+			want: StatusDraft,
+		},
+		{
+			name: "author edits post in review",
+			from: StatusInReview,
+			role: roleUser,
+			edit: func(uc *usecase) error {
+				_, err := uc.Update(ctx, &postdomain.Post{ID: postID, Title: "New title"}, authorID, roleUser, "")
+				return err
+			},
+			want: StatusDraft,
+		},
+		{
+			name: "author restores a revision of approved post",
+			from: StatusApproved,
+			role: roleUser,
+			edit: func(uc *usecase) error {
+				_, err := uc.RestoreRevision(ctx, postID, 1, 0, authorID, roleUser)
+				return err
+			},
+			want: StatusDraft,
+		},
+		{
+			name: "author translates published post",
+			from: StatusPublished,
+			role: roleUser,
+			edit: func(uc *usecase) error {
+				input := &postdomain.Translation{PostID: postID, Locale: "th", Title: "Title", Content: "Translated."}
+				_, err := uc.SetTranslation(ctx, input, 0, authorID, roleUser)
+				return err
+			},
+			want: StatusDraft,
+		},
+		{
+			name: "author removes a translation of approved post",
+			from: StatusApproved,
+			role: roleUser,
+			edit: func(uc *usecase) error {
+				input := &postdomain.Translation{PostID: postID, Locale: "th", Title: "Title", Content: "Translated."}
+				if _, err := uc.SetTranslation(ctx, input, 0, editorID, roleEditor); err != nil {
+					return err
+				}
+				_, err := uc.DeleteTranslation(ctx, postID, "th", 0, authorID, roleUser)
+				return err
+			},
+			want: StatusDraft,
+		},
+		{
+			name: "editor translates published post",
+			from: StatusPublished,
+			role: roleEditor,
+			edit: func(uc *usecase) error {
+				input := &postdomain.Translation{PostID: postID, Locale: "th", Title: "Title", Content: "Translated."}
+				_, err := uc.SetTranslation(ctx, input, 0, editorID, roleEditor)
+				return err
+			},
+			want: StatusPublished,
+		},
+		{
+			name: "author changes only the visibility",
+			from: StatusApproved,
+			role: roleUser,
+			edit: func(uc *usecase) error {
+				_, err := uc.Update(ctx, &postdomain.Post{ID: postID, Visibility: string(VisibilityUnlisted)}, authorID, roleUser, "")
+				return err
+			},
+			want: StatusApproved,
+		},
+		{
+			name: "editor edits approved post",
+			from: StatusApproved,
+			role: roleEditor,
+			edit: func(uc *usecase) error {
+				_, err := uc.Update(ctx, &postdomain.Post{ID: postID, Content: "Copy edited."}, editorID, roleEditor, "")
+				return err
+			},
+			want: StatusApproved,
+		},
+		{
+			name: "author edits scheduled post",
+			from: StatusScheduled,
+			role: roleUser,
+			edit: func(uc *usecase) error {
+				_, err := uc.Update(ctx, &postdomain.Post{ID: postID, Content: "Swapped after approval."}, authorID, roleUser, "")
+				return err
+			},
+			want: StatusDraft,
+		},
+		{
+			name: "author edits published post",
+			from: StatusPublished,
+			role: roleUser,
+			edit: func(uc *usecase) error {
+				_, err := uc.Update(ctx, &postdomain.Post{ID: postID, ContentFormat: "html"}, authorID, roleUser, "")
+				return err
+			},
+			want: StatusDraft,
+		},
+		{
+			name: "editor edits published post",
+			from: StatusPublished,
+			role: roleEditor,
+			edit: func(uc *usecase) error {
+				_, err := uc.Update(ctx, &postdomain.Post{ID: postID, Content: "Typo fixed."}, editorID, roleEditor, "")
+				return err
+			},
+			want: StatusPublished,
+		},
+		{
+			name: "author edits changes requested",
+			from: StatusChangesRequested,
+			role: roleUser,
+			edit: func(uc *usecase) error {
+				_, err := uc.Update(ctx, &postdomain.Post{ID: postID, Content: "Fixed."}, authorID, roleUser, "")
+				return err
+			},
+			want: StatusChangesRequested,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo(newPost(tt.from))
+			if err := tt.edit(newTestUsecase(repo)); err != nil {
+				t.Fatalf("edit: %v", err)
+			}
+			if got := repo.status(postID); got != string(tt.want) {
+				t.Errorf("status = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApproveEditPublishIsRefused(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepo(newPost(StatusDraft))
+	uc := newTestUsecase(repo)
+
+	if _, err := uc.Submit(ctx, postID, authorID, ""); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if _, err := uc.Approve(ctx, postID, editorID, roleEditor, ""); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	input := &postdomain.Post{ID: postID, Title: "Something else", Content: "Never reviewed."}
+	if _, err := uc.Update(ctx, input, authorID, roleUser, ""); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := uc.Publish(ctx, postID, nil, 0, authorID, roleUser); !errors.Is(err, errs.ErrReviewRequired) {
+		t.Fatalf("publish err = %v, want %v", err, errs.ErrReviewRequired)
+	}
+
+	// Going through review again lets it out.
+	if _, err := uc.Submit(ctx, postID, authorID, ""); err != nil {
+		t.Fatalf("resubmit: %v", err)
+	}
+	if _, err := uc.Approve(ctx, postID, editorID, roleEditor, ""); err != nil {
+		t.Fatalf("reapprove: %v", err)
+	}
+	post, err := uc.Publish(ctx, postID, nil, 0, authorID, roleUser)
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if post.Status != string(StatusPublished) {
+		t.Errorf("status = %s, want %s", post.Status, StatusPublished)
+	}
+}
+
+func TestEditLivePostIsWithdrawn(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		publish func(*usecase) error
+	}{
+		{
+			name: "scheduled",
+			publish: func(uc *usecase) error {
+				at := time.Now().Add(time.Hour)
+				_, err := uc.Publish(ctx, postID, &at, 0, authorID, roleUser)
+				return err
+			},
+		},
+		{
+			name: "published",
+			publish: func(uc *usecase) error {
+				_, err := uc.Publish(ctx, postID, nil, 0, authorID, roleUser)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo(newPost(StatusApproved))
+			uc := newTestUsecase(repo)
+
+			if err := tt.publish(uc); err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+			input := &postdomain.Post{ID: postID, Content: "Never reviewed."}
+			post, err := uc.Update(ctx, input, authorID, roleUser, "")
+			if err != nil {
+				t.Fatalf("update: %v", err)
+			}
+			if post.Status != string(StatusDraft) || post.PublishedAt != nil {
+				t.Fatalf("status = %s, published at %v, want an unpublished draft", post.Status, post.PublishedAt)
+			}
+			if err := tt.publish(uc); !errors.Is(err, errs.ErrReviewRequired) {
+				t.Fatalf("republish err = %v, want %v", err, errs.ErrReviewRequired)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package postusecase
+
+import (
+	"context"
+	"fmt"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/diff"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/logger"
+)
+
+const (
+	DiffModeLine = "line"
+	DiffModeWord = "word"
+)
+
+type RevisionDiff struct {
+	PostID  string    `json:"post_id"`
+	From    int       `json:"from"`
+	To      int       `json:"to"`
+	Mode    string    `json:"mode"`
+	Title   []diff.Op `json:"title"`
+	Content []diff.Op `json:"content"`
+}
+
+func (u *usecase) GetRevisions(ctx context.Context, postID string) ([]*postdomain.Revision, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.ListRevisions(ctx, postID)
+}
+
+func (u *usecase) GetRevision(ctx context.Context, postID string, revision int) (*postdomain.Revision, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.FindRevision(ctx, postID, revision)
+}
+
+func (u *usecase) DiffRevisions(ctx context.Context, postID string, from, to int, mode string) (*RevisionDiff, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	diffFunc := diff.Lines
+	switch mode {
+	case "", DiffModeLine:
+		mode = DiffModeLine
+	case DiffModeWord:
+		diffFunc = diff.Words
+	default:
+		return nil, errs.ErrInvalidDiffMode
+	}
+
+	fromRev, err := u.repo.FindRevision(ctx, postID, from)
+	if err != nil {
+		logger.Error("usecase.DiffRevisions: find from revision", "post_id", postID, "revision", from, "error", err)
+		return nil, err
+	}
+	toRev, err := u.repo.FindRevision(ctx, postID, to)
+	if err != nil {
+		logger.Error("usecase.DiffRevisions: find to revision", "post_id", postID, "revision", to, "error", err)
+		return nil, err
+	}
+
+	return &RevisionDiff{
+		PostID:  postID,
+		From:    from,
+		To:      to,
+		Mode:    mode,
+		Title:   diff.Words(fromRev.Title, toRev.Title),
+		Content: diffFunc(fromRev.Content, toRev.Content),
+	}, nil
+}
+
+// RestoreRevision copies an old revision back onto the post as an edit, so
+// it is subject to the edit lock and a non-zero version must match the
+// current one. History is never rewritten: the restore itself is recorded
+// as a new revision.
+func (u *usecase) RestoreRevision(ctx context.Context, postID string, revision, version int, editorID, role string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	rev, err := u.repo.FindRevision(ctx, postID, revision)
+	if err != nil {
+		logger.Error("usecase.RestoreRevision: find revision", "post_id", postID, "revision", revision, "error", err)
+		return nil, err
+	}
+
+	input := &postdomain.Post{
+		ID:            postID,
+		Title:         rev.Title,
+		Content:       rev.Content,
+		ContentFormat: rev.Format,
+		Version:       version,
+	}
+	note := fmt.Sprintf("restored from revision %d", revision)
+
+	return u.Update(ctx, input, editorID, role, note)
+}
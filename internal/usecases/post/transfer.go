@@ -0,0 +1,56 @@
+package postusecase
+
+import (
+	"context"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/render"
+)
+
+// Import creates a post brought over from another blog. Unlike Create it
+// keeps the given publication date of a published post, which is usually in
+// the past; without one the post is published now.
+func (u *usecase) Import(ctx context.Context, input *postdomain.Post) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if input.Status == string(StatusPublished) {
+		if input.PublishedAt == nil {
+			now := time.Now()
+			input.PublishedAt = &now
+		}
+	} else if err := u.prepareStatus(input); err != nil {
+		return nil, err
+	}
+
+	if input.ContentFormat == "" {
+		input.ContentFormat = render.FormatMarkdown
+	}
+	if input.Locale == "" {
+		input.Locale = DefaultLocale
+	}
+	if input.Visibility == "" {
+		input.Visibility = string(VisibilityPublic)
+	}
+	if err := u.renderContent(input); err != nil {
+		return nil, err
+	}
+	return u.repo.Insert(ctx, input)
+}
+
+func (u *usecase) GetIDBySlug(ctx context.Context, slug string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.FindIDBySlug(ctx, slug)
+}
+
+// GetAllPosts returns every post outside the trash with its content, for
+// export.
+func (u *usecase) GetAllPosts(ctx context.Context) ([]*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.ListAll(ctx)
+}
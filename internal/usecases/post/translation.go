@@ -0,0 +1,153 @@
+package postusecase
+
+import (
+	"context"
+	"slices"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/render"
+)
+
+// DefaultLocale is the language of posts created without one, such as
+// imported posts.
+const DefaultLocale = "en"
+
+// SetTranslation renders and stores the translation of a post into
+// input.Locale, and returns the post served in that locale.
+func (u *usecase) SetTranslation(ctx context.Context, input *postdomain.Translation, version int, editorID, role string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	post, err := u.repo.FindByID(ctx, input.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkLock(ctx, post.ID, editorID); err != nil {
+		return nil, err
+	}
+	if input.Locale == post.Locale {
+		return nil, errs.ErrTranslationOwnLocale
+	}
+
+	if input.ContentFormat == "" {
+		input.ContentFormat = render.FormatMarkdown
+	}
+	rendered := &postdomain.Post{Content: input.Content, ContentFormat: input.ContentFormat}
+	if err := u.renderContent(rendered); err != nil {
+		return nil, err
+	}
+	input.ContentHTML = rendered.ContentHTML
+	input.TOC = rendered.TOC
+	input.Excerpt = rendered.Excerpt
+	input.WordCount = rendered.WordCount
+	input.ReadingTime = rendered.ReadingTime
+
+	result, err := u.repo.UpsertTranslation(ctx, input, version, !IsReviewer(role))
+	if err != nil {
+		return nil, err
+	}
+	applyTranslation(result, input)
+	return result, nil
+}
+
+// DeleteTranslation removes a translation and returns the post in its own
+// locale.
+func (u *usecase) DeleteTranslation(ctx context.Context, postID, locale string, version int, editorID, role string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if err := u.checkLock(ctx, postID, editorID); err != nil {
+		return nil, err
+	}
+	return u.repo.DeleteTranslation(ctx, postID, locale, version, !IsReviewer(role))
+}
+
+func (u *usecase) GetTranslations(ctx context.Context, postID string) ([]*postdomain.Translation, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if _, err := u.repo.FindByID(ctx, postID); err != nil {
+		return nil, err
+	}
+	return u.repo.ListTranslations(ctx, postID)
+}
+
+func (u *usecase) ResolveSlug(ctx context.Context, slug string, chain []string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.FindBySlug(ctx, slug, chain)
+}
+
+func (u *usecase) Localize(ctx context.Context, post *postdomain.Post, chain []string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	for _, locale := range chain {
+		if locale == post.Locale {
+			return nil
+		}
+		if !slices.Contains(post.Locales, locale) {
+			continue
+		}
+
+		tr, err := u.repo.FindTranslation(ctx, post.ID, locale)
+		if err != nil {
+			return err
+		}
+		applyTranslation(post, tr)
+		return nil
+	}
+	return nil
+}
+
+// LocalizeSummaries serves each post of a list in the first locale of chain
+// it is written in or translated into.
+func (u *usecase) LocalizeSummaries(ctx context.Context, posts []*postdomain.Summary, chain []string) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	if len(posts) == 0 || len(chain) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+	translations, err := u.repo.FindTranslationTitles(ctx, ids, chain)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		for _, locale := range chain {
+			if locale == p.Locale {
+				break
+			}
+			if tr := translations[p.ID][locale]; tr != nil {
+				p.Locale = tr.Locale
+				p.Title = tr.Title
+				p.Excerpt = tr.Excerpt
+				p.WordCount = tr.WordCount
+				p.ReadingTime = tr.ReadingTime
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func applyTranslation(post *postdomain.Post, tr *postdomain.Translation) {
+	post.Locale = tr.Locale
+	post.Title = tr.Title
+	post.Slug = tr.Slug
+	post.Content = tr.Content
+	post.ContentFormat = tr.ContentFormat
+	post.ContentHTML = tr.ContentHTML
+	post.TOC = tr.TOC
+	post.Excerpt = tr.Excerpt
+	post.WordCount = tr.WordCount
+	post.ReadingTime = tr.ReadingTime
+}
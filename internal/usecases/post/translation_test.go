@@ -0,0 +1,62 @@
+package postusecase
+
+import (
+	"context"
+	"testing"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+)
+
+func TestResolveLocalizedSlug(t *testing.T) {
+	ctx := context.Background()
+
+	post := newPost(StatusPublished)
+	post.Locale = DefaultLocale
+	post.Slug = ptr("hello-world")
+	repo := newFakeRepo(post)
+	uc := newTestUsecase(repo)
+
+	input := &postdomain.Translation{
+		PostID:  postID,
+		Locale:  "th",
+		Title:   "สวัสดีชาวโลก",
+		Slug:    ptr("sawasdee"),
+		Content: "เนื้อหา",
+	}
+	if _, err := uc.SetTranslation(ctx, input, 0, editorID, roleEditor); err != nil {
+		t.Fatalf("set translation: %v", err)
+	}
+
+	tests := []struct {
+		slug      string
+		wantTitle string
+	}{
+		{"hello-world", "Draft"},
+		{"sawasdee", "สวัสดีชาวโลก"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			// The reader prefers English; the slug of the translation still
+			// serves the translation.
+			chain := []string{DefaultLocale}
+			id, locale, err := uc.ResolveSlug(ctx, tt.slug, chain)
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if id != postID {
+				t.Fatalf("id = %s, want %s", id, postID)
+			}
+
+			got, err := uc.GetByID(ctx, id)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if err := uc.Localize(ctx, got, append([]string{locale}, chain...)); err != nil {
+				t.Fatalf("localize: %v", err)
+			}
+			if got.Title != tt.wantTitle || got.Slug == nil || *got.Slug != tt.slug {
+				t.Errorf("served %q at %v, want %q at %s", got.Title, got.Slug, tt.wantTitle, tt.slug)
+			}
+		})
+	}
+}
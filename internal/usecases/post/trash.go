@@ -0,0 +1,48 @@
+package postusecase
+
+import (
+	"context"
+	"time"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	"github.com/codepnw/blog-api/internal/utils/logger"
+)
+
+func (u *usecase) GetDeletedByID(ctx context.Context, id string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.FindDeletedByID(ctx, id)
+}
+
+// GetTrash lists the trashed posts of authorID, or every trashed post when
+// authorID is empty.
+func (u *usecase) GetTrash(ctx context.Context, authorID string) ([]*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.ListDeleted(ctx, authorID)
+}
+
+func (u *usecase) Restore(ctx context.Context, id string) (*postdomain.Post, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	return u.repo.Restore(ctx, id)
+}
+
+// PurgeTrash hard-deletes posts that have been in the trash longer than
+// retention. It is run periodically by the scheduler.
+func (u *usecase) PurgeTrash(ctx context.Context, retention time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, contextTimeout)
+	defer cancel()
+
+	n, err := u.repo.Purge(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		logger.Info("usecase.PurgeTrash: posts purged", "count", n)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package postusecase
+
+import postdomain "github.com/codepnw/blog-api/internal/domains/post"
+
+type postVisibility string
+
+const (
+	VisibilityPublic postVisibility = "public"
+	// VisibilityUnlisted posts are reachable by link but left out of
+	// listings, feeds and the sitemap.
+	VisibilityUnlisted postVisibility = "unlisted"
+	// VisibilityMembers posts show anonymous readers a teaser.
+	VisibilityMembers postVisibility = "members"
+	// VisibilityPrivate posts are only visible to their authors and admins.
+	VisibilityPrivate postVisibility = "private"
+)
+
+// CanRead reports whether userID, empty for anonymous readers, may read the
+// post: its authors always, anyone else once it is published and not
+// private. Admins are not checked here.
+func CanRead(post *postdomain.Post, userID string) bool {
+	if IsAuthor(post, userID) {
+		return true
+	}
+	return post.Status == string(StatusPublished) && post.Visibility != string(VisibilityPrivate)
+}
+
+// IsListed reports whether a post of the given visibility appears in
+// listings.
+func IsListed(visibility string) bool {
+	return visibility == string(VisibilityPublic) || visibility == string(VisibilityMembers)
+}
+
+// listed leaves out the unlisted and private posts of a list that may hold
+// them, such as pins or related posts stored before the post changed.
+func listed(posts []*postdomain.Summary) []*postdomain.Summary {
+	result := make([]*postdomain.Summary, 0, len(posts))
+	for _, p := range posts {
+		if IsListed(p.Visibility) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,182 @@
+package previewusecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codepnw/blog-api/internal/config"
+	previewdomain "github.com/codepnw/blog-api/internal/domains/preview"
+	previewrepo "github.com/codepnw/blog-api/internal/repositories/preview"
+	"github.com/codepnw/blog-api/internal/usecases"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+type Usecase interface {
+	// Create makes a link to input.PostID that expires at input.ExpiresAt,
+	// or after the default lifetime when it is zero.
+	Create(ctx context.Context, input *previewdomain.Link, role string) (*previewdomain.Link, error)
+	GetByPost(ctx context.Context, postID, userID, role string) ([]*previewdomain.Link, error)
+	Revoke(ctx context.Context, postID, id, userID, role string) (*previewdomain.Link, error)
+	// Open returns the post a token links to, as it currently stands, while
+	// the link is active.
+	Open(ctx context.Context, token string) (*previewdomain.Preview, error)
+}
+
+type usecase struct {
+	repo   previewrepo.Repository
+	postUc postusecase.Usecase
+	cfg    config.PreviewConfig
+	secret []byte
+}
+
+func NewPreviewUsecase(repo previewrepo.Repository, postUc postusecase.Usecase, cfg config.PreviewConfig) Usecase {
+	return &usecase{repo: repo, postUc: postUc, cfg: cfg, secret: []byte(cfg.Secret)}
+}
+
+func (u *usecase) Create(ctx context.Context, input *previewdomain.Link, role string) (*previewdomain.Link, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	var userID string
+	if input.CreatedBy != nil {
+		userID = *input.CreatedBy
+	}
+	if err := u.checkPost(ctx, input.PostID, userID, role); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if input.ExpiresAt.IsZero() {
+		input.ExpiresAt = now.Add(u.cfg.DefaultTTL)
+	}
+	if !input.ExpiresAt.After(now) || input.ExpiresAt.After(now.Add(u.cfg.MaxTTL)) {
+		return nil, errs.ErrPreviewExpiry
+	}
+	// The token carries the expiry in whole seconds.
+	input.ExpiresAt = input.ExpiresAt.Truncate(time.Second)
+
+	link, err := u.repo.Insert(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	link.Token = u.sign(link)
+	return link, nil
+}
+
+func (u *usecase) GetByPost(ctx context.Context, postID, userID, role string) ([]*previewdomain.Link, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if err := u.checkPost(ctx, postID, userID, role); err != nil {
+		return nil, err
+	}
+	links, err := u.repo.ListByPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, link := range links {
+		if link.Active(now) {
+			link.Token = u.sign(link)
+		}
+	}
+	return links, nil
+}
+
+func (u *usecase) Revoke(ctx context.Context, postID, id, userID, role string) (*previewdomain.Link, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if err := u.checkPost(ctx, postID, userID, role); err != nil {
+		return nil, err
+	}
+	return u.repo.Revoke(ctx, postID, id)
+}
+
+func (u *usecase) Open(ctx context.Context, token string) (*previewdomain.Preview, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	id, expiresAt, ok := u.verify(token)
+	if !ok || !time.Now().Before(expiresAt) {
+		return nil, errs.ErrPreviewLinkInvalid
+	}
+
+	link, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, errs.ErrPreviewLinkNotFound) {
+			return nil, errs.ErrPreviewLinkInvalid
+		}
+		return nil, err
+	}
+	if !link.Active(time.Now()) || !link.ExpiresAt.Equal(expiresAt) {
+		return nil, errs.ErrPreviewLinkInvalid
+	}
+
+	post, err := u.postUc.GetByID(ctx, link.PostID)
+	if err != nil {
+		if errors.Is(err, errs.ErrPostNotFound) {
+			return nil, errs.ErrPreviewLinkInvalid
+		}
+		return nil, err
+	}
+	return &previewdomain.Preview{Preview: true, ExpiresAt: link.ExpiresAt, Post: post}, nil
+}
+
+// checkPost lets the authors of a post and admins share it.
+func (u *usecase) checkPost(ctx context.Context, postID, userID, role string) error {
+	post, err := u.postUc.GetByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if !postusecase.IsAuthor(post, userID) && role != string(userusecase.RoleAdmin) {
+		return errs.ErrPreviewForbidden
+	}
+	return nil
+}
+
+// sign makes the token of a link: its ID and expiry, and an HMAC of both so
+// neither can be changed.
+func (u *usecase) sign(link *previewdomain.Link) string {
+	payload := link.ID + "." + strconv.FormatInt(link.ExpiresAt.Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(u.mac(payload))
+}
+
+// verify checks the signature of a token and returns the link ID and expiry
+// it carries.
+func (u *usecase) verify(token string) (string, time.Time, bool) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return "", time.Time{}, false
+	}
+	payload := token[:i]
+	sig, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+	if err != nil || !hmac.Equal(sig, u.mac(payload)) {
+		return "", time.Time{}, false
+	}
+
+	id, exp, ok := strings.Cut(payload, ".")
+	if !ok {
+		return "", time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return id, time.Unix(unix, 0), true
+}
+
+func (u *usecase) mac(payload string) []byte {
+	mac := hmac.New(sha256.New, u.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,95 @@
+package reactionusecase
+
+import (
+	"context"
+	"slices"
+
+	reactiondomain "github.com/codepnw/blog-api/internal/domains/reaction"
+	reactionrepo "github.com/codepnw/blog-api/internal/repositories/reaction"
+	"github.com/codepnw/blog-api/internal/usecases"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+type Usecase interface {
+	// React and Unreact are idempotent, so clients can toggle without first
+	// reading the current state.
+	React(ctx context.Context, postID, userID, reaction string) (*reactiondomain.Reactions, error)
+	Unreact(ctx context.Context, postID, userID, reaction string) (*reactiondomain.Reactions, error)
+	GetByPost(ctx context.Context, postID, userID string) (*reactiondomain.Reactions, error)
+	// GetMine returns the reactions of a user on the given posts, keyed by
+	// post id.
+	GetMine(ctx context.Context, userID string, postIDs []string) (map[string][]string, error)
+}
+
+type usecase struct {
+	repo   reactionrepo.Repository
+	postUc postusecase.Usecase
+	types  []string
+}
+
+func NewReactionUsecase(repo reactionrepo.Repository, postUc postusecase.Usecase, types []string) Usecase {
+	return &usecase{repo: repo, postUc: postUc, types: types}
+}
+
+func (u *usecase) React(ctx context.Context, postID, userID, reaction string) (*reactiondomain.Reactions, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if err := u.check(ctx, postID, userID, reaction); err != nil {
+		return nil, err
+	}
+	if _, err := u.repo.Add(ctx, postID, userID, reaction); err != nil {
+		return nil, err
+	}
+	return u.repo.Find(ctx, postID, userID)
+}
+
+func (u *usecase) Unreact(ctx context.Context, postID, userID, reaction string) (*reactiondomain.Reactions, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if err := u.check(ctx, postID, userID, reaction); err != nil {
+		return nil, err
+	}
+	if _, err := u.repo.Remove(ctx, postID, userID, reaction); err != nil {
+		return nil, err
+	}
+	return u.repo.Find(ctx, postID, userID)
+}
+
+func (u *usecase) GetByPost(ctx context.Context, postID, userID string) (*reactiondomain.Reactions, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if err := u.checkPost(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+	return u.repo.Find(ctx, postID, userID)
+}
+
+func (u *usecase) GetMine(ctx context.Context, userID string, postIDs []string) (map[string][]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return u.repo.FindByUser(ctx, userID, postIDs)
+}
+
+func (u *usecase) check(ctx context.Context, postID, userID, reaction string) error {
+	if !slices.Contains(u.types, reaction) {
+		return errs.ErrInvalidReaction
+	}
+	return u.checkPost(ctx, postID, userID)
+}
+
+// checkPost only lets readers react to published posts they can read.
+func (u *usecase) checkPost(ctx context.Context, postID, userID string) error {
+	post, err := u.postUc.GetByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if post.Status != string(postusecase.StatusPublished) || !postusecase.CanRead(post, userID) {
+		return errs.ErrPostNotFound
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package redirectusecase
+
+import (
+	"context"
+
+	redirectrepo "github.com/codepnw/blog-api/internal/repositories/redirect"
+	"github.com/codepnw/blog-api/internal/usecases"
+)
+
+type Usecase interface {
+	// Add points old paths at a post and returns how many were new.
+	Add(ctx context.Context, postID string, paths []string) (int, error)
+	// Resolve returns the published post a path leads to.
+	Resolve(ctx context.Context, path string) (string, error)
+}
+
+type usecase struct {
+	repo redirectrepo.Repository
+}
+
+func NewRedirectUsecase(repo redirectrepo.Repository) Usecase {
+	return &usecase{repo: repo}
+}
+
+func (u *usecase) Add(ctx context.Context, postID string, paths []string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	n, err := u.repo.Insert(ctx, postID, paths)
+	return int(n), err
+}
+
+func (u *usecase) Resolve(ctx context.Context, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return u.repo.FindPostID(ctx, path)
+}
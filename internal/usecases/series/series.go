@@ -0,0 +1,238 @@
+package seriesusecase
+
+import (
+	"context"
+	"errors"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	seriesdomain "github.com/codepnw/blog-api/internal/domains/series"
+	seriesrepo "github.com/codepnw/blog-api/internal/repositories/series"
+	"github.com/codepnw/blog-api/internal/usecases"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+type Usecase interface {
+	Create(ctx context.Context, input *seriesdomain.Series) (*seriesdomain.Series, error)
+	// GetByID returns the series with the parts the viewer can see.
+	GetByID(ctx context.Context, id, viewerID, role string) (*seriesdomain.Series, error)
+	Update(ctx context.Context, input *seriesdomain.Series, userID, role string) (*seriesdomain.Series, error)
+	Delete(ctx context.Context, id, userID, role string) error
+
+	// Parts
+	AddPost(ctx context.Context, id, postID, userID, role string) (*seriesdomain.Series, error)
+	RemovePost(ctx context.Context, id, postID, userID, role string) (*seriesdomain.Series, error)
+	Reorder(ctx context.Context, id string, postIDs []string, userID, role string) (*seriesdomain.Series, error)
+	// GetNav returns where the post sits in its series, or nil when it isn't
+	// part of one.
+	GetNav(ctx context.Context, post *postdomain.Post, viewerID, role string) (*postdomain.SeriesNav, error)
+}
+
+type usecase struct {
+	repo   seriesrepo.Repository
+	postUc postusecase.Usecase
+}
+
+func NewSeriesUsecase(repo seriesrepo.Repository, postUc postusecase.Usecase) Usecase {
+	return &usecase{repo: repo, postUc: postUc}
+}
+
+func (u *usecase) Create(ctx context.Context, input *seriesdomain.Series) (*seriesdomain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	series, err := u.repo.Insert(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	series.Parts = []*postdomain.Summary{}
+	return series, nil
+}
+
+func (u *usecase) GetByID(ctx context.Context, id, viewerID, role string) (*seriesdomain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	series, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.withParts(ctx, series, viewerID, role)
+}
+
+func (u *usecase) Update(ctx context.Context, input *seriesdomain.Series, userID, role string) (*seriesdomain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	series, err := u.ownSeries(ctx, input.ID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if input.Title != "" {
+		series.Title = input.Title
+	}
+	if input.Description != "" {
+		series.Description = input.Description
+	}
+
+	if series, err = u.repo.Update(ctx, series); err != nil {
+		return nil, err
+	}
+	return u.withParts(ctx, series, userID, role)
+}
+
+func (u *usecase) Delete(ctx context.Context, id, userID, role string) error {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if _, err := u.ownSeries(ctx, id, userID, role); err != nil {
+		return err
+	}
+	return u.repo.Delete(ctx, id)
+}
+
+func (u *usecase) AddPost(ctx context.Context, id, postID, userID, role string) (*seriesdomain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	series, err := u.ownSeries(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := u.postUc.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.AuthorID != series.AuthorID {
+		return nil, errs.ErrSeriesPostNotOwned
+	}
+
+	if err := u.repo.AddPart(ctx, id, postID); err != nil {
+		return nil, err
+	}
+	return u.withParts(ctx, series, userID, role)
+}
+
+func (u *usecase) RemovePost(ctx context.Context, id, postID, userID, role string) (*seriesdomain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	series, err := u.ownSeries(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.RemovePart(ctx, id, postID); err != nil {
+		return nil, err
+	}
+	return u.withParts(ctx, series, userID, role)
+}
+
+func (u *usecase) Reorder(ctx context.Context, id string, postIDs []string, userID, role string) (*seriesdomain.Series, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	series, err := u.ownSeries(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.Reorder(ctx, id, postIDs); err != nil {
+		return nil, err
+	}
+	return u.withParts(ctx, series, userID, role)
+}
+
+func (u *usecase) GetNav(ctx context.Context, post *postdomain.Post, viewerID, role string) (*postdomain.SeriesNav, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	series, err := u.repo.FindByPost(ctx, post.ID)
+	if err != nil {
+		if errors.Is(err, errs.ErrSeriesNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	parts, err := u.repo.ListParts(ctx, series.ID)
+	if err != nil {
+		return nil, err
+	}
+	parts = visibleParts(parts, canSeeDrafts(series, viewerID, role))
+
+	nav := &postdomain.SeriesNav{ID: series.ID, Title: series.Title, Total: len(parts)}
+	for i, part := range parts {
+		if part.PostID != post.ID {
+			continue
+		}
+		nav.Position = i + 1
+		if i > 0 {
+			nav.Previous = &postdomain.SeriesLink{ID: parts[i-1].PostID, Title: parts[i-1].Title}
+		}
+		if i < len(parts)-1 {
+			nav.Next = &postdomain.SeriesLink{ID: parts[i+1].PostID, Title: parts[i+1].Title}
+		}
+	}
+	return nav, nil
+}
+
+// ownSeries loads a series the user may change: their own, or any for admins.
+func (u *usecase) ownSeries(ctx context.Context, id, userID, role string) (*seriesdomain.Series, error) {
+	series, err := u.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if series.AuthorID != userID && role != string(userusecase.RoleAdmin) {
+		return nil, errs.ErrSeriesNotOwner
+	}
+	return series, nil
+}
+
+func (u *usecase) withParts(ctx context.Context, series *seriesdomain.Series, viewerID, role string) (*seriesdomain.Series, error) {
+	parts, err := u.repo.ListParts(ctx, series.ID)
+	if err != nil {
+		return nil, err
+	}
+	parts = visibleParts(parts, canSeeDrafts(series, viewerID, role))
+
+	ids := make([]string, 0, len(parts))
+	for _, part := range parts {
+		ids = append(ids, part.PostID)
+	}
+
+	// Parts are all by the series author, who sees their drafts through the
+	// post visibility rule as well.
+	if canSeeDrafts(series, viewerID, role) {
+		viewerID = series.AuthorID
+	}
+	posts, err := u.postUc.GetByIDs(ctx, ids, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if posts == nil {
+		posts = []*postdomain.Summary{}
+	}
+	series.Parts = posts
+	return series, nil
+}
+
+func canSeeDrafts(series *seriesdomain.Series, viewerID, role string) bool {
+	return viewerID == series.AuthorID || role == string(userusecase.RoleAdmin)
+}
+
+// visibleParts leaves out unpublished and private parts for readers other
+// than the author and admins.
+func visibleParts(parts []*seriesdomain.Part, drafts bool) []*seriesdomain.Part {
+	if drafts {
+		return parts
+	}
+
+	visible := make([]*seriesdomain.Part, 0, len(parts))
+	for _, part := range parts {
+		if part.Status == string(postusecase.StatusPublished) && part.Visibility != string(postusecase.VisibilityPrivate) {
+			visible = append(visible, part)
+		}
+	}
+	return visible
+}
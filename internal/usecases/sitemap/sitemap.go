@@ -0,0 +1,86 @@
+package sitemapusecase
+
+import (
+	"context"
+
+	sitemapdomain "github.com/codepnw/blog-api/internal/domains/sitemap"
+	sitemaprepo "github.com/codepnw/blog-api/internal/repositories/sitemap"
+	"github.com/codepnw/blog-api/internal/usecases"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+const (
+	// URLsPerFile is the most URLs the sitemap protocol allows in one file.
+	URLsPerFile   = 50000
+	syncBatchSize = 500
+)
+
+type Usecase interface {
+	// Refresh applies the posts changed since the last run to the sitemap.
+	// It is run periodically by the scheduler.
+	Refresh(ctx context.Context) error
+	// GetIndex lists the sitemap files; there is always at least one.
+	GetIndex(ctx context.Context) ([]*sitemapdomain.File, error)
+	GetFile(ctx context.Context, page int) ([]*sitemapdomain.URL, error)
+}
+
+type usecase struct {
+	repo sitemaprepo.Repository
+}
+
+func NewSitemapUsecase(repo sitemaprepo.Repository) Usecase {
+	return &usecase{repo: repo}
+}
+
+func (u *usecase) Refresh(ctx context.Context) error {
+	// Keep going until caught up, so the first run after a deploy doesn't
+	// take one interval per batch.
+	for {
+		n, err := u.syncBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if n < syncBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (u *usecase) syncBatch(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return u.repo.SyncPosts(ctx, syncBatchSize)
+}
+
+func (u *usecase) GetIndex(ctx context.Context) ([]*sitemapdomain.File, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	files, err := u.repo.ListFiles(ctx, URLsPerFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		files = []*sitemapdomain.File{{Page: 1}}
+	}
+	return files, nil
+}
+
+func (u *usecase) GetFile(ctx context.Context, page int) ([]*sitemapdomain.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	if page < 1 {
+		return nil, errs.ErrSitemapNotFound
+	}
+	urls, err := u.repo.ListURLs(ctx, page, URLsPerFile)
+	if err != nil {
+		return nil, err
+	}
+	// The first file exists, empty, before anything is published.
+	if len(urls) == 0 && page > 1 {
+		return nil, errs.ErrSitemapNotFound
+	}
+	return urls, nil
+}
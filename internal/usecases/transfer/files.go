@@ -0,0 +1,168 @@
+package transferusecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	transferdomain "github.com/codepnw/blog-api/internal/domains/transfer"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+)
+
+const (
+	MaxFiles    = 5000
+	MaxFileSize = 5 << 20
+)
+
+// IsMarkdown reports whether name looks like a Markdown file worth
+// importing. Hidden files and macOS resource forks are left out.
+func IsMarkdown(name string) bool {
+	base := path.Base(filepath.ToSlash(name))
+	if strings.HasPrefix(base, ".") || strings.Contains(filepath.ToSlash(name), "__MACOSX/") {
+		return false
+	}
+	switch strings.ToLower(path.Ext(base)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// ReadZip returns the Markdown files in a ZIP archive.
+func ReadZip(data []byte) ([]*transferdomain.File, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errs.ErrImportUnsupported
+	}
+
+	var files []*transferdomain.File
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() || !IsMarkdown(entry.Name) {
+			continue
+		}
+		if len(files) == MaxFiles {
+			return nil, errs.ErrImportTooManyFiles
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		// The declared size can't be trusted, so the read is capped.
+		data, err := readLimited(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &transferdomain.File{Name: entry.Name, Data: data})
+	}
+	return files, nil
+}
+
+// ReadDir returns the Markdown files under dir, named by their path relative
+// to it.
+func ReadDir(dir string) ([]*transferdomain.File, error) {
+	var files []*transferdomain.File
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !IsMarkdown(p) {
+			return nil
+		}
+		if len(files) == MaxFiles {
+			return errs.ErrImportTooManyFiles
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		data, err := readLimited(f)
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, &transferdomain.File{Name: filepath.ToSlash(name), Data: data})
+		return nil
+	})
+	return files, err
+}
+
+// WriteZip writes files into a ZIP archive.
+func WriteZip(w io.Writer, files []*transferdomain.File) error {
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		fw, err := archive.Create(file.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(file.Data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// WriteDir writes files into dir, creating it when needed.
+func WriteDir(dir string, files []*transferdomain.File) error {
+	for _, file := range files {
+		p := filepath.Join(dir, filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p, file.Data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Slugify lowercases s and joins its runs of letters and digits with dashes.
+// Combining marks are kept, since scripts such as Thai need them.
+func Slugify(s string) string {
+	var (
+		sb   strings.Builder
+		dash bool
+	)
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return sb.String()
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxFileSize {
+		return nil, errs.ErrImportFileTooLarge
+	}
+	return data, nil
+}
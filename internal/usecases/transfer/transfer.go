@@ -0,0 +1,318 @@
+package transferusecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	transferdomain "github.com/codepnw/blog-api/internal/domains/transfer"
+	categoryusecase "github.com/codepnw/blog-api/internal/usecases/category"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/frontmatter"
+	"github.com/codepnw/blog-api/internal/utils/render"
+)
+
+const (
+	ResultCreated     = "created"
+	ResultWouldCreate = "would_create"
+	ResultExists      = "exists"
+	ResultFailed      = "failed"
+
+	maxTitleLength = 200
+	maxSlugLength  = 200
+	maxTagLength   = 100
+)
+
+type Usecase interface {
+	// Import creates a post from every Markdown file, skipping the ones
+	// whose slug is taken, so an interrupted import can simply be re-run.
+	// Problems with a file are reported for that file only.
+	Import(ctx context.Context, files []*transferdomain.File, opts transferdomain.ImportOptions) (*transferdomain.ImportReport, error)
+	// Export writes every post outside the trash in the format Import reads.
+	Export(ctx context.Context) ([]*transferdomain.File, error)
+}
+
+type usecase struct {
+	postUc     postusecase.Usecase
+	categoryUc categoryusecase.Usecase
+	userUc     userusecase.Usecase
+}
+
+func NewTransferUsecase(postUc postusecase.Usecase, categoryUc categoryusecase.Usecase, userUc userusecase.Usecase) Usecase {
+	return &usecase{
+		postUc:     postUc,
+		categoryUc: categoryUc,
+		userUc:     userUc,
+	}
+}
+
+// importer holds the lookups shared by the files of one import.
+type importer struct {
+	*usecase
+	opts       transferdomain.ImportOptions
+	categories map[string]string
+	authors    map[string]string
+	slugs      map[string]string
+}
+
+func (u *usecase) Import(ctx context.Context, files []*transferdomain.File, opts transferdomain.ImportOptions) (*transferdomain.ImportReport, error) {
+	categories, err := u.categoryUc.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &importer{
+		usecase:    u,
+		opts:       opts,
+		categories: make(map[string]string, len(categories)),
+		authors:    make(map[string]string),
+		slugs:      make(map[string]string),
+	}
+	for _, category := range categories {
+		imp.categories[strings.ToLower(category.Name)] = category.ID
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	report := &transferdomain.ImportReport{DryRun: opts.DryRun, Files: make([]*transferdomain.ImportItem, 0, len(files))}
+	for _, file := range files {
+		item := imp.importFile(ctx, file)
+		switch item.Result {
+		case ResultCreated, ResultWouldCreate:
+			report.Created++
+		case ResultExists:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+		report.Files = append(report.Files, item)
+	}
+	return report, nil
+}
+
+func (imp *importer) importFile(ctx context.Context, file *transferdomain.File) *transferdomain.ImportItem {
+	item := &transferdomain.ImportItem{File: file.Name}
+	fail := func(err error) *transferdomain.ImportItem {
+		item.Result, item.Error = ResultFailed, err.Error()
+		return item
+	}
+
+	var fm transferdomain.FrontMatter
+	body, err := frontmatter.Parse(file.Data, &fm)
+	if err != nil {
+		return fail(fmt.Errorf("invalid front matter: %w", err))
+	}
+
+	item.Title = strings.TrimSpace(fm.Title)
+	if item.Title == "" {
+		return fail(errs.ErrImportTitleRequired)
+	}
+
+	// Files without a slug are keyed by their name, which keeps re-imports
+	// of the same tree idempotent.
+	slug := fm.Slug
+	if slug == "" {
+		slug = strings.TrimSuffix(path.Base(file.Name), path.Ext(file.Name))
+	}
+	item.Slug = Slugify(slug)
+	if item.Slug == "" {
+		return fail(errs.ErrImportInvalidSlug)
+	}
+	if other, ok := imp.slugs[item.Slug]; ok {
+		return fail(fmt.Errorf("%w: %s", errs.ErrImportDuplicateSlug, other))
+	}
+	imp.slugs[item.Slug] = file.Name
+
+	tags, err := cleanTags(fm.Tags)
+	if err != nil {
+		return fail(err)
+	}
+	if utf8.RuneCountInString(item.Title) > maxTitleLength || utf8.RuneCountInString(item.Slug) > maxSlugLength {
+		return fail(errs.ErrImportTooLong)
+	}
+
+	authorID, err := imp.authorID(ctx, fm.Author)
+	if err != nil {
+		return fail(err)
+	}
+
+	var categoryID string
+	if fm.Category != "" {
+		id, ok := imp.categories[strings.ToLower(strings.TrimSpace(fm.Category))]
+		if !ok {
+			return fail(fmt.Errorf("%w: %s", errs.ErrCategoryNotFound, fm.Category))
+		}
+		categoryID = id
+	}
+
+	id, err := imp.postUc.GetIDBySlug(ctx, item.Slug)
+	switch {
+	case err == nil:
+		item.Result, item.PostID = ResultExists, id
+		return item
+	case !errors.Is(err, errs.ErrPostNotFound):
+		return fail(err)
+	}
+
+	item.PostStatus = importStatus(&fm)
+	if imp.opts.DryRun {
+		item.Result = ResultWouldCreate
+		return item
+	}
+
+	post, err := imp.postUc.Import(ctx, &postdomain.Post{
+		AuthorID:      authorID,
+		Title:         item.Title,
+		Slug:          &item.Slug,
+		Content:       string(body),
+		ContentFormat: render.FormatMarkdown,
+		CategoryID:    &categoryID,
+		Tags:          tags,
+		Status:        item.PostStatus,
+		PublishedAt:   fm.Date,
+	})
+	if err != nil {
+		return fail(err)
+	}
+	item.Result, item.PostID = ResultCreated, post.ID
+	return item
+}
+
+// authorID resolves the email in the front matter, falling back to the
+// import's default author.
+func (imp *importer) authorID(ctx context.Context, email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		if imp.opts.AuthorID == "" {
+			return "", errs.ErrImportAuthorRequired
+		}
+		return imp.opts.AuthorID, nil
+	}
+
+	if id, ok := imp.authors[email]; ok {
+		return id, nil
+	}
+	user, err := imp.userUc.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, errs.ErrUserNotFound) {
+			return "", fmt.Errorf("%w: %s", err, email)
+		}
+		return "", err
+	}
+	imp.authors[email] = user.ID
+	return user.ID, nil
+}
+
+// importStatus is draft for drafts, scheduled for dates still ahead and
+// published otherwise.
+func importStatus(fm *transferdomain.FrontMatter) string {
+	switch {
+	case fm.Draft:
+		return string(postusecase.StatusDraft)
+	case fm.Date != nil && fm.Date.After(time.Now()):
+		return string(postusecase.StatusScheduled)
+	default:
+		return string(postusecase.StatusPublished)
+	}
+}
+
+// cleanTags trims the tags and drops empty ones and repeats.
+func cleanTags(tags []string) ([]string, error) {
+	var (
+		out  = make([]string, 0, len(tags))
+		seen = make(map[string]bool, len(tags))
+	)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, errs.ErrImportTooLong
+		}
+		seen[key] = true
+		out = append(out, tag)
+	}
+	return out, nil
+}
+
+func (u *usecase) Export(ctx context.Context) ([]*transferdomain.File, error) {
+	posts, err := u.postUc.GetAllPosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := u.categoryUc.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	categoryNames := make(map[string]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	var (
+		emails = make(map[string]string)
+		names  = make(map[string]int)
+		files  = make([]*transferdomain.File, 0, len(posts))
+	)
+	for _, post := range posts {
+		email, ok := emails[post.AuthorID]
+		if !ok {
+			user, err := u.userUc.GetUser(ctx, post.AuthorID)
+			if err != nil {
+				return nil, err
+			}
+			email = user.Email
+			emails[post.AuthorID] = email
+		}
+
+		// Archived posts have no equivalent and come back as drafts.
+		fm := transferdomain.FrontMatter{
+			Title:  post.Title,
+			Date:   post.PublishedAt,
+			Tags:   post.Tags,
+			Author: email,
+			Draft:  post.Status == string(postusecase.StatusDraft) || post.Status == string(postusecase.StatusArchived),
+		}
+		if post.Slug != nil {
+			fm.Slug = *post.Slug
+		}
+		if post.CategoryID != nil {
+			fm.Category = categoryNames[*post.CategoryID]
+		}
+
+		data, err := frontmatter.Format(&fm, []byte(post.Content))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &transferdomain.File{Name: exportName(&fm, post.ID, names), Data: data})
+	}
+	return files, nil
+}
+
+// exportName names the file after the slug, or the title for posts created
+// through the API, numbering repeats.
+func exportName(fm *transferdomain.FrontMatter, postID string, used map[string]int) string {
+	name := fm.Slug
+	if name == "" {
+		name = Slugify(fm.Title)
+	}
+	if name == "" {
+		name = postID
+	}
+
+	used[name]++
+	if n := used[name]; n > 1 {
+		name = fmt.Sprintf("%s-%d", name, n)
+	}
+	return name + ".md"
+}
@@ -15,16 +15,18 @@ import (
 type userRole string
 
 const (
-	RoleAdmin userRole = "admin"
-	RoleUser  userRole = "user"
+	RoleAdmin  userRole = "admin"
+	RoleEditor userRole = "editor"
+	RoleUser   userRole = "user"
 )
 
 type Usecase interface {
 	CreateUser(ctx context.Context, input *userdomain.User) (*userdomain.User, error)
 	GetUser(ctx context.Context, id string) (*userdomain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*userdomain.User, error)
 	GetAllUsers(ctx context.Context) ([]*userdomain.User, error)
 	UpdateUser(ctx context.Context, input *userdomain.User) (*userdomain.User, error)
-	DeleteUser(ctx context.Context, id string) error
+	DeleteUser(ctx context.Context, id string, version int) error
 
 	// Auth
 	Register(ctx context.Context, input *userdomain.User) (*AuthResponse, error)
@@ -73,6 +75,13 @@ func (u *usecase) GetUser(ctx context.Context, id string) (*userdomain.User, err
 	return u.repo.FindByID(ctx, id)
 }
 
+func (u *usecase) GetUserByEmail(ctx context.Context, email string) (*userdomain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return u.repo.FindByEmail(ctx, email)
+}
+
 func (u *usecase) UpdateUser(ctx context.Context, input *userdomain.User) (*userdomain.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
 	defer cancel()
@@ -90,11 +99,11 @@ func (u *usecase) UpdateUser(ctx context.Context, input *userdomain.User) (*user
 	return u.repo.Update(ctx, input)
 }
 
-func (u *usecase) DeleteUser(ctx context.Context, id string) error {
+func (u *usecase) DeleteUser(ctx context.Context, id string, version int) error {
 	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
 	defer cancel()
 
-	return u.repo.Delete(ctx, id)
+	return u.repo.Delete(ctx, id, version)
 }
 
 //  ------- Auth -----------
@@ -0,0 +1,590 @@
+package wordpressusecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	categorydomain "github.com/codepnw/blog-api/internal/domains/category"
+	postdomain "github.com/codepnw/blog-api/internal/domains/post"
+	userdomain "github.com/codepnw/blog-api/internal/domains/user"
+	wordpressdomain "github.com/codepnw/blog-api/internal/domains/wordpress"
+	wordpressrepo "github.com/codepnw/blog-api/internal/repositories/wordpress"
+	"github.com/codepnw/blog-api/internal/usecases"
+	categoryusecase "github.com/codepnw/blog-api/internal/usecases/category"
+	postusecase "github.com/codepnw/blog-api/internal/usecases/post"
+	redirectusecase "github.com/codepnw/blog-api/internal/usecases/redirect"
+	transferusecase "github.com/codepnw/blog-api/internal/usecases/transfer"
+	userusecase "github.com/codepnw/blog-api/internal/usecases/user"
+	"github.com/codepnw/blog-api/internal/utils/errs"
+	"github.com/codepnw/blog-api/internal/utils/logger"
+	"github.com/codepnw/blog-api/internal/utils/render"
+	"github.com/codepnw/blog-api/internal/utils/wxr"
+)
+
+const (
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	// JobInterrupted is reported for a running job that stopped making
+	// progress, because the instance running it went away.
+	JobInterrupted = "interrupted"
+
+	// staleAfter is how long a running job may go without saving progress
+	// before it is reported as interrupted.
+	staleAfter    = 2 * time.Minute
+	saveInterval  = time.Second
+	maxJobErrors  = 100
+	maxNameLength = 100
+	maxDescLength = 200
+
+	untitled = "Untitled"
+)
+
+type Usecase interface {
+	// Start creates the job and imports the export in the background. Running
+	// it again for the same site skips everything the earlier runs created,
+	// so an interrupted import can simply be started again. Posts whose
+	// author has no account here belong to adminID.
+	Start(ctx context.Context, export *wxr.Export, adminID string) (*wordpressdomain.ImportJob, error)
+	GetJob(ctx context.Context, id string) (*wordpressdomain.ImportJob, error)
+}
+
+type usecase struct {
+	// background outlives the request that starts a job and is cancelled
+	// when the server shuts down.
+	background context.Context
+	repo       wordpressrepo.Repository
+	postUc     postusecase.Usecase
+	categoryUc categoryusecase.Usecase
+	userUc     userusecase.Usecase
+	redirectUc redirectusecase.Usecase
+}
+
+func NewWordPressUsecase(
+	background context.Context,
+	repo wordpressrepo.Repository,
+	postUc postusecase.Usecase,
+	categoryUc categoryusecase.Usecase,
+	userUc userusecase.Usecase,
+	redirectUc redirectusecase.Usecase,
+) Usecase {
+	return &usecase{
+		background: background,
+		repo:       repo,
+		postUc:     postUc,
+		categoryUc: categoryUc,
+		userUc:     userUc,
+		redirectUc: redirectUc,
+	}
+}
+
+func (u *usecase) Start(ctx context.Context, export *wxr.Export, adminID string) (*wordpressdomain.ImportJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	posts := importablePosts(export)
+	job, err := u.repo.InsertJob(ctx, &wordpressdomain.ImportJob{
+		Site:      export.Site(),
+		Total:     len(export.Authors) + len(export.Categories) + len(export.Tags) + len(posts),
+		CreatedBy: &adminID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &importer{
+		usecase:    u,
+		job:        job,
+		site:       job.Site,
+		adminID:    adminID,
+		users:      make(map[string]string),
+		logins:     make(map[string]string),
+		wpUsers:    make(map[string]string),
+		categories: make(map[string]string),
+	}
+	go imp.run(u.background, export, posts)
+
+	return job, nil
+}
+
+func (u *usecase) GetJob(ctx context.Context, id string) (*wordpressdomain.ImportJob, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	job, err := u.repo.FindJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == JobRunning && time.Since(job.UpdatedAt) > staleAfter {
+		job.Status = JobInterrupted
+	}
+	return job, nil
+}
+
+// importablePosts leaves out pages, attachments and other post types, and
+// the posts WordPress never showed.
+func importablePosts(export *wxr.Export) []*wxr.Item {
+	var posts []*wxr.Item
+	for i := range export.Items {
+		item := &export.Items[i]
+		if item.Type != "post" {
+			continue
+		}
+		switch item.Status {
+		case "trash", "auto-draft", "inherit":
+			continue
+		}
+		posts = append(posts, item)
+	}
+	return posts
+}
+
+// importer is one run of a job. Its lookups are keyed by lowercased email,
+// WordPress login, WordPress user ID and lowercased category name.
+type importer struct {
+	*usecase
+	job     *wordpressdomain.ImportJob
+	site    string
+	adminID string
+	savedAt time.Time
+
+	users      map[string]string
+	logins     map[string]string
+	wpUsers    map[string]string
+	categories map[string]string
+}
+
+func (imp *importer) run(ctx context.Context, export *wxr.Export, posts []*wxr.Item) {
+	imp.job.Status = JobRunning
+	err := imp.importAll(ctx, export, posts)
+
+	now := time.Now()
+	imp.job.FinishedAt = &now
+	imp.job.Status = JobCompleted
+	if err != nil {
+		logger.Error("usecase.WordPressImport: import", "job_id", imp.job.ID, "error", err)
+		imp.job.Status, imp.job.Error = JobFailed, err.Error()
+	}
+
+	// The job's own context may be gone by now, and the outcome must still
+	// be recorded.
+	saveCtx, cancel := context.WithTimeout(context.Background(), usecases.ContextTimeout)
+	defer cancel()
+	if err := imp.repo.UpdateJob(saveCtx, imp.job); err != nil {
+		logger.Error("usecase.WordPressImport: save job", "job_id", imp.job.ID, "error", err)
+	}
+}
+
+func (imp *importer) importAll(ctx context.Context, export *wxr.Export, posts []*wxr.Item) error {
+	steps := []func(context.Context, *wxr.Export, []*wxr.Item) error{
+		imp.importUsers,
+		imp.importCategories,
+		imp.importTags,
+		imp.importPosts,
+	}
+	for _, step := range steps {
+		if err := step(ctx, export, posts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (imp *importer) importUsers(ctx context.Context, export *wxr.Export, _ []*wxr.Item) error {
+	existing, err := imp.userUc.GetAllUsers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, user := range existing {
+		imp.users[strings.ToLower(user.Email)] = user.ID
+	}
+
+	for _, author := range export.Authors {
+		first, last := author.FirstName, author.LastName
+		if strings.TrimSpace(first+last) == "" {
+			first, last = splitName(author.DisplayName, author.Login)
+		}
+
+		count := &imp.job.Counts.Users
+		id, created, err := imp.userID(ctx, author.Email, first, last)
+		switch {
+		case err != nil:
+			imp.fail(count, "author "+author.Login, err)
+		case created:
+			count.Created++
+		default:
+			count.Skipped++
+		}
+		if id != "" {
+			imp.logins[author.Login] = id
+			imp.wpUsers[author.ID] = id
+		}
+		if err := imp.step(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userID finds the account with email, creating one when there is none.
+// The new account gets a random password, so nobody can sign in with it
+// until one is set. An empty email returns an empty ID.
+func (imp *importer) userID(ctx context.Context, email, first, last string) (string, bool, error) {
+	email = strings.TrimSpace(email)
+	key := strings.ToLower(email)
+	if key == "" {
+		return "", false, nil
+	}
+	if id, ok := imp.users[key]; ok {
+		return id, false, nil
+	}
+	if utf8.RuneCountInString(email) > maxNameLength {
+		return "", false, errs.ErrImportTooLong
+	}
+
+	password := make([]byte, 24)
+	if _, err := rand.Read(password); err != nil {
+		return "", false, err
+	}
+	user, err := imp.userUc.CreateUser(ctx, &userdomain.User{
+		FirstName:    truncate(first, maxNameLength),
+		LastName:     truncate(last, maxNameLength),
+		Email:        email,
+		PasswordHash: hex.EncodeToString(password),
+	})
+	if err != nil {
+		return "", false, err
+	}
+	imp.users[key] = user.ID
+	return user.ID, true, nil
+}
+
+func (imp *importer) importCategories(ctx context.Context, export *wxr.Export, posts []*wxr.Item) error {
+	existing, err := imp.categoryUc.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, category := range existing {
+		imp.categories[strings.ToLower(category.Name)] = category.ID
+	}
+
+	for _, category := range export.Categories {
+		imp.category(ctx, strings.TrimSpace(category.Name), strings.TrimSpace(category.Description))
+		if err := imp.step(ctx); err != nil {
+			return err
+		}
+	}
+	// Posts can use categories the export doesn't list.
+	for _, post := range posts {
+		for _, name := range post.TermNames("category") {
+			if _, ok := imp.categories[strings.ToLower(name)]; !ok {
+				imp.category(ctx, name, "")
+			}
+		}
+	}
+	return nil
+}
+
+func (imp *importer) category(ctx context.Context, name, description string) {
+	count := &imp.job.Counts.Categories
+	key := strings.ToLower(name)
+	if _, ok := imp.categories[key]; ok || key == "" {
+		count.Skipped++
+		return
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		imp.fail(count, "category "+name, errs.ErrImportTooLong)
+		return
+	}
+
+	input := &categorydomain.Category{Name: name, Description: truncate(description, maxDescLength)}
+	if err := imp.categoryUc.Create(ctx, input); err != nil {
+		imp.fail(count, "category "+name, err)
+		return
+	}
+	count.Created++
+	imp.categories[key] = input.ID
+}
+
+func (imp *importer) importTags(ctx context.Context, export *wxr.Export, posts []*wxr.Item) error {
+	var (
+		names []string
+		seen  = make(map[string]bool)
+	)
+	add := func(name string) {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		if utf8.RuneCountInString(name) > maxNameLength {
+			imp.fail(&imp.job.Counts.Tags, "tag "+name, errs.ErrImportTooLong)
+			return
+		}
+		names = append(names, name)
+	}
+	for _, tag := range export.Tags {
+		add(tag.Name)
+	}
+	for _, post := range posts {
+		for _, name := range post.TermNames("post_tag") {
+			add(name)
+		}
+	}
+
+	tagCtx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+	created, err := imp.repo.InsertTags(tagCtx, names)
+	if err != nil {
+		return err
+	}
+	imp.job.Counts.Tags.Created += int(created)
+	imp.job.Counts.Tags.Skipped += len(names) - int(created)
+
+	imp.job.Processed += len(export.Tags)
+	return imp.save(ctx, false)
+}
+
+func (imp *importer) importPosts(ctx context.Context, _ *wxr.Export, posts []*wxr.Item) error {
+	for _, item := range posts {
+		postID, err := imp.post(ctx, item)
+		if err != nil {
+			imp.fail(&imp.job.Counts.Posts, "post "+item.ID, err)
+		} else {
+			imp.comments(ctx, item, postID)
+			imp.redirects(ctx, item, postID)
+		}
+		if err := imp.step(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// post returns the ID of the post the item became, creating it unless an
+// earlier run did or a post already has its slug.
+func (imp *importer) post(ctx context.Context, item *wxr.Item) (string, error) {
+	count := &imp.job.Counts.Posts
+	wpID, err := strconv.ParseInt(item.ID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid post id %q", item.ID)
+	}
+
+	if id, err := imp.findRef(ctx, wpID); err != nil || id != "" {
+		if id != "" {
+			count.Skipped++
+		}
+		return id, err
+	}
+
+	var slug *string
+	if s := transferusecase.Slugify(item.Slug()); s != "" {
+		if utf8.RuneCountInString(s) > maxDescLength {
+			return "", errs.ErrImportTooLong
+		}
+		id, err := imp.postUc.GetIDBySlug(ctx, s)
+		switch {
+		case err == nil:
+			count.Skipped++
+			return id, imp.saveRef(ctx, wpID, id)
+		case !errors.Is(err, errs.ErrPostNotFound):
+			return "", err
+		}
+		slug = &s
+	}
+
+	title := strings.TrimSpace(item.Title)
+	if title == "" {
+		title = untitled
+	}
+	if utf8.RuneCountInString(title) > maxDescLength {
+		return "", errs.ErrImportTooLong
+	}
+
+	input := &postdomain.Post{
+		AuthorID:      imp.authorID(item),
+		Title:         title,
+		Slug:          slug,
+		Content:       wxr.ContentHTML(item.Content),
+		ContentFormat: render.FormatHTML,
+		Tags:          item.TermNames("post_tag"),
+	}
+	if names := item.TermNames("category"); len(names) > 0 {
+		id := imp.categories[strings.ToLower(names[0])]
+		input.CategoryID = &id
+	}
+	setStatus(input, item)
+
+	post, err := imp.postUc.Import(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	count.Created++
+	return post.ID, imp.saveRef(ctx, wpID, post.ID)
+}
+
+func (imp *importer) findRef(ctx context.Context, wpID int64) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return imp.repo.FindRef(ctx, imp.site, wordpressrepo.RefPost, wpID)
+}
+
+func (imp *importer) saveRef(ctx context.Context, wpID int64, postID string) error {
+	ctx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+
+	return imp.repo.SaveRef(ctx, imp.site, wordpressrepo.RefPost, wpID, postID)
+}
+
+// authorID maps the item's WordPress login, falling back to the admin who
+// started the import.
+func (imp *importer) authorID(item *wxr.Item) string {
+	if id, ok := imp.logins[item.Creator]; ok {
+		return id
+	}
+	return imp.adminID
+}
+
+// setStatus maps the WordPress status. Pending and private posts become
+// drafts, which only their authors and admins can see.
+func setStatus(input *postdomain.Post, item *wxr.Item) {
+	published := item.Published()
+	switch item.Status {
+	case "publish", "future":
+		input.Status = string(postusecase.StatusPublished)
+		if !published.IsZero() {
+			input.PublishedAt = &published
+		}
+		if published.After(time.Now()) {
+			input.Status = string(postusecase.StatusScheduled)
+		}
+	default:
+		input.Status = string(postusecase.StatusDraft)
+	}
+}
+
+// comments imports the approved comments of the item. Pending, spam and
+// trashed comments, pingbacks, and comments without an email are skipped.
+func (imp *importer) comments(ctx context.Context, item *wxr.Item, postID string) {
+	count := &imp.job.Counts.Comments
+	for _, c := range item.Comments {
+		content := wxr.CommentText(c.Content)
+		if c.Approved != "1" || (c.Type != "" && c.Type != "comment") || content == "" {
+			count.Skipped++
+			continue
+		}
+		wpID, err := strconv.ParseInt(c.ID, 10, 64)
+		if err != nil {
+			imp.fail(count, "comment "+c.ID, fmt.Errorf("invalid comment id %q", c.ID))
+			continue
+		}
+
+		// Commenters who aren't authors get an account of their own.
+		userID, ok := imp.wpUsers[c.UserID]
+		if !ok {
+			var created bool
+			first, last := splitName(c.Author, "")
+			userID, created, err = imp.userID(ctx, c.AuthorEmail, first, last)
+			if err != nil {
+				imp.fail(count, "comment "+c.ID, err)
+				continue
+			}
+			if created {
+				imp.job.Counts.Users.Created++
+			}
+		}
+		if userID == "" {
+			count.Skipped++
+			continue
+		}
+
+		created := c.Created()
+		if created.IsZero() {
+			created = time.Now()
+		}
+		input := &wordpressdomain.Comment{WPID: wpID, PostID: postID, UserID: userID, Content: content, CreatedAt: created}
+
+		commentCtx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+		inserted, err := imp.repo.InsertComment(commentCtx, imp.site, input)
+		cancel()
+		switch {
+		case err != nil:
+			imp.fail(count, "comment "+c.ID, err)
+		case inserted:
+			count.Created++
+		default:
+			count.Skipped++
+		}
+	}
+}
+
+func (imp *importer) redirects(ctx context.Context, item *wxr.Item, postID string) {
+	count := &imp.job.Counts.Redirects
+	paths := item.Permalinks()
+	created, err := imp.redirectUc.Add(ctx, postID, paths)
+	if err != nil {
+		imp.fail(count, "redirects of post "+item.ID, err)
+		return
+	}
+	count.Created += created
+	count.Skipped += len(paths) - created
+}
+
+// step counts one processed item and saves the progress now and then. It
+// stops the import when the server shuts down.
+func (imp *importer) step(ctx context.Context) error {
+	imp.job.Processed++
+	return imp.save(ctx, true)
+}
+
+func (imp *importer) save(ctx context.Context, throttle bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if throttle && time.Since(imp.savedAt) < saveInterval {
+		return nil
+	}
+
+	saveCtx, cancel := context.WithTimeout(ctx, usecases.ContextTimeout)
+	defer cancel()
+	if err := imp.repo.UpdateJob(saveCtx, imp.job); err != nil {
+		return err
+	}
+	imp.savedAt = time.Now()
+	return nil
+}
+
+// fail counts a failed item and keeps the first errors for the report.
+func (imp *importer) fail(count *wordpressdomain.ImportCount, item string, err error) {
+	count.Failed++
+	if len(imp.job.Errors) < maxJobErrors {
+		imp.job.Errors = append(imp.job.Errors, wordpressdomain.ImportError{Item: item, Error: err.Error()})
+	}
+}
+
+// splitName splits a display name into a first and last name, using
+// fallback when it is empty.
+func splitName(name, fallback string) (string, string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = fallback
+	}
+	first, last, _ := strings.Cut(name, " ")
+	return first, strings.TrimSpace(last)
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,208 @@
+package diff
+
+import (
+	"strings"
+	"unicode"
+)
+
+type OpType string
+
+const (
+	OpEqual  OpType = "equal"
+	OpInsert OpType = "insert"
+	OpDelete OpType = "delete"
+)
+
+type Op struct {
+	Type OpType `json:"type"`
+	Text string `json:"text"`
+}
+
+// Lines diffs a and b line by line.
+func Lines(a, b string) []Op {
+	return compute(splitLines(a), splitLines(b))
+}
+
+// Words diffs a and b word by word. Whitespace runs are kept as their own
+// tokens so that joining the ops gives back the original text.
+func Words(a, b string) []Op {
+	return compute(splitWords(a), splitWords(b))
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.SplitAfter(s, "\n")
+}
+
+func splitWords(s string) []string {
+	var (
+		tokens []string
+		start  int
+		space  bool
+	)
+	for i, r := range s {
+		isSpace := unicode.IsSpace(r)
+		if i > start && isSpace != space {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		space = isSpace
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+// maxTokens bounds the tokens left to diff once the common prefix and
+// suffix are trimmed. Beyond it the changed middle is shown as replaced
+// outright, as the time to diff grows with its size times the number of
+// edits.
+const maxTokens = 10000
+
+func compute(a, b []string) []Op {
+	// Common prefix and suffix don't need the full algorithm.
+	prefix := commonPrefix(a, b)
+	suffix := commonSuffix(a[prefix:], b[prefix:])
+
+	var ops []Op
+	ops = appendOp(ops, OpEqual, a[:prefix]...)
+	a, b, tail := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], a[len(a)-suffix:]
+	if len(a)+len(b) > maxTokens {
+		ops = appendOp(ops, OpDelete, a...)
+		ops = appendOp(ops, OpInsert, b...)
+	} else {
+		ops = myers(a, b, ops)
+	}
+	ops = appendOp(ops, OpEqual, tail...)
+	return merge(ops)
+}
+
+func commonPrefix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func commonSuffix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
+
+// myers appends the shortest edit script turning a into b, using the linear
+// space variant from "An O(ND) Difference Algorithm and Its Variations"
+// (Myers, 1986): find the middle snake of an optimal path, then solve both
+// halves the same way.
+func myers(a, b []string, ops []Op) []Op {
+	size := 2*((len(a)+len(b)+1)/2) + 3
+	return divide(a, b, make([]int, size), make([]int, size), ops)
+}
+
+func divide(a, b []string, vf, vb []int, ops []Op) []Op {
+	prefix := commonPrefix(a, b)
+	ops = appendOp(ops, OpEqual, a[:prefix]...)
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := commonSuffix(a, b)
+	a, b, tail := a[:len(a)-suffix], b[:len(b)-suffix], a[len(a)-suffix:]
+
+	switch {
+	case len(a) == 0:
+		ops = appendOp(ops, OpInsert, b...)
+	case len(b) == 0:
+		ops = appendOp(ops, OpDelete, a...)
+	default:
+		// Both halves hold fewer edits than the whole, so this ends.
+		x, y, u, v := middleSnake(a, b, vf, vb)
+		ops = divide(a[:x], b[:y], vf, vb, ops)
+		ops = appendOp(ops, OpEqual, a[x:u]...)
+		ops = divide(a[u:], b[v:], vf, vb, ops)
+	}
+	return appendOp(ops, OpEqual, tail...)
+}
+
+// middleSnake runs the greedy search from both ends at once and returns the
+// snake from (x, y) to (u, v) where the two meet. vf and vb hold the
+// furthest x reached on each diagonal, from the start and from the end.
+func middleSnake(a, b []string, vf, vb []int) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	max := (n + m + 1) / 2
+	off := max + 1
+	vf[off+1], vb[off+1] = 0, 0
+
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := next(vf, off, k, d)
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			vf[off+k] = x
+			// Diagonal k is diagonal delta-k counted from the end.
+			if r := delta - k; odd && r >= -(d-1) && r <= d-1 && x+vb[off+r] >= n {
+				return sx, sy, x, y
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			x := next(vb, off, k, d)
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			vb[off+k] = x
+			if f := delta - k; !odd && f >= -d && f <= d && x+vf[off+f] >= n {
+				return n - x, m - y, n - sx, m - sy
+			}
+		}
+	}
+	// Unreachable, as the searches meet by the time d reaches max. Deleting
+	// all of a and inserting all of b is still a valid script.
+	return n, 0, n, 0
+}
+
+// next is the furthest x diagonal k can start from at step d: one down from
+// diagonal k+1, or one right from diagonal k-1.
+func next(v []int, off, k, d int) int {
+	if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+		return v[off+k+1]
+	}
+	return v[off+k-1] + 1
+}
+
+func appendOp(ops []Op, typ OpType, tokens ...string) []Op {
+	for _, t := range tokens {
+		ops = append(ops, Op{Type: typ, Text: t})
+	}
+	return ops
+}
+
+// merge joins neighbouring ops of the same type.
+func merge(ops []Op) []Op {
+	var out []Op
+	for i := 0; i < len(ops); {
+		j := i + 1
+		for j < len(ops) && ops[j].Type == ops[i].Type {
+			j++
+		}
+		var sb strings.Builder
+		for _, op := range ops[i:j] {
+			sb.WriteString(op.Text)
+		}
+		out = append(out, Op{Type: ops[i].Type, Text: sb.String()})
+		i = j
+	}
+	return out
+}
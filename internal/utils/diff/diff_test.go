@@ -0,0 +1,83 @@
+package diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// apply rebuilds both sides of a diff from its ops.
+func apply(ops []Op) (a, b string) {
+	var sa, sb strings.Builder
+	for _, op := range ops {
+		if op.Type != OpInsert {
+			sa.WriteString(op.Text)
+		}
+		if op.Type != OpDelete {
+			sb.WriteString(op.Text)
+		}
+	}
+	return sa.String(), sb.String()
+}
+
+// lcs is the length of the longest common subsequence of a and b.
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestShortestEditScript(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tokens := func() []string {
+		s := make([]string, rng.Intn(30))
+		for i := range s {
+			s[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return s
+	}
+
+	for range 5000 {
+		a, b := tokens(), tokens()
+		ops := compute(a, b)
+		if gotA, gotB := apply(ops); gotA != strings.Join(a, "") || gotB != strings.Join(b, "") {
+			t.Fatalf("diff of %v and %v rebuilds %q, %q", a, b, gotA, gotB)
+		}
+
+		equal := 0
+		for _, op := range ops {
+			if op.Type == OpEqual {
+				equal += len(op.Text)
+			}
+		}
+		if want := lcs(a, b); equal != want {
+			t.Fatalf("diff of %v and %v keeps %d tokens, want %d", a, b, equal, want)
+		}
+	}
+}
+
+func TestWordsReplacesLargeRewrites(t *testing.T) {
+	a := strings.Repeat("old ", maxTokens)
+	b := "intro " + strings.Repeat("new ", maxTokens) + "end"
+
+	ops := Words(a, b)
+	if gotA, gotB := apply(ops); gotA != a || gotB != b {
+		t.Fatal("Words doesn't rebuild its input")
+	}
+	if len(ops) != 2 || ops[0].Type != OpDelete || ops[1].Type != OpInsert {
+		t.Fatalf("got %d ops, want a delete and an insert", len(ops))
+	}
+}
@@ -2,9 +2,51 @@ package errs
 
 import "errors"
 
+// Common
+var (
+	ErrVersionConflict = errors.New("resource has been modified, reload and try again")
+	ErrInvalidIfMatch  = errors.New("If-Match must be a strong entity tag")
+)
+
 // Post
 var (
-	ErrPostNotFound = errors.New("post not found")
+	ErrPostNotFound       = errors.New("post not found")
+	ErrPostInvalidStatus  = errors.New("invalid post status")
+	ErrPostScheduleInPast = errors.New("scheduled time must be in the future")
+	ErrPostInvalidFormat  = errors.New("content format must be markdown, html or plaintext")
+	ErrRevisionNotFound   = errors.New("revision not found")
+	ErrInvalidDiffMode    = errors.New("diff mode must be line or word")
+	ErrPostOwnerRequired  = errors.New("the post owner must stay among the authors")
+	ErrPostSlugTaken      = errors.New("another post already has this slug")
+	ErrPinNotFound        = errors.New("post is not pinned here")
+	ErrPinExpiryInPast    = errors.New("pin expiry must be in the future")
+)
+
+// Translation
+var (
+	ErrTranslationNotFound  = errors.New("translation not found")
+	ErrTranslationOwnLocale = errors.New("the post is already written in this locale")
+	ErrUnsupportedLocale    = errors.New("unsupported locale")
+)
+
+// Review
+var (
+	ErrPostInvalidTransition = errors.New("the post can't take this step from its current status")
+	ErrReviewRequired        = errors.New("the post must be approved by an editor before it is published")
+	ErrReviewForbidden       = errors.New("only editors and admins can review posts")
+	ErrReviewOwnPost         = errors.New("authors can't review their own posts")
+)
+
+// Lock
+var (
+	ErrLockNotFound = errors.New("post is not locked")
+	ErrPostLocked   = errors.New("another user is editing the post")
+	ErrLockNotHeld  = errors.New("only the holder of the lock or an admin can release it")
+)
+
+// Autosave
+var (
+	ErrAutosaveNotFound = errors.New("no autosave for this post")
 )
 
 // User
@@ -14,9 +56,84 @@ var (
 	ErrUserInvalid      = errors.New("invalid email or password")
 )
 
+// Category
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+// Tag
+var (
+	ErrTagNotFound = errors.New("tag not found")
+)
+
 // Comment
 var (
 	ErrCommentNotFound   = errors.New("comment not found")
 	ErrCommentIsRequired = errors.New("comment is required")
 	ErrCommentNotOwner   = errors.New("user not owner of comment")
 )
+
+// Media
+var (
+	ErrMediaNotFound        = errors.New("media not found")
+	ErrMediaNotOwner        = errors.New("user not owner of media")
+	ErrMediaTooLarge        = errors.New("file is too large")
+	ErrMediaUnsupportedType = errors.New("file must be a JPEG, PNG, GIF or WebP image")
+)
+
+// Analytics
+var (
+	ErrStatsForbidden   = errors.New("only the authors and admins can see post stats")
+	ErrInvalidDateRange = errors.New("from must be before to and the range at most 366 days")
+)
+
+// Reaction
+var (
+	ErrInvalidReaction = errors.New("unknown reaction type")
+)
+
+// Bookmark
+var (
+	ErrReadingListNotFound = errors.New("reading list not found")
+)
+
+// Series
+var (
+	ErrSeriesNotFound     = errors.New("series not found")
+	ErrSeriesNotOwner     = errors.New("user not owner of series")
+	ErrPostInSeries       = errors.New("post already belongs to a series")
+	ErrPostNotInSeries    = errors.New("post is not part of this series")
+	ErrSeriesPostNotOwned = errors.New("only the series author's posts can be added")
+	ErrInvalidSeriesOrder = errors.New("order must list every part of the series exactly once")
+)
+
+// Sitemap
+var (
+	ErrSitemapNotFound = errors.New("sitemap not found")
+)
+
+// Import
+var (
+	ErrImportTitleRequired  = errors.New("title is required")
+	ErrImportAuthorRequired = errors.New("author is required when no default author is given")
+	ErrImportInvalidSlug    = errors.New("slug must contain letters or digits")
+	ErrImportDuplicateSlug  = errors.New("another file in the import has the same slug")
+	ErrImportTooLong        = errors.New("title and slug are limited to 200 characters, tags to 100")
+	ErrImportTooManyFiles   = errors.New("an import is limited to 5000 files")
+	ErrImportFileTooLarge   = errors.New("markdown files are limited to 5 MB")
+	ErrImportUnsupported    = errors.New("upload ZIP archives or Markdown files")
+	ErrImportJobNotFound    = errors.New("import job not found")
+)
+
+// Redirect
+var (
+	ErrRedirectNotFound = errors.New("redirect not found")
+)
+
+// Preview
+var (
+	ErrPreviewLinkNotFound = errors.New("preview link not found")
+	ErrPreviewLinkInvalid  = errors.New("preview link is invalid, expired or revoked")
+	ErrPreviewForbidden    = errors.New("only the authors and admins can share previews of a post")
+	ErrPreviewExpiry       = errors.New("expires_at must be in the future and within the longest lifetime of a link")
+)
@@ -0,0 +1,64 @@
+// Package frontmatter reads and writes Markdown documents that start with a
+// YAML block between "---" lines.
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const delimiter = "---"
+
+var ErrUnterminated = errors.New("front matter is not closed with ---")
+
+// Parse decodes the front matter of doc into v and returns the body after it.
+// A document without front matter leaves v untouched and is all body.
+func Parse(doc []byte, v any) ([]byte, error) {
+	doc = bytes.TrimPrefix(doc, []byte("\ufeff"))
+	first, rest := cutLine(doc)
+	if string(bytes.TrimSpace(first)) != delimiter {
+		return doc, nil
+	}
+
+	var front []byte
+	for len(rest) > 0 {
+		var line []byte
+		line, rest = cutLine(rest)
+		if string(bytes.TrimRight(line, " \t\r")) == delimiter {
+			if err := yaml.Unmarshal(front, v); err != nil {
+				return nil, err
+			}
+			return bytes.TrimLeft(rest, "\r\n"), nil
+		}
+		front = append(front, line...)
+		front = append(front, '\n')
+	}
+	return nil, ErrUnterminated
+}
+
+// Format writes v as front matter followed by body.
+func Format(v any, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString(delimiter + "\n\n")
+	buf.Write(body)
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+func cutLine(b []byte) (line, rest []byte) {
+	line, rest, _ = bytes.Cut(b, []byte("\n"))
+	return line, rest
+}
@@ -0,0 +1,84 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	MimeJPEG = "image/jpeg"
+	MimePNG  = "image/png"
+	MimeGIF  = "image/gif"
+	MimeWebP = "image/webp"
+
+	jpegQuality = 85
+)
+
+// extensions holds the accepted upload types.
+var extensions = map[string]string{
+	MimeJPEG: "jpg",
+	MimePNG:  "png",
+	MimeGIF:  "gif",
+	MimeWebP: "webp",
+}
+
+// Sniff detects the type from the content itself, ignoring the file name and
+// whatever Content-Type the client claimed.
+func Sniff(data []byte) (mime string, ok bool) {
+	mime = http.DetectContentType(data)
+	_, ok = extensions[mime]
+	return mime, ok
+}
+
+// Extension returns the file extension for an accepted type.
+func Extension(mime string) string {
+	return extensions[mime]
+}
+
+// DecodeConfig reads the dimensions without decoding the pixels, so oversized
+// images can be refused cheaply.
+func DecodeConfig(data []byte) (image.Config, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	return cfg, err
+}
+
+// Decode decodes the image. For animated GIFs this is the first frame.
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// Resize scales img to width, keeping the aspect ratio.
+func Resize(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	height := max(1, b.Dy()*width/b.Dx())
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// VariantType is the format resized copies of mime are stored in. Photos stay
+// JPEG; everything else may have transparency and becomes PNG.
+func VariantType(mime string) string {
+	if mime == MimeJPEG {
+		return MimeJPEG
+	}
+	return MimePNG
+}
+
+// Encode writes img as JPEG, or PNG for any other type.
+func Encode(w io.Writer, img image.Image, mime string) error {
+	if mime == MimeJPEG {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	return png.Encode(w, img)
+}
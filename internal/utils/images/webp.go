@@ -0,0 +1,46 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os/exec"
+)
+
+const webpQuality = 80
+
+// WebPEncoder converts images to WebP with the cwebp tool from libwebp, as
+// there is no WebP encoder in the standard library.
+type WebPEncoder struct {
+	path string
+}
+
+// NewWebPEncoder returns nil when the cwebp binary can't be found, in which
+// case callers skip WebP variants.
+func NewWebPEncoder(bin string) *WebPEncoder {
+	path, err := exec.LookPath(bin)
+	if err != nil {
+		return nil
+	}
+	return &WebPEncoder{path: path}
+}
+
+// Encode pipes img through cwebp as a lossless PNG and returns the WebP data.
+func (e *WebPEncoder) Encode(ctx context.Context, img image.Image) ([]byte, error) {
+	var in bytes.Buffer
+	if err := png.Encode(&in, img); err != nil {
+		return nil, err
+	}
+
+	var out, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.path, "-quiet", "-q", fmt.Sprint(webpQuality), "-o", "-", "--", "-")
+	cmd.Stdin = &in
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("cwebp: %w: %s", err, stderr.String())
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+// Package locale picks the languages a reader asked for among the ones the
+// blog publishes in.
+package locale
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Match returns the supported locale tag names, comparing the primary
+// language only, so "th-TH" matches "th". It returns an empty string when
+// none does.
+func Match(tag string, supported []string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "" || tag == "*" {
+		return ""
+	}
+	for _, s := range supported {
+		if strings.EqualFold(s, tag) {
+			return s
+		}
+	}
+	return ""
+}
+
+// Chain is the order to try locales in: lang, usually from ?lang=, then the
+// Accept-Language header by preference. Unsupported and repeated languages
+// are dropped. Callers fall back to the post's own language after it.
+func Chain(lang, acceptLanguage string, supported []string) []string {
+	var chain []string
+	add := func(tag string) {
+		if l := Match(tag, supported); l != "" && !slices.Contains(chain, l) {
+			chain = append(chain, l)
+		}
+	}
+
+	add(lang)
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		add(tag)
+	}
+	return chain
+}
+
+type weightedTag struct {
+	tag string
+	q   float64
+}
+
+// parseAcceptLanguage returns the tags of the header, most preferred first.
+// Tags with q=0 are refused by the reader and left out.
+func parseAcceptLanguage(header string) []string {
+	var tags []weightedTag
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = v
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weightedTag{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, t.tag)
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// headingIDs generates heading anchors that keep non-Latin letters, so Thai
+// headings get readable IDs instead of goldmark's generic "heading".
+type headingIDs struct {
+	seen map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{seen: make(map[string]bool)}
+}
+
+func (s *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	var (
+		sb   strings.Builder
+		dash bool
+	)
+	for _, r := range strings.ToLower(string(value)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r):
+			sb.WriteRune(r)
+			dash = false
+		case !dash && sb.Len() > 0:
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+
+	base := strings.TrimSuffix(sb.String(), "-")
+	if base == "" {
+		base = "heading"
+	}
+
+	id := base
+	for i := 1; s.seen[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	s.seen[id] = true
+	return []byte(id)
+}
+
+func (s *headingIDs) Put(value []byte) {
+	s.seen[string(value)] = true
+}
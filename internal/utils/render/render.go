@@ -0,0 +1,162 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const (
+	FormatMarkdown  = "markdown"
+	FormatHTML      = "html"
+	FormatPlaintext = "plaintext"
+)
+
+var ErrUnknownFormat = errors.New("unknown content format")
+
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+type Result struct {
+	HTML string
+	TOC  []Heading
+}
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		highlighting.NewHighlighting(
+			highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+		),
+	),
+	goldmark.WithParserOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithASTTransformers(util.Prioritized(&anchorTransformer{}, 100)),
+	),
+)
+
+// Render converts source to sanitized HTML according to format. Markdown and
+// HTML output both go through the same allowlist sanitizer.
+func Render(format, source string) (*Result, error) {
+	switch format {
+	case FormatMarkdown:
+		return renderMarkdown(source)
+	case FormatHTML:
+		return &Result{HTML: sanitizer.Sanitize(source)}, nil
+	case FormatPlaintext:
+		return &Result{HTML: renderPlaintext(source)}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func renderMarkdown(source string) (*Result, error) {
+	src := []byte(source)
+	pc := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := markdown.Parser().Parse(text.NewReader(src), parser.WithContext(pc))
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		HTML: sanitizer.Sanitize(buf.String()),
+		TOC:  tableOfContents(doc, src),
+	}, nil
+}
+
+func renderPlaintext(source string) string {
+	var sb strings.Builder
+	for _, para := range strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		sb.WriteString("<p>")
+		sb.WriteString(strings.ReplaceAll(html.EscapeString(para), "\n", "<br>"))
+		sb.WriteString("</p>\n")
+	}
+	return sb.String()
+}
+
+func tableOfContents(doc ast.Node, src []byte) []Heading {
+	var toc []Heading
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		id, _ := h.AttributeString("id")
+		idBytes, _ := id.([]byte)
+		toc = append(toc, Heading{
+			Level: h.Level,
+			ID:    string(idBytes),
+			Text:  headingText(h, src),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+func headingText(n ast.Node, src []byte) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if l, ok := c.(*ast.Link); ok && isAnchor(l) {
+			continue
+		}
+		if t, ok := c.(*ast.Text); ok {
+			sb.Write(t.Segment.Value(src))
+			continue
+		}
+		sb.WriteString(headingText(c, src))
+	}
+	return sb.String()
+}
+
+// anchorTransformer appends a "#" permalink to every heading.
+type anchorTransformer struct{}
+
+func (t *anchorTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		id, ok := h.AttributeString("id")
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+		idBytes, _ := id.([]byte)
+
+		link := ast.NewLink()
+		link.Destination = append([]byte("#"), idBytes...)
+		link.SetAttributeString("class", []byte("anchor"))
+		link.AppendChild(link, ast.NewString([]byte("#")))
+		h.AppendChild(h, ast.NewString([]byte(" ")))
+		h.AppendChild(h, link)
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+func isAnchor(l *ast.Link) bool {
+	class, ok := l.AttributeString("class")
+	if !ok {
+		return false
+	}
+	b, _ := class.([]byte)
+	return string(b) == "anchor"
+}
@@ -0,0 +1,198 @@
+package textstat
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// ExcerptLength is the maximum excerpt length in characters.
+	ExcerptLength = 200
+
+	wordsPerMinute = 200
+	// Chinese and Japanese reading speed is measured in characters.
+	charsPerMinute = 500
+	// Thai, Lao, Khmer and Myanmar don't put spaces between words, and
+	// splitting them properly needs a dictionary. Words are estimated from
+	// the number of base letters instead, ignoring vowel and tone marks.
+	lettersPerUnspacedWord = 3.5
+)
+
+type Stats struct {
+	Excerpt        string
+	WordCount      int
+	ReadingMinutes int
+}
+
+// FromHTML computes the stats of rendered, sanitized HTML. The excerpt skips
+// headings and code blocks; the word count includes them.
+func FromHTML(src string) Stats {
+	all, prose := extractText(src)
+	c := count(all)
+
+	minutes := 0
+	if c.total() > 0 {
+		m := float64(c.spaced+c.unspaced)/wordsPerMinute + float64(c.ideographs)/charsPerMinute
+		minutes = max(1, int(math.Ceil(m)))
+	}
+
+	return Stats{
+		Excerpt:        excerpt(prose, ExcerptLength),
+		WordCount:      c.total(),
+		ReadingMinutes: minutes,
+	}
+}
+
+// CountWords counts words in plain text. Every Chinese or Japanese character
+// counts as one word.
+func CountWords(s string) int {
+	return count(s).total()
+}
+
+type counts struct {
+	spaced     int
+	unspaced   int
+	ideographs int
+}
+
+func (c counts) total() int {
+	return c.spaced + c.unspaced + c.ideographs
+}
+
+func count(s string) counts {
+	var (
+		c       counts
+		inWord  bool
+		letters int
+	)
+
+	endUnspaced := func() {
+		if letters > 0 {
+			c.unspaced += int(math.Ceil(float64(letters) / lettersPerUnspacedWord))
+			letters = 0
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case isIdeograph(r):
+			endUnspaced()
+			inWord = false
+			c.ideographs++
+		case isUnspaced(r):
+			inWord = false
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				letters++
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			endUnspaced()
+			if !inWord {
+				c.spaced++
+				inWord = true
+			}
+		case inWord && isWordJoiner(r):
+			// don't, e-mail
+		default:
+			endUnspaced()
+			inWord = false
+		}
+	}
+	endUnspaced()
+	return c
+}
+
+func isIdeograph(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+func isUnspaced(r rune) bool {
+	return unicode.In(r, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
+
+func isWordJoiner(r rune) bool {
+	return r == '\'' || r == '’' || r == '-'
+}
+
+// excerpt cuts text at limit characters, on a space when there is one in
+// the second half, so it doesn't end in the middle of a word.
+func excerpt(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)[:limit]
+	cut := len(runes)
+	for i := len(runes) - 1; i > limit/2; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+// extractText returns all visible text, and the text outside headings and
+// code blocks, with whitespace collapsed.
+func extractText(src string) (all, prose string) {
+	var (
+		allB, proseB strings.Builder
+		skip         int
+	)
+
+	z := html.NewTokenizer(strings.NewReader(src))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return collapse(allB.String()), collapse(proseB.String())
+		case html.TextToken:
+			text := string(z.Text())
+			allB.WriteString(text)
+			if skip == 0 {
+				proseB.WriteString(text)
+			}
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			tag := atom.Lookup(name)
+			if blockTags[tag] {
+				// Blocks separate words even without whitespace.
+				allB.WriteByte(' ')
+				proseB.WriteByte(' ')
+			}
+			if skipTags[tag] {
+				switch z.Token().Type {
+				case html.StartTagToken:
+					skip++
+				case html.EndTagToken:
+					skip = max(0, skip-1)
+				}
+			}
+		}
+	}
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+var skipTags = map[atom.Atom]bool{
+	atom.H1: true, atom.H2: true, atom.H3: true,
+	atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Pre: true,
+}
+
+var blockTags = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Hr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true,
+	atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true,
+	atom.Blockquote: true, atom.Pre: true,
+	atom.Table: true, atom.Tr: true, atom.Td: true, atom.Th: true,
+	atom.Img: true, atom.Figure: true, atom.Figcaption: true,
+}
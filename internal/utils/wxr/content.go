@@ -0,0 +1,78 @@
+package wxr
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// blockComment matches the <!-- wp:... --> delimiters of the block editor.
+	blockComment = regexp.MustCompile(`<!--\s*/?wp:[^>]*-->`)
+	// wrapperShortcode matches the shortcodes that only wrap content, such
+	// as [caption], keeping what they wrap.
+	wrapperShortcode = regexp.MustCompile(`\[/?(caption|wp_caption)[^\]]*\]`)
+	embedShortcode   = regexp.MustCompile(`\[embed[^\]]*\]\s*(\S+?)\s*\[/embed\]`)
+	paragraphBreak   = regexp.MustCompile(`\n\s*\n`)
+	blockStart       = regexp.MustCompile(`(?i)^<(p|div|h[1-6]|ul|ol|li|dl|blockquote|pre|table|figure|hr|address|section|article|aside|header|footer|details|form|iframe|video|audio)[\s>/]`)
+	preTag           = regexp.MustCompile(`(?i)<(/?)pre[\s>]`)
+)
+
+// ContentHTML turns post content as WordPress stores it into plain HTML.
+// Block editor comments and wrapper shortcodes are dropped, embeds become
+// links, and the blank lines the classic editor uses between paragraphs
+// become <p> elements, as WordPress does when it displays a post.
+func ContentHTML(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = blockComment.ReplaceAllString(content, "")
+	content = wrapperShortcode.ReplaceAllString(content, "")
+	content = embedShortcode.ReplaceAllString(content, `<a href="$1">$1</a>`)
+	return autop(content)
+}
+
+func autop(content string) string {
+	var (
+		sb    strings.Builder
+		inPre int
+	)
+	for _, chunk := range paragraphBreak.Split(content, -1) {
+		chunk = strings.TrimSpace(chunk)
+		if chunk == "" {
+			continue
+		}
+
+		// Blank lines within <pre> are part of the code.
+		if inPre > 0 || blockStart.MatchString(chunk) {
+			sb.WriteString(chunk)
+		} else {
+			sb.WriteString("<p>")
+			sb.WriteString(strings.ReplaceAll(chunk, "\n", "<br>\n"))
+			sb.WriteString("</p>")
+		}
+		for _, m := range preTag.FindAllStringSubmatch(chunk, -1) {
+			if m[1] == "" {
+				inPre++
+			} else if inPre > 0 {
+				inPre--
+			}
+		}
+		sb.WriteString("\n\n")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// CommentText strips the markup WordPress allows in comments, since comments
+// here are plain text.
+func CommentText(content string) string {
+	var sb strings.Builder
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(sb.String())
+		case html.TextToken:
+			sb.Write(z.Text())
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Package wxr reads WordPress eXtended RSS exports, the XML files written by
+// Tools > Export in the WordPress admin.
+package wxr
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02 15:04:05"
+
+var ErrNotWXR = errors.New("file is not a WordPress export")
+
+// Export is the channel of a WXR file. The wp: namespace changes with every
+// WXR version, so elements are matched by their local name; only
+// <content:encoded> needs its namespace to tell it from <excerpt:encoded>.
+type Export struct {
+	Title string `xml:"title"`
+	// SiteURL is the address of the blog the export came from.
+	SiteURL    string     `xml:"base_site_url"`
+	BlogURL    string     `xml:"base_blog_url"`
+	Version    string     `xml:"wxr_version"`
+	Authors    []Author   `xml:"author"`
+	Categories []Category `xml:"category"`
+	Tags       []Tag      `xml:"tag"`
+	Items      []Item     `xml:"item"`
+}
+
+type Author struct {
+	ID          string `xml:"author_id"`
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+	FirstName   string `xml:"author_first_name"`
+	LastName    string `xml:"author_last_name"`
+}
+
+type Category struct {
+	Slug        string `xml:"category_nicename"`
+	Name        string `xml:"cat_name"`
+	Description string `xml:"category_description"`
+}
+
+type Tag struct {
+	Slug string `xml:"tag_slug"`
+	Name string `xml:"tag_name"`
+}
+
+// Item is a post, page, attachment or any other post type.
+type Item struct {
+	Title    string    `xml:"title"`
+	Link     string    `xml:"link"`
+	PubDate  string    `xml:"pubDate"`
+	Creator  string    `xml:"creator"`
+	GUID     string    `xml:"guid"`
+	Content  string    `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	ID       string    `xml:"post_id"`
+	Date     string    `xml:"post_date"`
+	DateGMT  string    `xml:"post_date_gmt"`
+	Name     string    `xml:"post_name"`
+	Status   string    `xml:"status"`
+	Type     string    `xml:"post_type"`
+	Terms    []Term    `xml:"category"`
+	Comments []Comment `xml:"comment"`
+}
+
+// Term is a category or tag of an item; Domain is "category" or "post_tag".
+type Term struct {
+	Domain string `xml:"domain,attr"`
+	Slug   string `xml:"nicename,attr"`
+	Name   string `xml:",chardata"`
+}
+
+type Comment struct {
+	ID          string `xml:"comment_id"`
+	Author      string `xml:"comment_author"`
+	AuthorEmail string `xml:"comment_author_email"`
+	Date        string `xml:"comment_date"`
+	DateGMT     string `xml:"comment_date_gmt"`
+	Content     string `xml:"comment_content"`
+	// Approved is "1" for published comments, "0" for pending ones, or
+	// "spam" and "trash".
+	Approved string `xml:"comment_approved"`
+	Type     string `xml:"comment_type"`
+	Parent   string `xml:"comment_parent"`
+	UserID   string `xml:"comment_user_id"`
+}
+
+type document struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel Export   `xml:"channel"`
+}
+
+// Parse reads a WXR file.
+func Parse(r io.Reader) (*Export, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.As(err, new(xml.UnmarshalError)) {
+			return nil, ErrNotWXR
+		}
+		return nil, err
+	}
+	if doc.Channel.Version == "" {
+		return nil, ErrNotWXR
+	}
+	return &doc.Channel, nil
+}
+
+// Site is the address the export came from, used to tell the imports of
+// different blogs apart.
+func (e *Export) Site() string {
+	site := e.SiteURL
+	if site == "" {
+		site = e.BlogURL
+	}
+	return strings.TrimRight(strings.TrimSpace(site), "/")
+}
+
+// Published is when the item was published, or the zero time for drafts,
+// which WordPress exports with a zero date.
+func (it *Item) Published() time.Time {
+	if t, ok := parseGMT(it.DateGMT); ok {
+		return t
+	}
+	if t, err := time.Parse(time.RFC1123Z, strings.TrimSpace(it.PubDate)); err == nil && t.Unix() > 0 {
+		return t
+	}
+	// post_date is in the blog's time zone, which the export doesn't name.
+	if t, ok := parseGMT(it.Date); ok {
+		return t
+	}
+	return time.Time{}
+}
+
+// Slug is the unescaped post name. WordPress stores names in other scripts
+// percent-encoded.
+func (it *Item) Slug() string {
+	if name, err := url.PathUnescape(it.Name); err == nil {
+		return name
+	}
+	return it.Name
+}
+
+// TermNames returns the names of the item's terms in domain.
+func (it *Item) TermNames(domain string) []string {
+	var names []string
+	for _, term := range it.Terms {
+		if term.Domain == domain && strings.TrimSpace(term.Name) != "" {
+			names = append(names, strings.TrimSpace(term.Name))
+		}
+	}
+	return names
+}
+
+// Permalinks are the paths the item was reachable at on the old blog: its
+// pretty permalink and the ?p= form every WordPress post answers to.
+func (it *Item) Permalinks() []string {
+	var paths []string
+	if u, err := url.Parse(strings.TrimSpace(it.Link)); err == nil && u.Path != "" {
+		if p := NormalizePath(u.Path); p != "/" {
+			paths = append(paths, p)
+		}
+	}
+	if it.ID != "" {
+		paths = append(paths, "/?p="+it.ID)
+	}
+	return paths
+}
+
+func (c *Comment) Created() time.Time {
+	if t, ok := parseGMT(c.DateGMT); ok {
+		return t
+	}
+	if t, ok := parseGMT(c.Date); ok {
+		return t
+	}
+	return time.Time{}
+}
+
+// NormalizePath drops the trailing slash, since WordPress answers with and
+// without it.
+func NormalizePath(p string) string {
+	return "/" + strings.Trim(p, "/")
+}
+
+func parseGMT(s string) (time.Time, bool) {
+	t, err := time.Parse(dateLayout, strings.TrimSpace(s))
+	if err != nil || t.Year() <= 1 {
+		return time.Time{}, false
+	}
+	return t, true
+}